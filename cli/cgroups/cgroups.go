@@ -0,0 +1,401 @@
+package cgroups
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// mountPoint is a mount point of the cgroup v2 hierarchy.
+	mountPoint = "/sys/fs/cgroup"
+	// defaultRootName is a name of the default parent cgroup of instances
+	// cgroups.
+	defaultRootName = "tt"
+	// cpuPeriod is a period of the CPU bandwidth limit in microseconds.
+	cpuPeriod = 100000
+	// maxIOWeight is a maximum value of the IO weight.
+	maxIOWeight = 10000
+	// unlimited is a value of unlimited cgroup settings.
+	unlimited = "max"
+)
+
+// ErrDelegationRequired is returned if the parent of the root cgroup is not
+// delegated to the current user with the required controllers.
+var ErrDelegationRequired = errors.New("cgroup delegation required")
+
+// procDir is a mount point of the proc filesystem.
+var procDir = "/proc"
+
+// ProcessCgroup returns a path of the cgroup v2 of the process.
+func ProcessCgroup(pid int) (string, error) {
+	data, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read cgroup of process %d: %w", pid, err)
+	}
+	for line := range strings.Lines(string(data)) {
+		// The cgroup v2 entry has the zero hierarchy ID and no controllers.
+		if path, found := strings.CutPrefix(strings.TrimSpace(line), "0::"); found {
+			return filepath.Join(mountPoint, path), nil
+		}
+	}
+	return "", fmt.Errorf("process %d is not in a cgroup v2 hierarchy", pid)
+}
+
+// DefaultRoot returns a default parent cgroup of instances cgroups started by
+// the process. It is a cgroup next to the cgroup of the process: the cgroup
+// of the process can not enable controllers for children while it has
+// processes, but its parent belongs to the sub-tree delegated to the user by
+// a service manager. A cgroup in the root of the hierarchy is used if the
+// process is in the root cgroup or its cgroup is unknown.
+func DefaultRoot(pid int) string {
+	parent := mountPoint
+	if cgroup, err := ProcessCgroup(pid); err == nil && cgroup != mountPoint {
+		parent = filepath.Dir(cgroup)
+	}
+	return filepath.Join(parent, defaultRootName)
+}
+
+// Limits are resource limits of an instance cgroup.
+type Limits struct {
+	// MemoryMax is a memory usage hard limit in bytes. Zero means no limit.
+	MemoryMax int64
+	// CPUQuota is a CPU time limit in percents of one CPU. Zero means no limit.
+	CPUQuota int
+	// IOWeight is a relative IO weight from 1 to 10000. Zero means the
+	// default weight.
+	IOWeight int
+}
+
+// IsSet returns true if any of the limits is set.
+func (limits Limits) IsSet() bool {
+	return limits.MemoryMax > 0 || limits.CPUQuota > 0 || limits.IOWeight > 0
+}
+
+// Validate checks the limits values.
+func (limits Limits) Validate() error {
+	if limits.MemoryMax < 0 {
+		return fmt.Errorf("memory limit must not be negative")
+	}
+	if limits.CPUQuota < 0 {
+		return fmt.Errorf("CPU quota must not be negative")
+	}
+	if limits.IOWeight < 0 || limits.IOWeight > maxIOWeight {
+		return fmt.Errorf("IO weight must be in range from 1 to %d", maxIOWeight)
+	}
+	return nil
+}
+
+// controllers returns cgroup controllers required for the limits.
+func (limits Limits) controllers() []string {
+	var controllers []string
+	if limits.CPUQuota > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if limits.IOWeight > 0 {
+		controllers = append(controllers, "io")
+	}
+	if limits.MemoryMax > 0 {
+		controllers = append(controllers, "memory")
+	}
+	return controllers
+}
+
+// ParseMemorySize parses a memory size in bytes with an optional K, M, G or
+// T binary suffix, like `512M`.
+func ParseMemorySize(str string) (int64, error) {
+	num := strings.TrimSpace(str)
+	multiplier := int64(1)
+	if num != "" {
+		suffixes := "KMGT"
+		if idx := strings.IndexByte(suffixes, strings.ToUpper(num)[len(num)-1]); idx != -1 {
+			multiplier = 1 << (10 * (idx + 1))
+			num = num[:len(num)-1]
+		}
+	}
+	size, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid memory size %q: expected a number of bytes "+
+			"with an optional K, M, G or T suffix", str)
+	}
+	return size * multiplier, nil
+}
+
+// readControllers reads space-separated controllers from the file.
+func readControllers(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// checkDelegation checks that the controllers are enabled for children of
+// the cgroup, so a child cgroup could be created and configured without
+// modifications of the cgroup.
+func checkDelegation(dir string, controllers []string) error {
+	enabled, err := readControllers(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("failed to read enabled controllers of %q: %w", dir, err)
+	}
+	var missing []string
+	for _, controller := range controllers {
+		if !slices.Contains(enabled, controller) {
+			missing = append(missing, controller)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("%w: %s controllers are not enabled for children of %q, "+
+			"delegate it to the user or set env.resource_limits.cgroup_root",
+			ErrDelegationRequired, strings.Join(missing, ", "), dir)
+	}
+	return nil
+}
+
+// enableControllers enables the controllers for children of the cgroup.
+func enableControllers(dir string, controllers []string) error {
+	subtreeControl := filepath.Join(dir, "cgroup.subtree_control")
+	enabled, err := readControllers(subtreeControl)
+	if err != nil {
+		return fmt.Errorf("failed to read enabled controllers of %q: %w", dir, err)
+	}
+	var missing []string
+	for _, controller := range controllers {
+		if !slices.Contains(enabled, controller) {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	err = os.WriteFile(subtreeControl, []byte(strings.Join(missing, " ")), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to enable %s controllers in %q: %w",
+			strings.Join(controllers, ", "), dir, err)
+	}
+	return nil
+}
+
+// writeLimits writes the limits that are set into the cgroup.
+func writeLimits(path string, limits Limits) error {
+	settings := []struct {
+		file  string
+		value string
+		set   bool
+	}{
+		{"memory.max", strconv.FormatInt(limits.MemoryMax, 10), limits.MemoryMax > 0},
+		{
+			"cpu.max",
+			fmt.Sprintf("%d %d", limits.CPUQuota*cpuPeriod/100, cpuPeriod),
+			limits.CPUQuota > 0,
+		},
+		{"io.weight", fmt.Sprintf("default %d", limits.IOWeight), limits.IOWeight > 0},
+	}
+	for _, setting := range settings {
+		if !setting.set {
+			continue
+		}
+		file := filepath.Join(path, setting.file)
+		if err := os.WriteFile(file, []byte(setting.value), 0o644); err != nil {
+			return fmt.Errorf("failed to set %s: %w", setting.file, err)
+		}
+	}
+	return nil
+}
+
+// Create creates the cgroup with the limits. The path must be inside the root
+// cgroup. The required controllers must be enabled for children of the parent
+// of the root cgroup, they are enabled from the root cgroup down to the parent
+// of the created cgroup. Cgroups above the root cgroup are not modified.
+func Create(root, path string, limits Limits) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("cgroup %q is not inside %q", path, root)
+	}
+
+	controllers := limits.controllers()
+	if err := checkDelegation(filepath.Dir(root), controllers); err != nil {
+		return err
+	}
+	// Intermediate cgroups have no processes, so controllers can be enabled.
+	dirs := []string{root}
+	if parent := filepath.Dir(rel); parent != "." {
+		for _, name := range strings.Split(parent, string(filepath.Separator)) {
+			dirs = append(dirs, filepath.Join(dirs[len(dirs)-1], name))
+		}
+	}
+	for _, dir := range dirs {
+		if err := os.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+			if errors.Is(err, fs.ErrPermission) {
+				return fmt.Errorf("%w: failed to create cgroup: %w", ErrDelegationRequired, err)
+			}
+			return fmt.Errorf("failed to create cgroup: %w", err)
+		}
+		if err := enableControllers(dir, controllers); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(path, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	return writeLimits(path, limits)
+}
+
+// AddProcess moves the process into the cgroup.
+func AddProcess(path string, pid int) error {
+	err := os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to move process %d into cgroup %q: %w", pid, path, err)
+	}
+	return nil
+}
+
+// Remove removes the cgroup. The cgroup must have no processes.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Usage is a current resource usage of a cgroup.
+type Usage struct {
+	// MemoryCurrent is a current memory usage in bytes.
+	MemoryCurrent int64 `json:"memory_current" yaml:"memory_current"`
+	// MemoryMax is a memory limit in bytes. Zero means no limit.
+	MemoryMax int64 `json:"memory_max,omitempty" yaml:"memory_max,omitempty"`
+	// CPUUsageUsec is a total CPU time consumed by the cgroup in microseconds.
+	CPUUsageUsec int64 `json:"cpu_usage_usec" yaml:"cpu_usage_usec"`
+	// CPUQuota is a CPU time limit in percents of one CPU. Zero means no limit.
+	CPUQuota int `json:"cpu_quota,omitempty" yaml:"cpu_quota,omitempty"`
+	// IOWeight is an IO weight of the cgroup. Zero means the IO controller
+	// is not enabled.
+	IOWeight int `json:"io_weight,omitempty" yaml:"io_weight,omitempty"`
+}
+
+// readValue reads a value of the cgroup file. An empty string is returned
+// if the file does not exist.
+func readValue(path, file string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, file))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+// parseInt parses an integer value of the cgroup file.
+func parseInt(file, value string) (int64, error) {
+	num, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return num, nil
+}
+
+// ReadUsage reads a current resource usage of the cgroup.
+func ReadUsage(path string) (Usage, error) {
+	var usage Usage
+	if _, err := os.Stat(path); err != nil {
+		return usage, fmt.Errorf("failed to read cgroup: %w", err)
+	}
+
+	value, err := readValue(path, "memory.current")
+	if err == nil && value != "" {
+		usage.MemoryCurrent, err = parseInt("memory.current", value)
+	}
+	if err != nil {
+		return usage, err
+	}
+
+	value, err = readValue(path, "memory.max")
+	if err == nil && value != "" && value != unlimited {
+		usage.MemoryMax, err = parseInt("memory.max", value)
+	}
+	if err != nil {
+		return usage, err
+	}
+
+	value, err = readValue(path, "cpu.stat")
+	if err != nil {
+		return usage, err
+	}
+	for line := range strings.Lines(value) {
+		key, usec, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found || key != "usage_usec" {
+			continue
+		}
+		if usage.CPUUsageUsec, err = parseInt("cpu.stat", usec); err != nil {
+			return usage, err
+		}
+	}
+
+	value, err = readValue(path, "cpu.max")
+	if err != nil {
+		return usage, err
+	}
+	if quota, period, found := strings.Cut(value, " "); found && quota != unlimited {
+		quotaNum, err := parseInt("cpu.max", quota)
+		if err != nil {
+			return usage, err
+		}
+		periodNum, err := parseInt("cpu.max", period)
+		if err != nil {
+			return usage, err
+		}
+		if periodNum > 0 {
+			usage.CPUQuota = int(quotaNum * 100 / periodNum)
+		}
+	}
+
+	value, err = readValue(path, "io.weight")
+	if err != nil {
+		return usage, err
+	}
+	if _, weight, found := strings.Cut(value, "default "); found && weight != "" {
+		weightNum, err := parseInt("io.weight", strings.Fields(weight)[0])
+		if err != nil {
+			return usage, err
+		}
+		usage.IOWeight = int(weightNum)
+	}
+	return usage, nil
+}
+
+// formatMemorySize formats the memory size with a binary suffix.
+func formatMemorySize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"K", "M", "G", "T"} {
+		value /= unit
+		if value < unit || suffix == "T" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	return ""
+}
+
+// String returns a human-readable resource usage.
+func (usage Usage) String() string {
+	memory := formatMemorySize(usage.MemoryCurrent)
+	if usage.MemoryMax > 0 {
+		memory += " / " + formatMemorySize(usage.MemoryMax)
+	}
+	cpu := (time.Duration(usage.CPUUsageUsec) * time.Microsecond).Round(time.Millisecond).String()
+	if usage.CPUQuota > 0 {
+		cpu += fmt.Sprintf(" (quota %d%%)", usage.CPUQuota)
+	}
+	parts := []string{"memory " + memory, "CPU time " + cpu}
+	if usage.IOWeight > 0 {
+		parts = append(parts, fmt.Sprintf("IO weight %d", usage.IOWeight))
+	}
+	return strings.Join(parts, ", ")
+}
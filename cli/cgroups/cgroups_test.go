@@ -0,0 +1,185 @@
+package cgroups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMemorySize(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected int64
+		errMsg   string
+	}{
+		{"1024", 1024, ""},
+		{"512K", 512 << 10, ""},
+		{"512m", 512 << 20, ""},
+		{" 2G ", 2 << 30, ""},
+		{"1T", 1 << 40, ""},
+		{"", 0, `invalid memory size ""`},
+		{"1.5G", 0, `invalid memory size "1.5G"`},
+		{"-1M", 0, `invalid memory size "-1M"`},
+		{"10X", 0, `invalid memory size "10X"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			size, err := ParseMemorySize(tt.str)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	assert.NoError(t, Limits{}.Validate())
+	assert.NoError(t, Limits{MemoryMax: 1 << 30, CPUQuota: 150, IOWeight: 10000}.Validate())
+	assert.ErrorContains(t, Limits{MemoryMax: -1}.Validate(), "memory limit")
+	assert.ErrorContains(t, Limits{CPUQuota: -1}.Validate(), "CPU quota")
+	assert.ErrorContains(t, Limits{IOWeight: 10001}.Validate(), "IO weight")
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCreate(t *testing.T) {
+	// The parent of the root cgroup is delegated with the controllers.
+	mount := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(mount, "cgroup.subtree_control"),
+		[]byte("cpu io memory pids\n"), 0o644))
+
+	root := filepath.Join(mount, "tt")
+	path := filepath.Join(root, "app", "master")
+	limits := Limits{MemoryMax: 512 << 20, CPUQuota: 150, IOWeight: 200}
+	require.NoError(t, Create(root, path, limits))
+
+	// Cgroups above the root are not modified.
+	assert.Equal(t, "cpu io memory pids\n",
+		readFile(t, filepath.Join(mount, "cgroup.subtree_control")))
+	assert.Equal(t, "+cpu +io +memory", readFile(t, filepath.Join(root, "cgroup.subtree_control")))
+	assert.Equal(t, "+cpu +io +memory",
+		readFile(t, filepath.Join(root, "app", "cgroup.subtree_control")))
+	assert.NoFileExists(t, filepath.Join(path, "cgroup.subtree_control"))
+
+	assert.Equal(t, "536870912", readFile(t, filepath.Join(path, "memory.max")))
+	assert.Equal(t, "150000 100000", readFile(t, filepath.Join(path, "cpu.max")))
+	assert.Equal(t, "default 200", readFile(t, filepath.Join(path, "io.weight")))
+
+	// Re-creation of the existing cgroup is allowed.
+	require.NoError(t, Create(root, path, Limits{MemoryMax: 1 << 20}))
+	assert.Equal(t, "1048576", readFile(t, filepath.Join(path, "memory.max")))
+
+	require.NoError(t, AddProcess(path, 42))
+	assert.Equal(t, "42", readFile(t, filepath.Join(path, "cgroup.procs")))
+
+	assert.ErrorContains(t, Create(root, root, limits), "is not inside")
+	assert.ErrorContains(t, Create(root, filepath.Join(mount, "other"), limits),
+		"is not inside")
+}
+
+func TestCreate_delegationRequired(t *testing.T) {
+	mount := t.TempDir()
+	subtreeControl := filepath.Join(mount, "cgroup.subtree_control")
+	require.NoError(t, os.WriteFile(subtreeControl, []byte("memory\n"), 0o644))
+
+	root := filepath.Join(mount, "tt")
+	err := Create(root, filepath.Join(root, "app", "master"),
+		Limits{MemoryMax: 512 << 20, CPUQuota: 150})
+	require.ErrorIs(t, err, ErrDelegationRequired)
+	assert.ErrorContains(t, err, "cpu controllers are not enabled for children of")
+	assert.Equal(t, "memory\n", readFile(t, subtreeControl))
+	assert.NoDirExists(t, root)
+}
+
+func TestDefaultRoot(t *testing.T) {
+	origProcDir := procDir
+	defer func() { procDir = origProcDir }()
+	procDir = t.TempDir()
+
+	writeCgroup := func(pid, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(procDir, pid), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(procDir, pid, "cgroup"),
+			[]byte(content), 0o644))
+	}
+	writeCgroup("1", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/tt.scope\n")
+	writeCgroup("2", "4:memory:/\n0::/\n")
+	writeCgroup("3", "4:memory:/user.slice\n")
+
+	cgroup, err := ProcessCgroup(1)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"/sys/fs/cgroup/user.slice/user-1000.slice/user@1000.service/app.slice/tt.scope",
+		cgroup)
+	assert.Equal(t, "/sys/fs/cgroup/user.slice/user-1000.slice/user@1000.service/app.slice/tt",
+		DefaultRoot(1))
+
+	// The process is in the root cgroup.
+	assert.Equal(t, "/sys/fs/cgroup/tt", DefaultRoot(2))
+
+	_, err = ProcessCgroup(3)
+	assert.EqualError(t, err, "process 3 is not in a cgroup v2 hierarchy")
+	assert.Equal(t, "/sys/fs/cgroup/tt", DefaultRoot(3))
+
+	_, err = ProcessCgroup(4)
+	assert.ErrorContains(t, err, "failed to read cgroup of process 4")
+}
+
+func TestRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inst")
+	require.NoError(t, os.Mkdir(path, 0o755))
+	require.NoError(t, Remove(path))
+	assert.NoDirExists(t, path)
+	require.NoError(t, Remove(path))
+}
+
+func TestReadUsage(t *testing.T) {
+	path := t.TempDir()
+	for file, content := range map[string]string{
+		"memory.current": "125829120\n",
+		"memory.max":     "536870912\n",
+		"cpu.stat":       "usage_usec 12345678\nuser_usec 10000000\nsystem_usec 2345678\n",
+		"cpu.max":        "150000 100000\n",
+		"io.weight":      "default 200\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), []byte(content), 0o644))
+	}
+
+	usage, err := ReadUsage(path)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{
+		MemoryCurrent: 125829120,
+		MemoryMax:     536870912,
+		CPUUsageUsec:  12345678,
+		CPUQuota:      150,
+		IOWeight:      200,
+	}, usage)
+	assert.Equal(t, "memory 120.0M / 512.0M, CPU time 12.346s (quota 150%), IO weight 200",
+		usage.String())
+
+	// Limits are not set.
+	for file, content := range map[string]string{
+		"memory.max": "max\n",
+		"cpu.max":    "max 100000\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), []byte(content), 0o644))
+	}
+	require.NoError(t, os.Remove(filepath.Join(path, "io.weight")))
+	usage, err = ReadUsage(path)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{MemoryCurrent: 125829120, CPUUsageUsec: 12345678}, usage)
+	assert.Equal(t, "memory 120.0M, CPU time 12.346s", usage.String())
+
+	_, err = ReadUsage(filepath.Join(path, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
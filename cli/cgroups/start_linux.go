@@ -0,0 +1,69 @@
+//go:build linux
+
+package cgroups
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneIntoCgroupSupported checks whether the kernel supports starting
+// processes inside a cgroup with clone3(CLONE_INTO_CGROUP). It is available
+// since Linux 5.7.
+var cloneIntoCgroupSupported = sync.OnceValue(func() bool {
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		return false
+	}
+	major, minor, ok := parseKernelVersion(unix.ByteSliceToString(uname.Release[:]))
+	return ok && (major > 5 || major == 5 && minor >= 7)
+})
+
+// parseKernelVersion parses major and minor numbers of a kernel release like
+// `6.8.0-45-generic`.
+func parseKernelVersion(release string) (int, int, bool) {
+	majorStr, rest, found := strings.Cut(release, ".")
+	if !found {
+		return 0, 0, false
+	}
+	minorStr := rest
+	if end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		minorStr = rest[:end]
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// PrepareStart configures the command to start the process inside the
+// cgroup. It returns false if the kernel does not support it, so the process
+// must be moved into the cgroup with AddProcess after the start. The returned
+// function releases the cgroup descriptor and must be called after the start.
+func PrepareStart(cmd *exec.Cmd, path string) (func(), bool, error) {
+	if !cloneIntoCgroupSupported() {
+		return func() {}, false, nil
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open cgroup %q: %w", path, err)
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { dir.Close() }, true, nil
+}
@@ -0,0 +1,62 @@
+//go:build linux
+
+package cgroups
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKernelVersion(t *testing.T) {
+	tests := []struct {
+		release string
+		major   int
+		minor   int
+		ok      bool
+	}{
+		{"6.8.0-45-generic", 6, 8, true},
+		{"5.7", 5, 7, true},
+		{"5.15.167.4-microsoft-standard-WSL2", 5, 15, true},
+		{"4.18.0-553.el8_10.x86_64", 4, 18, true},
+		{"6", 0, 0, false},
+		{"linux", 0, 0, false},
+		{"6.rc1", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.release, func(t *testing.T) {
+			major, minor, ok := parseKernelVersion(tt.release)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.major, major)
+			assert.Equal(t, tt.minor, minor)
+		})
+	}
+}
+
+func TestPrepareStart(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command("true")
+	release, ok, err := PrepareStart(cmd, dir)
+	require.NoError(t, err)
+	defer release()
+
+	assert.Equal(t, cloneIntoCgroupSupported(), ok)
+	if ok {
+		require.NotNil(t, cmd.SysProcAttr)
+		assert.True(t, cmd.SysProcAttr.UseCgroupFD)
+		assert.Positive(t, cmd.SysProcAttr.CgroupFD)
+	} else {
+		assert.Nil(t, cmd.SysProcAttr)
+	}
+}
+
+func TestPrepareStart_missing(t *testing.T) {
+	if !cloneIntoCgroupSupported() {
+		t.Skip("starting processes inside a cgroup is not supported")
+	}
+	_, _, err := PrepareStart(exec.Command("true"), "/nonexistent/cgroup")
+	assert.ErrorContains(t, err, `failed to open cgroup "/nonexistent/cgroup"`)
+}
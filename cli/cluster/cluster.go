@@ -91,7 +91,16 @@ func collectTarantoolConfig(collectors libcluster.CollectorFactory,
 	var opts []tarantoolOpts
 	for _, endpoint := range tarantoolConfig.Endpoints {
 		var network, address string
-		if !connect.IsBaseURI(endpoint.Uri) {
+		var sshOpts dial.SshOpts
+		if strings.HasPrefix(endpoint.Uri, "ssh://") {
+			uriOpts, err := connect.CreateUriOpts(endpoint.Uri)
+			if err != nil {
+				return nil, fmt.Errorf("invalid config storage endpoint %q: %w",
+					endpoint.Uri, err)
+			}
+			network, address = "tcp", uriOpts.Host
+			sshOpts = dial.SshOpts(uriOpts.Ssh)
+		} else if !connect.IsBaseURI(endpoint.Uri) {
 			network = "tcp"
 			address = endpoint.Uri
 		} else {
@@ -110,6 +119,7 @@ func collectTarantoolConfig(collectors libcluster.CollectorFactory,
 			SslPassword:     endpoint.Params.SslPassword,
 			SslPasswordFile: endpoint.Params.SslPasswordFile,
 			Transport:       endpoint.Params.Transport,
+			Ssh:             sshOpts,
 		})
 		if err != nil {
 			return nil, err
@@ -178,15 +188,6 @@ func GetClusterConfig(collectors libcluster.CollectorFactory,
 		return ret, fmt.Errorf("a configuration file must be set")
 	}
 
-	config := libcluster.NewConfig()
-
-	mainEnvConfig, err := mainEnvCollector.Collect()
-	if err != nil {
-		fmtErr := "failed to collect a config from environment variables: %w"
-		return ret, fmt.Errorf(fmtErr, err)
-	}
-	config.Merge(mainEnvConfig)
-
 	collector, err := collectors.NewFile(path)
 	if err != nil {
 		return ret, fmt.Errorf("failed to create a file collector: %w", err)
@@ -197,6 +198,43 @@ func GetClusterConfig(collectors libcluster.CollectorFactory,
 		fmtErr := "unable to get cluster config from %q: %w"
 		return ret, fmt.Errorf(fmtErr, path, err)
 	}
+	return makeClusterConfig(collectors, fileConfig)
+}
+
+// GetRenderedClusterConfig returns a cluster configuration rendered from
+// a path to a config file with the render options. It uses the rendered
+// configuration, etcd and default environment variables as sources as
+// GetClusterConfig does.
+func GetRenderedClusterConfig(collectors libcluster.DataCollectorFactory,
+	path string, opts RenderOpts,
+) (libcluster.ClusterConfig, error) {
+	ret := libcluster.ClusterConfig{}
+	if path == "" {
+		return ret, fmt.Errorf("a configuration file must be set")
+	}
+
+	_, fileConfig, err := RenderConfig(collectors, path, opts)
+	if err != nil {
+		return ret, err
+	}
+	return makeClusterConfig(libcluster.NewCollectorFactory(collectors), fileConfig)
+}
+
+// makeClusterConfig makes a cluster configuration from a file configuration
+// merged with environment variables, etcd and tarantool config storage
+// configurations.
+func makeClusterConfig(collectors libcluster.CollectorFactory,
+	fileConfig *libcluster.Config,
+) (libcluster.ClusterConfig, error) {
+	ret := libcluster.ClusterConfig{}
+	config := libcluster.NewConfig()
+
+	mainEnvConfig, err := mainEnvCollector.Collect()
+	if err != nil {
+		fmtErr := "failed to collect a config from environment variables: %w"
+		return ret, fmt.Errorf(fmtErr, err)
+	}
+	config.Merge(mainEnvConfig)
 	config.Merge(fileConfig)
 
 	clusterConfig, err := libcluster.MakeClusterConfig(config)
@@ -192,7 +192,31 @@ func createPublisherAndCollector(
 		return nil
 	}
 
-	if err := libcluster.DoOnStorage(connOpts, opts, tarantoolFunc, etcdFunc); err != nil {
+	consulFunc := func(client *libcluster.ConsulClient) error {
+		if publishers != nil {
+			publisher, err = publishers.NewConsul(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create consul publisher: %w", err)
+			}
+		}
+		if collectors != nil {
+			collector, err = collectors.NewConsul(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create consul collector: %w", err)
+			}
+		}
+		closeFunc = func() { client.Close() }
+		return nil
+	}
+
+	err = libcluster.DoOnStorageFuncs(connOpts, opts, libcluster.StorageFuncs{
+		Tarantool: tarantoolFunc,
+		Etcd:      etcdFunc,
+		Consul:    consulFunc,
+	})
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
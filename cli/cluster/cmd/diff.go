@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+	"github.com/tarantool/tt/lib/connect"
+)
+
+const (
+	// DiffFormatText is a human-readable format of a difference.
+	DiffFormatText = "text"
+	// DiffFormatJSON is a JSON format of a difference.
+	DiffFormatJSON = "json"
+)
+
+// DiffCtx contains information about cluster diff command execution context.
+type DiffCtx struct {
+	// Username defines a username for connection.
+	Username string
+	// Password defines a password for connection.
+	Password string
+	// Collectors defines a used collectors factory.
+	Collectors libcluster.CollectorFactory
+	// Format defines an output format: text or json.
+	Format string
+	// Writer is a writer to print the difference to.
+	Writer io.Writer
+}
+
+// diffMarks are marks of difference kinds in the text format.
+var diffMarks = map[libcluster.DiffKind]string{
+	libcluster.DiffAdded:   "+",
+	libcluster.DiffRemoved: "-",
+	libcluster.DiffChanged: "~",
+}
+
+// collectCurrent collects a current configuration to compare with. A missing
+// configuration is treated as an empty one.
+func collectCurrent(collector libcluster.Collector) (*libcluster.Config, error) {
+	config, err := collector.Collect()
+	var emptyErr libcluster.CollectEmptyError
+	if errors.As(err, &emptyErr) || errors.Is(err, fs.ErrNotExist) {
+		return libcluster.NewConfig(), nil
+	}
+	return config, err
+}
+
+// diffInstanceConfigs returns a difference between instance configurations
+// merged from all scopes of the cluster configurations.
+func diffInstanceConfigs(old, new *libcluster.Config,
+	instance string,
+) ([]libcluster.DiffEntry, error) {
+	oldCluster, err := libcluster.MakeClusterConfig(old)
+	if err != nil {
+		return nil, err
+	}
+	newCluster, err := libcluster.MakeClusterConfig(new)
+	if err != nil {
+		return nil, err
+	}
+	if !libcluster.HasInstance(oldCluster, instance) &&
+		!libcluster.HasInstance(newCluster, instance) {
+		return nil, fmt.Errorf("instance %q not found", instance)
+	}
+
+	var oldInstance, newInstance *libcluster.Config
+	if libcluster.HasInstance(oldCluster, instance) {
+		oldInstance = libcluster.Instantiate(oldCluster, instance)
+	}
+	if libcluster.HasInstance(newCluster, instance) {
+		newInstance = libcluster.Instantiate(newCluster, instance)
+	}
+	return libcluster.Diff(oldInstance, newInstance), nil
+}
+
+// PrintDiff prints the difference in the format. The text format groups
+// changes by global, group, replicaset and instance scopes.
+func PrintDiff(w io.Writer, entries []libcluster.DiffEntry, format string) error {
+	switch format {
+	case DiffFormatJSON:
+		if entries == nil {
+			entries = []libcluster.DiffEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the difference: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case DiffFormatText, "":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text or json)", format)
+	}
+
+	var scopes []libcluster.DiffScope
+	lines := map[libcluster.DiffScope][]string{}
+	for _, entry := range entries {
+		scope, path := entry.Scope()
+		if _, ok := lines[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+
+		line := fmt.Sprintf("  %s %s: ", diffMarks[entry.Kind], libcluster.FormatDiffPath(path))
+		switch entry.Kind {
+		case libcluster.DiffAdded:
+			line += libcluster.FormatDiffValue(entry.New)
+		case libcluster.DiffRemoved:
+			line += libcluster.FormatDiffValue(entry.Old)
+		case libcluster.DiffChanged:
+			line += libcluster.FormatDiffValue(entry.Old) + " -> " +
+				libcluster.FormatDiffValue(entry.New)
+		}
+		lines[scope] = append(lines[scope], line)
+	}
+
+	for _, scope := range scopes {
+		if _, err := fmt.Fprintf(w, "%s:\n", scope); err != nil {
+			return err
+		}
+		for _, line := range lines[scope] {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printDiff prints a difference between configurations. It compares
+// configurations of the instance if the name is not empty.
+func printDiff(diffCtx DiffCtx, old, new *libcluster.Config, instance string) error {
+	var (
+		entries []libcluster.DiffEntry
+		err     error
+	)
+	if instance == "" {
+		entries = libcluster.Diff(old, new)
+	} else if entries, err = diffInstanceConfigs(old, new, instance); err != nil {
+		return err
+	}
+	return PrintDiff(diffCtx.Writer, entries, diffCtx.Format)
+}
+
+// collectLocal collects a local configuration from the path as is, the same
+// way as it is published.
+func collectLocal(collectors libcluster.CollectorFactory,
+	path string,
+) (*libcluster.Config, error) {
+	collector, err := collectors.NewFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a file collector: %w", err)
+	}
+	config, err := collector.Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect a configuration from %q: %w", path, err)
+	}
+	return config, nil
+}
+
+// DiffUri prints a difference between a configuration from URI and a cluster
+// configuration file. The file is compared as is, the same way as
+// 'tt cluster publish --diff' does.
+func DiffUri(diffCtx DiffCtx, path string, opts connect.UriOpts) error {
+	local, err := collectLocal(diffCtx.Collectors, path)
+	if err != nil {
+		return err
+	}
+
+	connOpts := libcluster.ConnectOpts{
+		Username: diffCtx.Username,
+		Password: diffCtx.Password,
+	}
+	collector, cancel, err := libcluster.CreateCollector(diffCtx.Collectors, connOpts, opts)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	remote, err := collectCurrent(collector)
+	if err != nil {
+		return fmt.Errorf("failed to collect a configuration: %w", err)
+	}
+
+	return printDiff(diffCtx, remote, local, opts.Params["name"])
+}
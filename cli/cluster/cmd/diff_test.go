@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+const diffOldConfig = `groups:
+  storages:
+    replicasets:
+      s-001:
+        leader: s-001-a
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3301
+          s-001-b: {}
+`
+
+const diffNewConfig = `replication:
+  failover: manual
+groups:
+  storages:
+    replicasets:
+      s-001:
+        leader: s-001-a
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3311
+          s-001-c: {}
+`
+
+func collectYaml(t *testing.T, data string) *libcluster.Config {
+	t.Helper()
+
+	config, err := libcluster.NewYamlCollector([]byte(data)).Collect()
+	require.NoError(t, err)
+	return config
+}
+
+func TestPrintDiff(t *testing.T) {
+	old, new := collectYaml(t, diffOldConfig), collectYaml(t, diffNewConfig)
+
+	var buf bytes.Buffer
+	require.NoError(t, printDiff(DiffCtx{Writer: &buf}, old, new, ""))
+	assert.Equal(t, `instance "s-001-a":
+  ~ iproto.listen: [{"uri":"localhost:3301"}] -> [{"uri":"localhost:3311"}]
+replicaset "s-001":
+  - instances.s-001-b: {}
+  + instances.s-001-c: {}
+global:
+  + replication: {"failover":"manual"}
+`, buf.String())
+
+	buf.Reset()
+	diffCtx := DiffCtx{Writer: &buf, Format: DiffFormatJSON}
+	require.NoError(t, printDiff(diffCtx, old, new, "s-001-a"))
+	assert.JSONEq(t, `[
+		{
+			"kind": "changed",
+			"path": ["iproto", "listen"],
+			"scope": "global",
+			"old": [{"uri": "localhost:3301"}],
+			"new": [{"uri": "localhost:3311"}]
+		},
+		{
+			"kind": "added",
+			"path": ["replication"],
+			"scope": "global",
+			"new": {"failover": "manual"}
+		}
+	]`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printDiff(diffCtx, old, old, ""))
+	assert.Equal(t, "[]\n", buf.String())
+
+	err := printDiff(diffCtx, old, new, "unknown")
+	assert.EqualError(t, err, `instance "unknown" not found`)
+
+	err = printDiff(DiffCtx{Writer: &buf, Format: "yaml"}, old, new, "")
+	assert.EqualError(t, err, "unsupported format: yaml (use text or json)")
+}
+
+func TestPublishCluster_confirm(t *testing.T) {
+	origAskConfirm := askConfirm
+	defer func() { askConfirm = origAskConfirm }()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	publishCtx := PublishCtx{
+		Publishers: libcluster.NewDataPublisherFactory(),
+		Collectors: libcluster.NewCollectorFactory(libcluster.NewDataCollectorFactory()),
+		Src:        []byte(diffNewConfig),
+		Config:     collectYaml(t, diffNewConfig),
+		Force:      true,
+		Confirm:    true,
+	}
+
+	readConfig := func() string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// A new configuration file is compared with an empty configuration.
+	var questions []string
+	askConfirm = func(question string) (bool, error) {
+		questions = append(questions, question)
+		return false, nil
+	}
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+	assert.NoFileExists(t, path)
+	assert.Equal(t, []string{"Publish the configuration?"}, questions)
+
+	require.NoError(t, os.WriteFile(path, []byte(diffOldConfig), 0o644))
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+	assert.Equal(t, diffOldConfig, readConfig())
+
+	askConfirm = func(string) (bool, error) { return true, nil }
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+	assert.Equal(t, diffNewConfig, readConfig())
+
+	// Nothing is published without changes.
+	askConfirm = func(string) (bool, error) {
+		t.Fatal("confirmation is asked without changes")
+		return false, nil
+	}
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+
+	// An instance configuration is merged into the current configuration.
+	instanceConfig := "iproto:\n  listen:\n  - uri: localhost:3321\n"
+	publishCtx.Src = []byte(instanceConfig)
+	publishCtx.Config = collectYaml(t, instanceConfig)
+	askConfirm = func(string) (bool, error) { return true, nil }
+	require.NoError(t, PublishCluster(publishCtx, path, "s-001-c"))
+	assert.Contains(t, readConfig(), "localhost:3321")
+}
+
+func TestCollectLocal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	src := "config:\n  etcd:\n    endpoints:\n    - http://localhost:1\n" + diffOldConfig
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+	t.Setenv("TT_LOG_LEVEL", "debug")
+
+	collectors := libcluster.NewCollectorFactory(libcluster.NewDataCollectorFactory())
+	config, err := collectLocal(collectors, path)
+	require.NoError(t, err)
+	// The configuration is the same as published: environment variables and
+	// a configuration from the storage are not merged.
+	assert.Equal(t, collectYaml(t, src).String(), config.String())
+
+	_, err = collectLocal(collectors, filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to collect a configuration from")
+}
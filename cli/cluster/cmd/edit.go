@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/tt/cli/util"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+	libconnect "github.com/tarantool/tt/lib/connect"
+)
+
+// editDefaultKey is a key to edit if the URI has no 'key' argument.
+const editDefaultKey = "all"
+
+// EditCtx contains information about cluster edit command execution context.
+type EditCtx struct {
+	// Username defines a username for connection.
+	Username string
+	// Password defines a password for connection.
+	Password string
+	// Force defines whether a validation step is omitted.
+	Force bool
+	// Publishers defines a used data publishers factory.
+	Publishers libcluster.DataPublisherFactory
+}
+
+// editFile opens the file in a text editor.
+var editFile = util.EditFile
+
+// writeEditFile writes data into a new temporary file to edit.
+func writeEditFile(data []byte) (string, error) {
+	file, err := os.CreateTemp("", "tt_cluster_*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write a temporary file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// editConfig opens the file in a text editor until the edited configuration
+// is valid or the user refuses to edit it again.
+func editConfig(editCtx EditCtx, path string) ([]byte, *libcluster.Config, error) {
+	for {
+		if err := editFile(path); err != nil {
+			return nil, nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the edited file: %w", err)
+		}
+
+		config, err := libcluster.NewYamlCollector(data).Collect()
+		if err == nil && !editCtx.Force {
+			err = validateRawConfig(config, "")
+		}
+		if err == nil {
+			return data, config, nil
+		}
+
+		log.Errorf("The edited configuration is invalid: %s", err)
+		again, askErr := askConfirm("Edit the configuration again?")
+		if askErr != nil {
+			return nil, nil, fmt.Errorf("failed to get a confirmation: %w", askErr)
+		}
+		if !again {
+			return nil, nil, fmt.Errorf("the edited configuration is invalid: %w", err)
+		}
+	}
+}
+
+// edit edits the stored data and publishes the result if the stored revision
+// is not changed. The edited configuration is kept in a temporary file if it
+// is not published. A stored value that is not a valid YAML is opened as is
+// to fix it, the changes are shown against an empty configuration.
+func edit(editCtx EditCtx, stored libcluster.Data, publisher libcluster.DataPublisher) error {
+	current, err := libcluster.NewYamlCollector(stored.Value).Collect()
+	if err != nil {
+		log.Warnf("The stored configuration is invalid, the changes are shown "+
+			"against an empty configuration: %s", err)
+		current = libcluster.NewConfig()
+	}
+
+	path, err := writeEditFile(stored.Value)
+	if err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if done {
+			os.Remove(path)
+		} else {
+			log.Infof("The edited configuration is saved to %s.", path)
+		}
+	}()
+
+	data, config, err := editConfig(editCtx, path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, stored.Value) {
+		log.Info("The configuration is not changed, nothing to publish.")
+		done = true
+		return nil
+	}
+
+	// Changes of comments or formatting have an empty difference, but they
+	// are published too.
+	err = PrintDiff(os.Stdout, libcluster.Diff(current, config), DiffFormatText)
+	if err != nil {
+		return err
+	}
+	confirmed, err := askConfirm("Publish the configuration?")
+	if err != nil {
+		return fmt.Errorf("failed to get a confirmation: %w", err)
+	}
+	if !confirmed {
+		log.Info("Publishing is canceled.")
+		return nil
+	}
+
+	if err := publisher.Publish(stored.Revision, data); err != nil {
+		return fmt.Errorf("failed to publish the configuration, it could be "+
+			"modified since revision %d: %w", stored.Revision, err)
+	}
+	done = true
+	log.Info("The configuration is published.")
+	return nil
+}
+
+// EditUri edits a configuration from URI in a text editor and publishes it.
+func EditUri(editCtx EditCtx, opts libconnect.UriOpts) error {
+	key := opts.Params["key"]
+	if key == "" {
+		key = editDefaultKey
+		// The key publisher supports the compare-and-swap.
+		opts.Params = maps.Clone(opts.Params)
+		if opts.Params == nil {
+			opts.Params = map[string]string{}
+		}
+		opts.Params["key"] = key
+	}
+
+	connOpts := libcluster.ConnectOpts{
+		Username: editCtx.Username,
+		Password: editCtx.Password,
+	}
+	conn, err := libcluster.ConnectCStorage(opts, connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to connect to config storage: %w", err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if opts.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	stored, err := conn.Get(ctx, libcluster.ConfigKey(opts.Prefix, key))
+	if err != nil {
+		return err
+	}
+
+	publisher, _, cancel, err := createPublisherAndCollector(editCtx.Publishers, nil,
+		connOpts, opts)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return edit(editCtx, stored[0], publisher)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/go-tarantool/v2/test_helpers"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+const editInvalidConfig = "replication:\n  failover: unknown\n"
+
+// stubEdit replaces the editor and the confirmation with stubs. The editor
+// writes the contents one by one and the confirmation returns the answers
+// one by one.
+func stubEdit(t *testing.T, contents []string, answers []bool) *[]string {
+	t.Helper()
+
+	origEditFile, origAskConfirm := editFile, askConfirm
+	t.Cleanup(func() {
+		editFile, askConfirm = origEditFile, origAskConfirm
+	})
+
+	var paths []string
+	editFile = func(path string) error {
+		require.NotEmpty(t, contents, "unexpected editor call")
+		paths = append(paths, path)
+		err := os.WriteFile(path, []byte(contents[0]), 0o644)
+		contents = contents[1:]
+		return err
+	}
+	askConfirm = func(question string) (bool, error) {
+		require.NotEmpty(t, answers, "unexpected question: %s", question)
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, nil
+	}
+	return &paths
+}
+
+func TestEdit(t *testing.T) {
+	stored := libcluster.Data{Value: []byte(diffOldConfig), Revision: 5}
+
+	// The editor is opened again on validation errors.
+	paths := stubEdit(t, []string{editInvalidConfig, diffNewConfig}, []bool{true, true})
+	publisher := &mockPublisher{}
+	require.NoError(t, edit(EditCtx{}, stored, publisher))
+	assert.Equal(t, int64(5), publisher.revision)
+	assert.Equal(t, []byte(diffNewConfig), publisher.data)
+	require.Len(t, *paths, 2)
+	assert.Equal(t, (*paths)[0], (*paths)[1])
+	assert.NoFileExists(t, (*paths)[0])
+
+	// Nothing is published without changes.
+	stubEdit(t, []string{diffOldConfig}, nil)
+	publisher = &mockPublisher{}
+	require.NoError(t, edit(EditCtx{}, stored, publisher))
+	assert.Nil(t, publisher.data)
+
+	// The validation is skipped with the force flag.
+	stubEdit(t, []string{editInvalidConfig}, []bool{true})
+	require.NoError(t, edit(EditCtx{Force: true}, stored, publisher))
+	assert.Equal(t, []byte(editInvalidConfig), publisher.data)
+}
+
+func TestEdit_invalidStored(t *testing.T) {
+	const invalidStored = "replication: [\n"
+	stored := libcluster.Data{Value: []byte(invalidStored), Revision: 5}
+
+	// The stored value is opened as is.
+	var opened string
+	stubEdit(t, nil, []bool{true})
+	editFile = func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		opened = string(data)
+		return os.WriteFile(path, []byte(diffNewConfig), 0o644)
+	}
+
+	publisher := &mockPublisher{}
+	require.NoError(t, edit(EditCtx{}, stored, publisher))
+	assert.Equal(t, invalidStored, opened)
+	assert.Equal(t, int64(5), publisher.revision)
+	assert.Equal(t, []byte(diffNewConfig), publisher.data)
+}
+
+func TestEdit_keepFile(t *testing.T) {
+	stored := libcluster.Data{Value: []byte(diffOldConfig), Revision: 5}
+
+	// An invalid configuration.
+	paths := stubEdit(t, []string{editInvalidConfig}, []bool{false})
+	publisher := &mockPublisher{}
+	err := edit(EditCtx{}, stored, publisher)
+	assert.ErrorContains(t, err, "the edited configuration is invalid")
+	assert.Nil(t, publisher.data)
+	require.Len(t, *paths, 1)
+	assert.FileExists(t, (*paths)[0])
+	os.Remove((*paths)[0])
+
+	// Publishing is canceled.
+	paths = stubEdit(t, []string{diffNewConfig}, []bool{false})
+	require.NoError(t, edit(EditCtx{}, stored, publisher))
+	assert.Nil(t, publisher.data)
+	assert.FileExists(t, (*paths)[0])
+	os.Remove((*paths)[0])
+
+	// The stored revision is changed.
+	paths = stubEdit(t, []string{diffNewConfig}, []bool{true})
+	publisher.err = errors.New("revision mismatch")
+	err = edit(EditCtx{}, stored, publisher)
+	assert.EqualError(t, err, "failed to publish the configuration, it could be "+
+		"modified since revision 5: revision mismatch")
+	data, err := os.ReadFile((*paths)[0])
+	require.NoError(t, err)
+	assert.Equal(t, diffNewConfig, string(data))
+	os.Remove((*paths)[0])
+}
+
+func TestEdit_staleRevision(t *testing.T) {
+	stored := libcluster.Data{Value: []byte(diffOldConfig), Revision: 5}
+
+	// The config storage rejects the transaction since the key is modified
+	// after revision 5.
+	doer := test_helpers.NewMockDoer(t,
+		test_helpers.NewMockResponse(t, []any{map[string]any{"data": []any{}}}),
+		test_helpers.NewMockResponse(t, []any{
+			map[string]any{"data": map[string]any{"is_success": false}},
+		}),
+	)
+	publisher := libcluster.NewTarantoolKeyDataPublisher(&doer, "/prefix", "all", time.Second)
+
+	paths := stubEdit(t, []string{diffNewConfig}, []bool{true})
+	err := edit(EditCtx{}, stored, publisher)
+	assert.EqualError(t, err, "failed to publish the configuration, it could be "+
+		"modified since revision 5: failed to put data into tarantool: wrong revision")
+	require.Len(t, doer.Requests, 2)
+	require.Len(t, *paths, 1)
+	assert.FileExists(t, (*paths)[0])
+	os.Remove((*paths)[0])
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/tarantool/go-tarantool/v2"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+	libconnect "github.com/tarantool/tt/lib/connect"
+)
+
+// historyDefaultKey is a key to work with if the URI has no 'key' argument.
+const historyDefaultKey = "all"
+
+// HistoryCtx contains information about cluster history and rollback commands
+// execution context.
+type HistoryCtx struct {
+	// Username defines a username for connection.
+	Username string
+	// Password defines a password for connection.
+	Password string
+	// Publishers defines a used data publishers factory.
+	Publishers libcluster.DataPublisherFactory
+	// Format defines an output format: text or json.
+	Format string
+	// Limit defines a maximum count of printed revisions. Zero means no limit.
+	Limit int
+	// Revision is a target revision of a rollback.
+	Revision int64
+	// Writer is a writer to print the history to.
+	Writer io.Writer
+}
+
+// historyRecord is a revision of a configuration with a difference from
+// the previous revision.
+type historyRecord struct {
+	// Revision is a modification revision.
+	Revision int64 `json:"revision"`
+	// Time is a modification time if it is known.
+	Time *time.Time `json:"time,omitempty"`
+	// Diff is a difference from the previous revision. It is nil if
+	// the previous revision is unavailable.
+	Diff []libcluster.DiffEntry `json:"diff,omitzero"`
+}
+
+// createHistoryReaderAndPublisher creates a history reader and a publisher
+// with a compare-and-swap support for a key from the URI.
+func createHistoryReaderAndPublisher(
+	publishers libcluster.DataPublisherFactory,
+	connOpts libcluster.ConnectOpts,
+	opts libconnect.UriOpts,
+) (libcluster.HistoryReader, libcluster.DataPublisher, func(), error) {
+	prefix, key, timeout := opts.Prefix, opts.Params["key"], opts.Timeout
+	if key == "" {
+		key = historyDefaultKey
+	}
+
+	var (
+		reader    libcluster.HistoryReader
+		publisher libcluster.DataPublisher
+		err       error
+		closeFunc func()
+	)
+
+	tarantoolFunc := func(conn tarantool.Connector) error {
+		reader = libcluster.NewTarantoolHistoryReader(conn, prefix, key, timeout)
+		if publishers != nil {
+			publisher, err = publishers.NewTarantool(conn, prefix, key, timeout)
+			if err != nil {
+				conn.Close()
+				return fmt.Errorf("failed to create tarantool config storage publisher: %w", err)
+			}
+		}
+		closeFunc = func() { conn.Close() }
+		return nil
+	}
+
+	etcdFunc := func(client *clientv3.Client) error {
+		reader = libcluster.NewEtcdHistoryReader(client, prefix, key, timeout)
+		if publishers != nil {
+			publisher, err = publishers.NewEtcd(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create etcd publisher: %w", err)
+			}
+		}
+		closeFunc = func() { client.Close() }
+		return nil
+	}
+
+	consulFunc := func(client *libcluster.ConsulClient) error {
+		reader = libcluster.NewConsulHistoryReader(client, prefix, key, timeout)
+		if publishers != nil {
+			publisher, err = publishers.NewConsul(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create consul publisher: %w", err)
+			}
+		}
+		closeFunc = func() { client.Close() }
+		return nil
+	}
+
+	err = libcluster.DoOnStorageFuncs(connOpts, opts, libcluster.StorageFuncs{
+		Tarantool: tarantoolFunc,
+		Etcd:      etcdFunc,
+		Consul:    consulFunc,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return reader, publisher, closeFunc, nil
+}
+
+// parseRevision parses a configuration of a revision.
+func parseRevision(revision libcluster.KeyRevision) (*libcluster.Config, error) {
+	config, err := libcluster.NewYamlCollector(revision.Value).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse a configuration of revision %d: %w",
+			revision.Revision, err)
+	}
+	return config, nil
+}
+
+// makeHistoryRecords makes records with differences between neighbouring
+// revisions. The oldest revision is used only as a base of a difference if
+// the revisions count exceeds the limit.
+func makeHistoryRecords(revisions []libcluster.KeyRevision,
+	limit int,
+) ([]historyRecord, error) {
+	configs := make([]*libcluster.Config, len(revisions))
+	for i, revision := range revisions {
+		config, err := parseRevision(revision)
+		if err != nil {
+			return nil, err
+		}
+		configs[i] = config
+	}
+
+	records := []historyRecord{}
+	for i, revision := range revisions {
+		if limit != 0 && i == limit {
+			break
+		}
+		record := historyRecord{Revision: revision.Revision}
+		if !revision.Time.IsZero() {
+			record.Time = &revision.Time
+		}
+		if i+1 < len(revisions) {
+			record.Diff = libcluster.Diff(configs[i+1], configs[i])
+			if record.Diff == nil {
+				record.Diff = []libcluster.DiffEntry{}
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// printHistory prints revisions of a configuration key with differences from
+// previous revisions.
+func printHistory(historyCtx HistoryCtx, reader libcluster.HistoryReader) error {
+	limit := historyCtx.Limit
+	if limit != 0 {
+		// An extra revision is required to get a difference for the oldest
+		// printed one.
+		limit++
+	}
+	revisions, err := reader.History(limit)
+	if err != nil {
+		return err
+	}
+	records, err := makeHistoryRecords(revisions, historyCtx.Limit)
+	if err != nil {
+		return err
+	}
+
+	w := historyCtx.Writer
+	switch historyCtx.Format {
+	case DiffFormatJSON:
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the history: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case DiffFormatText, "":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text or json)", historyCtx.Format)
+	}
+
+	for i, record := range records {
+		header := fmt.Sprintf("revision %d", record.Revision)
+		if record.Time != nil {
+			header += ", " + record.Time.Format(time.RFC3339)
+		}
+		if i == 0 {
+			header += " (current)"
+		}
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+
+		var err error
+		switch {
+		case record.Diff == nil:
+			_, err = fmt.Fprintln(w, "  the previous revision is unavailable")
+		case len(record.Diff) == 0:
+			_, err = fmt.Fprintln(w, "  no changes")
+		default:
+			err = PrintDiff(w, record.Diff, DiffFormatText)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback republishes a value of the target revision. The value is published
+// only if the key is not modified after the history is read.
+func rollback(historyCtx HistoryCtx, reader libcluster.HistoryReader,
+	publisher libcluster.DataPublisher,
+) error {
+	revisions, err := reader.History(0)
+	if err != nil {
+		return err
+	}
+	current := revisions[0]
+	if current.Revision == historyCtx.Revision {
+		log.Infof("The revision %d is the current one, nothing to roll back.",
+			historyCtx.Revision)
+		return nil
+	}
+
+	target, found := libcluster.FindRevision(revisions, historyCtx.Revision)
+	if !found {
+		return fmt.Errorf("revision %d is not found in the history", historyCtx.Revision)
+	}
+
+	currentConfig, err := parseRevision(current)
+	if err != nil {
+		return err
+	}
+	targetConfig, err := parseRevision(target)
+	if err != nil {
+		return err
+	}
+	err = PrintDiff(historyCtx.Writer, libcluster.Diff(currentConfig, targetConfig),
+		DiffFormatText)
+	if err != nil {
+		return err
+	}
+
+	if err := publisher.Publish(current.Revision, target.Value); err != nil {
+		return fmt.Errorf("failed to roll back to revision %d: %w", target.Revision, err)
+	}
+	log.Infof("The configuration is rolled back to revision %d.", target.Revision)
+	return nil
+}
+
+// History prints revisions of a configuration from URI.
+func History(historyCtx HistoryCtx, opts libconnect.UriOpts) error {
+	connOpts := libcluster.ConnectOpts{
+		Username: historyCtx.Username,
+		Password: historyCtx.Password,
+	}
+	reader, _, cancel, err := createHistoryReaderAndPublisher(nil, connOpts, opts)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return printHistory(historyCtx, reader)
+}
+
+// Rollback republishes a previous revision of a configuration from URI.
+func Rollback(historyCtx HistoryCtx, opts libconnect.UriOpts) error {
+	connOpts := libcluster.ConnectOpts{
+		Username: historyCtx.Username,
+		Password: historyCtx.Password,
+	}
+	reader, publisher, cancel, err := createHistoryReaderAndPublisher(
+		historyCtx.Publishers, connOpts, opts)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return rollback(historyCtx, reader, publisher)
+}
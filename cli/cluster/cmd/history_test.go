@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+type mockHistoryReader struct {
+	revisions []libcluster.KeyRevision
+	limit     int
+}
+
+func (reader *mockHistoryReader) History(limit int) ([]libcluster.KeyRevision, error) {
+	reader.limit = limit
+	if limit != 0 && len(reader.revisions) > limit {
+		return reader.revisions[:limit], nil
+	}
+	return reader.revisions, nil
+}
+
+type mockPublisher struct {
+	revision int64
+	data     []byte
+	err      error
+}
+
+func (publisher *mockPublisher) Publish(revision int64, data []byte) error {
+	publisher.revision = revision
+	publisher.data = data
+	return publisher.err
+}
+
+var historyRevisions = []libcluster.KeyRevision{
+	{
+		Revision: 9,
+		Time:     time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC),
+		Value:    []byte(diffNewConfig),
+	},
+	{Revision: 7, Value: []byte(diffNewConfig)},
+	{
+		Revision: 3,
+		Time:     time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC),
+		Value:    []byte(diffOldConfig),
+	},
+}
+
+func TestPrintHistory(t *testing.T) {
+	reader := &mockHistoryReader{revisions: historyRevisions}
+
+	var buf bytes.Buffer
+	require.NoError(t, printHistory(HistoryCtx{Writer: &buf}, reader))
+	assert.Equal(t, 0, reader.limit)
+	assert.Equal(t, `revision 9, 2026-10-18T11:00:00Z (current)
+  no changes
+revision 7
+instance "s-001-a":
+  ~ iproto.listen: [{"uri":"localhost:3301"}] -> [{"uri":"localhost:3311"}]
+replicaset "s-001":
+  - instances.s-001-b: {}
+  + instances.s-001-c: {}
+global:
+  + replication: {"failover":"manual"}
+revision 3, 2026-10-18T10:00:00Z
+  the previous revision is unavailable
+`, buf.String())
+
+	buf.Reset()
+	historyCtx := HistoryCtx{Writer: &buf, Format: DiffFormatJSON, Limit: 2}
+	require.NoError(t, printHistory(historyCtx, reader))
+	assert.Equal(t, 3, reader.limit)
+	assert.JSONEq(t, `[
+		{"revision": 9, "time": "2026-10-18T11:00:00Z", "diff": []},
+		{
+			"revision": 7,
+			"diff": [
+				{
+					"kind": "changed",
+					"path": ["groups", "storages", "replicasets", "s-001", "instances",
+						"s-001-a", "iproto", "listen"],
+					"scope": "instance",
+					"name": "s-001-a",
+					"old": [{"uri": "localhost:3301"}],
+					"new": [{"uri": "localhost:3311"}]
+				},
+				{
+					"kind": "removed",
+					"path": ["groups", "storages", "replicasets", "s-001", "instances",
+						"s-001-b"],
+					"scope": "replicaset",
+					"name": "s-001",
+					"old": {}
+				},
+				{
+					"kind": "added",
+					"path": ["groups", "storages", "replicasets", "s-001", "instances",
+						"s-001-c"],
+					"scope": "replicaset",
+					"name": "s-001",
+					"new": {}
+				},
+				{
+					"kind": "added",
+					"path": ["replication"],
+					"scope": "global",
+					"new": {"failover": "manual"}
+				}
+			]
+		}
+	]`, buf.String())
+
+	err := printHistory(HistoryCtx{Writer: &buf, Format: "yaml"}, reader)
+	assert.EqualError(t, err, "unsupported format: yaml (use text or json)")
+
+	reader.revisions = []libcluster.KeyRevision{{Revision: 1, Value: []byte("[")}}
+	err = printHistory(HistoryCtx{Writer: &buf}, reader)
+	assert.ErrorContains(t, err, "failed to parse a configuration of revision 1")
+}
+
+func TestRollback(t *testing.T) {
+	reader := &mockHistoryReader{revisions: historyRevisions}
+	publisher := &mockPublisher{}
+
+	var buf bytes.Buffer
+	historyCtx := HistoryCtx{Writer: &buf, Revision: 3}
+	require.NoError(t, rollback(historyCtx, reader, publisher))
+	assert.Equal(t, int64(9), publisher.revision)
+	assert.Equal(t, []byte(diffOldConfig), publisher.data)
+	assert.Contains(t, buf.String(), "- replication")
+
+	publisher = &mockPublisher{}
+	historyCtx.Revision = 9
+	require.NoError(t, rollback(historyCtx, reader, publisher))
+	assert.Nil(t, publisher.data)
+
+	historyCtx.Revision = 5
+	err := rollback(historyCtx, reader, publisher)
+	assert.EqualError(t, err, "revision 5 is not found in the history")
+
+	historyCtx.Revision = 7
+	publisher.err = errors.New("revision mismatch")
+	err = rollback(historyCtx, reader, publisher)
+	assert.EqualError(t, err, "failed to roll back to revision 7: revision mismatch")
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// LintFormatSarif is a SARIF format of lint issues.
+const LintFormatSarif = "sarif"
+
+// LintCtx contains information about cluster lint command execution context.
+type LintCtx struct {
+	// Format defines an output format: text, json or sarif.
+	Format string
+	// Writer is a writer to print issues to.
+	Writer io.Writer
+}
+
+// lintIssue is a lint issue with a location in a configuration file.
+type lintIssue struct {
+	libcluster.LintIssue
+	// Line is a line of the issue in the file or zero if it is unknown.
+	Line int `json:"line,omitempty"`
+}
+
+// yamlNodeLine returns a line of the deepest existing node on the path in
+// a YAML document.
+func yamlNodeLine(node *yaml.Node, path []string) int {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return yamlNodeLine(node.Content[0], path)
+	}
+	if len(path) == 0 || node.Kind != yaml.MappingNode {
+		return 0
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		if line := yamlNodeLine(node.Content[i+1], path[1:]); line != 0 {
+			return line
+		}
+		return node.Content[i].Line
+	}
+	return 0
+}
+
+// locateIssues finds lines of the issues in the configuration data.
+func locateIssues(data []byte, issues []libcluster.LintIssue) []lintIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		doc = yaml.Node{}
+	}
+
+	located := make([]lintIssue, 0, len(issues))
+	for _, issue := range issues {
+		located = append(located, lintIssue{
+			LintIssue: issue,
+			Line:      yamlNodeLine(&doc, issue.Path),
+		})
+	}
+	return located
+}
+
+// sarifLog is a minimal log of the Static Analysis Results Interchange Format.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun is a run of a tool with results.
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationUri string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// sarifRule is a description of a rule.
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+// sarifMessage is a text message.
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifResult is a found issue.
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+// sarifLocation is a location of an issue.
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			Uri string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+// sarifLogicalLocation is a configuration path of a result.
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifRegion is a region of a file.
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// makeSarifLog makes a SARIF log of the issues in the file.
+func makeSarifLog(path string, issues []lintIssue) sarifLog {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "tt"
+	run.Tool.Driver.InformationUri = "https://github.com/tarantool/tt"
+	for _, rule := range libcluster.LintRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			Id:               rule.ID,
+			ShortDescription: sarifMessage{Text: rule.Description},
+		})
+	}
+
+	for _, issue := range issues {
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.Uri = filepath.ToSlash(path)
+		if issue.Line != 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line}
+		}
+		location.LogicalLocations = []sarifLogicalLocation{
+			{FullyQualifiedName: libcluster.FormatDiffPath(issue.Path)},
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleId:    issue.Rule,
+			Level:     string(issue.Severity),
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+// LintRulesHelp returns a description of lint rules.
+func LintRulesHelp() string {
+	var help strings.Builder
+	for _, rule := range libcluster.LintRules {
+		fmt.Fprintf(&help, "  %s: %s\n", rule.ID, rule.Description)
+	}
+	return help.String()
+}
+
+// printLintIssues prints issues in the file in the format.
+func printLintIssues(w io.Writer, path string, issues []lintIssue, format string) error {
+	var report any
+	switch format {
+	case DiffFormatJSON:
+		report = issues
+	case LintFormatSarif:
+		report = makeSarifLog(path, issues)
+	case DiffFormatText, "":
+		for _, issue := range issues {
+			location := path
+			if issue.Line != 0 {
+				location += fmt.Sprintf(":%d", issue.Line)
+			}
+			_, err := fmt.Fprintf(w, "%s: %s [%s] %s: %s\n", location, issue.Severity,
+				issue.Rule, libcluster.FormatDiffPath(issue.Path), issue.Message)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %s (use text, json or sarif)", format)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal issues: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// LintCluster checks semantics of a cluster configuration file. It returns
+// an error if issues with the error severity are found.
+func LintCluster(lintCtx LintCtx, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration: %w", err)
+	}
+	config, err := libcluster.NewYamlCollector(data).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to parse the configuration: %w", err)
+	}
+	cconfig, err := libcluster.MakeClusterConfig(config)
+	if err != nil {
+		return err
+	}
+
+	issues := libcluster.Lint(cconfig, libcluster.LintOpts{AppDir: filepath.Dir(path)})
+	located := locateIssues(data, issues)
+	if err := printLintIssues(lintCtx.Writer, path, located, lintCtx.Format); err != nil {
+		return err
+	}
+
+	errorsCount := 0
+	for _, issue := range issues {
+		if issue.Severity == libcluster.LintError {
+			errorsCount++
+		}
+	}
+	if errorsCount != 0 {
+		return fmt.Errorf("found %d error(s) in the configuration", errorsCount)
+	}
+	return nil
+}
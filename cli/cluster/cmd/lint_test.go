@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+const lintConfig = `groups:
+  storages:
+    replicasets:
+      s-001:
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3301
+          s-001-b:
+            iproto:
+              listen:
+              - uri: localhost:3301
+            database:
+              mode: rw
+`
+
+func TestLintCluster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(lintConfig), 0o644))
+
+	var buf bytes.Buffer
+	err := LintCluster(LintCtx{Writer: &buf}, path)
+	assert.EqualError(t, err, "found 2 error(s) in the configuration")
+	prefix := "groups.storages.replicasets.s-001.instances."
+	assert.Equal(t, path+":8: error [duplicate-listen] "+prefix+"s-001-a.iproto.listen: "+
+		`URI "localhost:3301" is used by instances: s-001-a, s-001-b`+"\n"+
+		path+":12: error [duplicate-listen] "+prefix+"s-001-b.iproto.listen: "+
+		`URI "localhost:3301" is used by instances: s-001-a, s-001-b`+"\n",
+		buf.String())
+
+	buf.Reset()
+	err = LintCluster(LintCtx{Writer: &buf, Format: DiffFormatJSON}, path)
+	require.Error(t, err)
+	assert.JSONEq(t, `[
+		{
+			"rule": "duplicate-listen",
+			"severity": "error",
+			"path": ["groups", "storages", "replicasets", "s-001", "instances", "s-001-a",
+				"iproto", "listen"],
+			"message": "URI \"localhost:3301\" is used by instances: s-001-a, s-001-b",
+			"line": 8
+		},
+		{
+			"rule": "duplicate-listen",
+			"severity": "error",
+			"path": ["groups", "storages", "replicasets", "s-001", "instances", "s-001-b",
+				"iproto", "listen"],
+			"message": "URI \"localhost:3301\" is used by instances: s-001-a, s-001-b",
+			"line": 12
+		}
+	]`, buf.String())
+
+	// Warnings are not errors.
+	require.NoError(t, os.WriteFile(path, []byte(`groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i: {}
+`), 0o644))
+	buf.Reset()
+	require.NoError(t, LintCluster(LintCtx{Writer: &buf, Format: LintFormatSarif}, path))
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+	run := report.Runs[0]
+	assert.Equal(t, "tt", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, len(libcluster.LintRules))
+	require.Len(t, run.Results, 1)
+	result := run.Results[0]
+	assert.Equal(t, "unreachable-instance", result.RuleId)
+	assert.Equal(t, "warning", result.Level)
+	assert.Equal(t, `instance "i" has no URI to connect: set iproto.listen`, result.Message.Text)
+	require.Len(t, result.Locations, 1)
+	location := result.Locations[0]
+	assert.Equal(t, filepath.ToSlash(path), location.PhysicalLocation.ArtifactLocation.Uri)
+	assert.Equal(t, &sarifRegion{StartLine: 6}, location.PhysicalLocation.Region)
+	assert.Equal(t, []sarifLogicalLocation{
+		{FullyQualifiedName: "groups.g.replicasets.r.instances.i.iproto.listen"},
+	}, location.LogicalLocations)
+
+	err = LintCluster(LintCtx{Writer: &buf, Format: "yaml"}, path)
+	assert.EqualError(t, err, "unsupported format: yaml (use text, json or sarif)")
+}
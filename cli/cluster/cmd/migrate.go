@@ -0,0 +1,513 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/tarantool/tt/cli/replicaset"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// MigrateCartridgeCtx contains information about cluster migrate-cartridge
+// command execution context.
+type MigrateCartridgeCtx struct {
+	// Writer is a writer to print the migrated configuration to.
+	Writer io.Writer
+	// ReportWriter is a writer to print a report of the settings that
+	// could not be translated to.
+	ReportWriter io.Writer
+}
+
+// migrateIssue is a setting of a Cartridge configuration that could not be
+// translated or is translated approximately.
+type migrateIssue struct {
+	// Path is a path of the setting in the Cartridge configuration.
+	Path string
+	// Message describes the issue.
+	Message string
+}
+
+// cartridgeServer is an instance from a Cartridge topology.
+type cartridgeServer struct {
+	URI            string         `mapstructure:"uri"`
+	ReplicasetUUID string         `mapstructure:"replicaset_uuid"`
+	Disabled       bool           `mapstructure:"disabled"`
+	Zone           string         `mapstructure:"zone"`
+	Other          map[string]any `mapstructure:",remain"`
+}
+
+// cartridgeReplicaset is a replicaset from a Cartridge topology.
+type cartridgeReplicaset struct {
+	Alias       string          `mapstructure:"alias"`
+	Roles       map[string]bool `mapstructure:"roles"`
+	Master      any             `mapstructure:"master"`
+	Weight      *float64        `mapstructure:"weight"`
+	AllRW       bool            `mapstructure:"all_rw"`
+	VShardGroup string          `mapstructure:"vshard_group"`
+	Other       map[string]any  `mapstructure:",remain"`
+}
+
+// Cartridge failover modes.
+const (
+	cartridgeFailoverDisabled = "disabled"
+	cartridgeFailoverEventual = "eventual"
+	cartridgeFailoverStateful = "stateful"
+	cartridgeFailoverRaft     = "raft"
+)
+
+// Cartridge roles with Tarantool 3 equivalents.
+const (
+	cartridgeRoleRouter              = "vshard-router"
+	cartridgeRoleStorage             = "vshard-storage"
+	cartridgeRoleFailoverCoordinator = "failover-coordinator"
+)
+
+// cartridgeRoles are Cartridge roles replaced by Tarantool 3 roles.
+var cartridgeRoles = map[string]string{
+	"crud-router":  "roles.crud-router",
+	"crud-storage": "roles.crud-storage",
+}
+
+// cartridgeVShardSettings are vshard settings with the same names in
+// Tarantool 3 sharding configuration.
+var cartridgeVShardSettings = []string{
+	"bucket_count",
+	"rebalancer_disbalance_threshold",
+	"rebalancer_max_receiving",
+	"rebalancer_max_sending",
+	"rebalancer_mode",
+	"sched_move_quota",
+	"sched_ref_quota",
+	"sync_timeout",
+}
+
+// Groups of the migrated replicasets.
+const (
+	migrateGroupRouters  = "routers"
+	migrateGroupStorages = "storages"
+	migrateGroupDefault  = "default"
+)
+
+// cartridgeMigrator translates a Cartridge configuration into a Tarantool 3
+// cluster configuration.
+type cartridgeMigrator struct {
+	source replicaset.CartridgeConfig
+	config *libcluster.Config
+	issues []migrateIssue
+	// sharded is true if there are replicasets with vshard roles.
+	sharded bool
+}
+
+// report adds an issue.
+func (m *cartridgeMigrator) report(path, format string, args ...any) {
+	m.issues = append(m.issues, migrateIssue{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// set sets a value in the migrated configuration.
+func (m *cartridgeMigrator) set(path []string, value any) {
+	// The path always consists of maps in the migrated configuration.
+	_ = m.config.Set(path, value)
+}
+
+// alias returns an alias of an instance by UUID.
+func (m *cartridgeMigrator) alias(uuid string) string {
+	if alias, ok := m.source.Aliases[uuid]; ok && alias != "" {
+		return alias
+	}
+	return uuid
+}
+
+// isZeroValue returns true if the value is not set or has a default value.
+func isZeroValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// decodeCartridgeValue decodes a value of a Cartridge configuration. Empty
+// Lua tables could be received as arrays, so they are decoded as empty maps.
+func decodeCartridgeValue(input, output any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result: output,
+		DecodeHook: func(from, to reflect.Type, data any) (any, error) {
+			if from.Kind() == reflect.Slice && to.Kind() == reflect.Map &&
+				reflect.ValueOf(data).Len() == 0 {
+				return map[string]any{}, nil
+			}
+			return data, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// sortedKeys returns sorted keys of a map.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// cartridgeMasters returns a list of the leaders UUIDs in the priority order.
+func cartridgeMasters(master any) []string {
+	switch v := master.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		masters := make([]string, 0, len(v))
+		for _, uuid := range v {
+			masters = append(masters, fmt.Sprint(uuid))
+		}
+		return masters
+	}
+	return nil
+}
+
+// migrateCredentials translates the cluster cookie.
+func (m *cartridgeMigrator) migrateCredentials() {
+	if m.source.Cookie == "" {
+		m.report("cluster_cookie", "the cluster cookie is unknown: set a password of "+
+			"the admin user in credentials.users.admin.password")
+	} else {
+		m.set([]string{"credentials", "users", "admin", "password"}, m.source.Cookie)
+	}
+	m.set([]string{"iproto", "advertise", "peer", "login"}, "admin")
+}
+
+// migrateFailover translates failover settings and returns a Tarantool 3
+// failover mode.
+func (m *cartridgeMigrator) migrateFailover(topology map[string]any) string {
+	const path = "topology.failover"
+
+	var settings map[string]any
+	mode := cartridgeFailoverDisabled
+	switch v := topology["failover"].(type) {
+	case bool:
+		if v {
+			mode = cartridgeFailoverEventual
+		}
+	case map[string]any:
+		settings = v
+		if value, ok := v["mode"].(string); ok {
+			mode = value
+		}
+	}
+
+	failover := "manual"
+	switch mode {
+	case cartridgeFailoverDisabled:
+	case cartridgeFailoverEventual:
+		failover = "election"
+		m.report(path+".mode", "the eventual failover is translated to the election failover")
+	case cartridgeFailoverRaft:
+		failover = "election"
+	case cartridgeFailoverStateful:
+		failover = "supervised"
+		m.report(path+".state_provider", "the state provider %q is not used: the "+
+			"supervised failover keeps its state in the config storage, run a failover "+
+			"coordinator with 'tarantool --failover'", settings["state_provider"])
+	default:
+		m.report(path+".mode", "the unknown failover mode %q is translated to "+
+			"the manual failover", mode)
+	}
+
+	for _, key := range sortedKeys(settings) {
+		if key == "mode" || key == "state_provider" || isZeroValue(settings[key]) {
+			continue
+		}
+		m.report(path+"."+key, "the failover setting is not translated")
+	}
+
+	m.set([]string{"replication", "failover"}, failover)
+	return failover
+}
+
+// migrateServers translates instances and returns them by replicasets UUIDs.
+func (m *cartridgeMigrator) migrateServers(topology map[string]any,
+) (map[string]map[string]cartridgeServer, error) {
+	servers, _ := topology["servers"].(map[string]any)
+	byReplicasets := map[string]map[string]cartridgeServer{}
+	for _, uuid := range sortedKeys(servers) {
+		if servers[uuid] == "expelled" {
+			continue
+		}
+		alias := m.alias(uuid)
+		path := "topology.servers." + alias
+
+		var server cartridgeServer
+		if err := decodeCartridgeValue(servers[uuid], &server); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if server.ReplicasetUUID == "" {
+			continue
+		}
+		if server.Disabled {
+			m.report(path, "the instance is disabled and skipped")
+			continue
+		}
+		if server.Zone != "" {
+			m.report(path+".zone", "zones are not supported, the zone %q is skipped",
+				server.Zone)
+		}
+		for _, key := range sortedKeys(server.Other) {
+			if !isZeroValue(server.Other[key]) {
+				m.report(path+"."+key, "the instance setting is not translated")
+			}
+		}
+
+		if byReplicasets[server.ReplicasetUUID] == nil {
+			byReplicasets[server.ReplicasetUUID] = map[string]cartridgeServer{}
+		}
+		byReplicasets[server.ReplicasetUUID][uuid] = server
+	}
+	return byReplicasets, nil
+}
+
+// migrateRoles translates roles of a replicaset. It returns a group of the
+// replicaset, sharding roles and other roles.
+func (m *cartridgeMigrator) migrateRoles(path string, enabled map[string]bool,
+) (string, []any, []any) {
+	group := migrateGroupDefault
+	var shardingRoles, roles []any
+	for _, role := range sortedKeys(enabled) {
+		if !enabled[role] {
+			continue
+		}
+		switch role {
+		case cartridgeRoleRouter:
+			shardingRoles = append(shardingRoles, "router")
+			if group == migrateGroupDefault {
+				group = migrateGroupRouters
+			}
+		case cartridgeRoleStorage:
+			shardingRoles = append(shardingRoles, "storage")
+			group = migrateGroupStorages
+		case cartridgeRoleFailoverCoordinator:
+			m.report(path+"."+role, "the role is not needed: run a failover "+
+				"coordinator with 'tarantool --failover' for the supervised failover")
+		default:
+			if name, ok := cartridgeRoles[role]; ok {
+				roles = append(roles, name)
+				continue
+			}
+			roles = append(roles, role)
+			m.report(path+"."+role, "the role is added as is: port it to "+
+				"the Tarantool 3 roles API")
+		}
+	}
+	return group, shardingRoles, roles
+}
+
+// migrateReplicasets translates replicasets.
+func (m *cartridgeMigrator) migrateReplicasets(topology map[string]any, failover string,
+	mainVShardGroup string,
+) error {
+	servers, err := m.migrateServers(topology)
+	if err != nil {
+		return err
+	}
+
+	replicasets, _ := topology["replicasets"].(map[string]any)
+	for _, uuid := range sortedKeys(replicasets) {
+		var rs cartridgeReplicaset
+		if err := decodeCartridgeValue(replicasets[uuid], &rs); err != nil {
+			return fmt.Errorf("failed to parse topology.replicasets.%s: %w", uuid, err)
+		}
+		name := cmp.Or(rs.Alias, uuid)
+		path := "topology.replicasets." + name
+
+		instances := servers[uuid]
+		if len(instances) == 0 {
+			m.report(path, "the replicaset has no instances and skipped")
+			continue
+		}
+
+		group, shardingRoles, roles := m.migrateRoles(path+".roles", rs.Roles)
+		rsPath := []string{"groups", group, "replicasets", name}
+		if len(shardingRoles) > 0 {
+			m.sharded = true
+			m.set(append(slices.Clone(rsPath), "sharding", "roles"), shardingRoles)
+		}
+		if len(roles) > 0 {
+			m.set(append(slices.Clone(rsPath), "roles"), roles)
+		}
+		if rs.Weight != nil && *rs.Weight != 1 && slices.Contains(shardingRoles, "storage") {
+			m.set(append(slices.Clone(rsPath), "sharding", "weight"), *rs.Weight)
+		}
+		if rs.VShardGroup != "" && rs.VShardGroup != mainVShardGroup {
+			m.report(path+".vshard_group", "multiple vshard groups are not supported: "+
+				"the replicaset is a part of the single sharded cluster")
+		}
+		for _, key := range sortedKeys(rs.Other) {
+			if !isZeroValue(rs.Other[key]) {
+				m.report(path+"."+key, "the replicaset setting is not translated")
+			}
+		}
+
+		for uuid, server := range instances {
+			m.set(append(slices.Clone(rsPath), "instances", m.alias(uuid), "iproto", "listen"),
+				[]any{map[string]any{"uri": server.URI}})
+		}
+
+		var leaders []string
+		for _, uuid := range cartridgeMasters(rs.Master) {
+			if _, ok := instances[uuid]; ok {
+				leaders = append(leaders, m.alias(uuid))
+			}
+		}
+		switch {
+		case rs.AllRW:
+			m.set(append(slices.Clone(rsPath), "replication", "failover"), "off")
+			m.set(append(slices.Clone(rsPath), "database", "mode"), "rw")
+		case failover == "manual" && len(leaders) > 0:
+			m.set(append(slices.Clone(rsPath), "leader"), leaders[0])
+		case failover == "supervised" && len(leaders) > 0:
+			for i, leader := range leaders {
+				m.set([]string{"failover", "replicasets", name, "priority", leader},
+					len(leaders)-i)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateVShard translates vshard settings and returns a name of the main
+// vshard group.
+func (m *cartridgeMigrator) migrateVShard() string {
+	groups, ok := m.source.Config["vshard_groups"].(map[string]any)
+	if !ok {
+		groups = map[string]any{}
+		if vshard, ok := m.source.Config["vshard"]; ok {
+			groups["default"] = vshard
+		}
+	}
+
+	main := "default"
+	if _, ok := groups[main]; !ok && len(groups) == 1 {
+		main = sortedKeys(groups)[0]
+	}
+	for _, name := range sortedKeys(groups) {
+		path := "vshard_groups." + name
+		if name != main {
+			m.report(path, "multiple vshard groups are not supported, the group is "+
+				"not translated")
+			continue
+		}
+
+		settings, _ := groups[name].(map[string]any)
+		for _, key := range sortedKeys(settings) {
+			switch {
+			case key == "bootstrapped":
+			case slices.Contains(cartridgeVShardSettings, key):
+				m.set([]string{"sharding", key}, settings[key])
+			case !isZeroValue(settings[key]):
+				m.report(path+"."+key, "the vshard setting is not translated")
+			}
+		}
+	}
+	return main
+}
+
+// migrateSections reports sections of the configuration that could not be
+// translated.
+func (m *cartridgeMigrator) migrateSections() {
+	for _, section := range sortedKeys(m.source.Config) {
+		switch section {
+		case "topology", "vshard", "vshard_groups":
+		case "users_acl":
+			users, _ := m.source.Config[section].(map[string]any)
+			for _, user := range sortedKeys(users) {
+				m.report(section+"."+user, "Cartridge web UI users are not supported")
+			}
+		case "auth":
+			auth, _ := m.source.Config[section].(map[string]any)
+			if enabled, _ := auth["enabled"].(bool); enabled {
+				m.report(section, "Cartridge web UI authentication is not supported")
+			}
+		default:
+			m.report(section, "the section is not translated: move it into roles_cfg "+
+				"of a role or config.context")
+		}
+	}
+}
+
+// migrate translates the Cartridge configuration.
+func (m *cartridgeMigrator) migrate() error {
+	if m.source.FromAppFiles {
+		m.report("config", "the clusterwide configuration is not found, the configuration "+
+			"is made from replicasets.yml and failover.yml: vshard settings, users and "+
+			"custom sections are not migrated")
+	}
+
+	topology, _ := m.source.Config["topology"].(map[string]any)
+	for _, key := range sortedKeys(topology) {
+		switch key {
+		case "servers", "replicasets", "failover":
+		default:
+			if !isZeroValue(topology[key]) {
+				m.report("topology."+key, "the topology setting is not translated")
+			}
+		}
+	}
+
+	m.migrateCredentials()
+	failover := m.migrateFailover(topology)
+	mainVShardGroup := m.migrateVShard()
+	if err := m.migrateReplicasets(topology, failover, mainVShardGroup); err != nil {
+		return err
+	}
+	if m.sharded {
+		m.set([]string{"iproto", "advertise", "sharding", "login"}, "admin")
+	}
+	m.migrateSections()
+
+	if err := validateRawClusterConfig(m.config); err != nil {
+		m.report("config", "the migrated configuration is invalid: %s", err)
+	}
+	slices.SortStableFunc(m.issues, func(a, b migrateIssue) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return nil
+}
+
+// MigrateCartridge translates a Cartridge configuration into a Tarantool 3
+// cluster configuration. It prints the configuration and a report of the
+// settings that could not be translated.
+func MigrateCartridge(migrateCtx MigrateCartridgeCtx,
+	source replicaset.CartridgeConfig,
+) error {
+	migrator := cartridgeMigrator{
+		source: source,
+		config: libcluster.NewConfig(),
+	}
+	if err := migrator.migrate(); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(migrateCtx.Writer, migrator.config.String()); err != nil {
+		return err
+	}
+	for _, issue := range migrator.issues {
+		_, err := fmt.Fprintf(migrateCtx.ReportWriter, "%s: %s\n", issue.Path, issue.Message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/replicaset"
+)
+
+func TestMigrateCartridge(t *testing.T) {
+	source := replicaset.CartridgeConfig{
+		Config: map[string]any{
+			"topology": map[string]any{
+				"servers": map[string]any{
+					"uuid-router": map[string]any{
+						"uri":             "localhost:3301",
+						"replicaset_uuid": "uuid-rs-router",
+					},
+					"uuid-s1-master": map[string]any{
+						"uri":             "localhost:3302",
+						"replicaset_uuid": "uuid-rs-s1",
+						"zone":            "msk",
+					},
+					"uuid-s1-replica": map[string]any{
+						"uri":             "localhost:3303",
+						"replicaset_uuid": "uuid-rs-s1",
+					},
+					"uuid-s1-disabled": map[string]any{
+						"uri":             "localhost:3304",
+						"replicaset_uuid": "uuid-rs-s1",
+						"disabled":        true,
+					},
+					"uuid-expelled": "expelled",
+				},
+				"replicasets": map[string]any{
+					"uuid-rs-router": map[string]any{
+						"alias": "router",
+						"roles": map[string]any{
+							"vshard-router":        true,
+							"failover-coordinator": true,
+							"app.roles.api":        true,
+						},
+						"master": "uuid-router",
+						"weight": 0,
+					},
+					"uuid-rs-s1": map[string]any{
+						"alias":  "s-1",
+						"roles":  map[string]any{"vshard-storage": true, "crud-storage": true},
+						"master": []any{"uuid-s1-master", "uuid-s1-replica"},
+						"weight": 2,
+					},
+				},
+				"failover": map[string]any{
+					"mode":             "stateful",
+					"state_provider":   "stateboard",
+					"failover_timeout": 20,
+				},
+			},
+			"vshard": map[string]any{
+				"bucket_count":        30000,
+				"bootstrapped":        true,
+				"collect_lua_garbage": true,
+			},
+			"users_acl": map[string]any{"user": map[string]any{}},
+			"custom":    "value",
+		},
+		Aliases: map[string]string{
+			"uuid-router":      "router-1",
+			"uuid-s1-master":   "s1-master",
+			"uuid-s1-replica":  "s1-replica",
+			"uuid-s1-disabled": "s1-disabled",
+		},
+		Cookie: "cookie",
+	}
+
+	var buf, report bytes.Buffer
+	migrateCtx := MigrateCartridgeCtx{Writer: &buf, ReportWriter: &report}
+	require.NoError(t, MigrateCartridge(migrateCtx, source))
+	assert.Equal(t, `credentials:
+  users:
+    admin:
+      password: cookie
+failover:
+  replicasets:
+    router:
+      priority:
+        router-1: 1
+    s-1:
+      priority:
+        s1-master: 2
+        s1-replica: 1
+groups:
+  routers:
+    replicasets:
+      router:
+        instances:
+          router-1:
+            iproto:
+              listen:
+                - uri: localhost:3301
+        roles:
+          - app.roles.api
+        sharding:
+          roles:
+            - router
+  storages:
+    replicasets:
+      s-1:
+        instances:
+          s1-master:
+            iproto:
+              listen:
+                - uri: localhost:3302
+          s1-replica:
+            iproto:
+              listen:
+                - uri: localhost:3303
+        roles:
+          - roles.crud-storage
+        sharding:
+          roles:
+            - storage
+          weight: 2
+iproto:
+  advertise:
+    peer:
+      login: admin
+    sharding:
+      login: admin
+replication:
+  failover: supervised
+sharding:
+  bucket_count: 30000
+`, buf.String())
+	assert.Equal(t, strings.Join([]string{
+		"custom: the section is not translated: move it into roles_cfg of a role or " +
+			"config.context",
+		"topology.failover.failover_timeout: the failover setting is not translated",
+		`topology.failover.state_provider: the state provider "stateboard" is not used: ` +
+			"the supervised failover keeps its state in the config storage, run a failover " +
+			"coordinator with 'tarantool --failover'",
+		"topology.replicasets.router.roles.app.roles.api: the role is added as is: port it " +
+			"to the Tarantool 3 roles API",
+		"topology.replicasets.router.roles.failover-coordinator: the role is not needed: run " +
+			"a failover coordinator with 'tarantool --failover' for the supervised failover",
+		"topology.servers.s1-disabled: the instance is disabled and skipped",
+		`topology.servers.s1-master.zone: zones are not supported, the zone "msk" is skipped`,
+		"users_acl.user: Cartridge web UI users are not supported",
+		"vshard_groups.default.collect_lua_garbage: the vshard setting is not translated",
+		"",
+	}, "\n"), report.String())
+}
+
+func TestMigrateCartridge_failover(t *testing.T) {
+	cases := []struct {
+		name     string
+		failover any
+		allRW    bool
+		expected string
+		report   string
+	}{
+		{
+			name:     "disabled",
+			failover: nil,
+			expected: "failover: manual",
+		},
+		{
+			name:     "eventual",
+			failover: true,
+			expected: "failover: election",
+			report: "topology.failover.mode: the eventual failover is translated to " +
+				"the election failover\n",
+		},
+		{
+			name:     "raft",
+			failover: map[string]any{"mode": "raft"},
+			expected: "failover: election",
+		},
+		{
+			name:     "all_rw",
+			failover: false,
+			allRW:    true,
+			expected: "mode: rw",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := replicaset.CartridgeConfig{
+				Config: map[string]any{
+					"topology": map[string]any{
+						"servers": map[string]any{
+							"uuid-a": map[string]any{"uri": "a:3301", "replicaset_uuid": "rs"},
+						},
+						"replicasets": map[string]any{
+							"rs": map[string]any{
+								"alias":  "rs",
+								"roles":  []any{},
+								"master": []any{"uuid-a"},
+								"all_rw": tc.allRW,
+							},
+						},
+						"failover": tc.failover,
+					},
+				},
+				Aliases: map[string]string{"uuid-a": "a"},
+				Cookie:  "cookie",
+			}
+
+			var buf, report bytes.Buffer
+			migrateCtx := MigrateCartridgeCtx{Writer: &buf, ReportWriter: &report}
+			require.NoError(t, MigrateCartridge(migrateCtx, source))
+			assert.Contains(t, buf.String(), tc.expected)
+			assert.Equal(t, tc.report, report.String())
+			if tc.allRW {
+				assert.NotContains(t, buf.String(), "leader:")
+			} else if tc.expected == "failover: manual" {
+				assert.Contains(t, buf.String(), "leader: a")
+			}
+		})
+	}
+}
+
+func TestMigrateCartridge_fromAppFiles(t *testing.T) {
+	var buf, report bytes.Buffer
+	migrateCtx := MigrateCartridgeCtx{Writer: &buf, ReportWriter: &report}
+	require.NoError(t, MigrateCartridge(migrateCtx, replicaset.CartridgeConfig{
+		Config:       map[string]any{"topology": map[string]any{}},
+		FromAppFiles: true,
+	}))
+	assert.Contains(t, report.String(), "config: the clusterwide configuration is not found")
+	assert.Contains(t, report.String(), "cluster_cookie: the cluster cookie is unknown")
+}
@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/apex/log"
+
+	"github.com/tarantool/tt/cli/util"
 	libcluster "github.com/tarantool/tt/lib/cluster"
 	"github.com/tarantool/tt/lib/connect"
 )
@@ -29,10 +33,39 @@ type PublishCtx struct {
 	Group string
 	// Replicaset is a replicaset name for a new instance configuration publishing.
 	Replicaset string
+	// Diff defines whether a difference between the current and the published
+	// configurations is printed before publishing.
+	Diff bool
+	// Confirm defines whether a confirmation is asked before publishing. It
+	// implies Diff.
+	Confirm bool
+	// Secrets defines resolvers to check secret references before
+	// publishing. The references are published as is into a file.
+	Secrets libcluster.SecretResolvers
+	// ResolveSecrets defines whether secret references are replaced with
+	// values of the secrets before publishing. Instances can not resolve
+	// references from a remote storage, so a configuration with references
+	// is published into the storage only with the resolved values.
+	ResolveSecrets bool
+}
+
+// askConfirm asks the user for a confirmation.
+var askConfirm = func(question string) (bool, error) {
+	return util.AskConfirm(os.Stdin, question)
 }
 
 // PublishUri publishes a configuration to URI.
 func PublishUri(publishCtx PublishCtx, opts connect.UriOpts) error {
+	if libcluster.HasSecrets(publishCtx.Src) && !publishCtx.ResolveSecrets {
+		return fmt.Errorf("the configuration contains secret references, instances " +
+			"can not resolve them from a config storage: use --resolve-secrets to " +
+			"publish values of the secrets")
+	}
+	publishCtx, err := publishCtxResolveSecrets(publishCtx)
+	if err != nil {
+		return err
+	}
+
 	instance := opts.Params["name"]
 	if err := publishCtxValidateConfig(publishCtx, instance); err != nil {
 		return err
@@ -53,15 +86,18 @@ func PublishUri(publishCtx PublishCtx, opts connect.UriOpts) error {
 
 	if instance == "" {
 		// The easy case, just publish the configuration as is.
-		return publisher.Publish(0, publishCtx.Src)
+		return publishSource(publishCtx, collector, publisher)
 	}
 
-	return setInstanceConfig(publishCtx.Group, publishCtx.Replicaset, instance,
-		publishCtx.Config, collector, publisher)
+	return setInstanceConfig(publishCtx, instance, collector, publisher)
 }
 
 // PublishCluster publishes a configuration to the configuration path.
 func PublishCluster(publishCtx PublishCtx, path, instance string) error {
+	publishCtx, err := publishCtxResolveSecrets(publishCtx)
+	if err != nil {
+		return err
+	}
 	if err := publishCtxValidateConfig(publishCtx, instance); err != nil {
 		return err
 	}
@@ -71,34 +107,104 @@ func PublishCluster(publishCtx PublishCtx, path, instance string) error {
 		return fmt.Errorf("failed to create a file publisher: %w", err)
 	}
 
+	collector, err := publishCtx.Collectors.NewFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to create a file collector: %w", err)
+	}
+
 	if instance == "" {
 		// The easy case, just publish the configuration as is.
-		return publisher.Publish(0, publishCtx.Src)
+		return publishSource(publishCtx, collector, publisher)
 	}
 
-	collector, err := publishCtx.Collectors.NewFile(path)
+	return setInstanceConfig(publishCtx, instance, collector, publisher)
+}
+
+// publishCtxResolveSecrets replaces secret references in the source
+// configuration from the publish context with values of the secrets if it is
+// required.
+func publishCtxResolveSecrets(publishCtx PublishCtx) (PublishCtx, error) {
+	if !publishCtx.ResolveSecrets || !libcluster.HasSecrets(publishCtx.Src) {
+		return publishCtx, nil
+	}
+	config, err := libcluster.ResolveSecrets(publishCtx.Config, publishCtx.Secrets)
 	if err != nil {
-		return fmt.Errorf("failed to create a file collector: %w", err)
+		return publishCtx, fmt.Errorf("failed to resolve secret references: %w", err)
 	}
-
-	return setInstanceConfig(publishCtx.Group, publishCtx.Replicaset, instance,
-		publishCtx.Config, collector, publisher)
+	publishCtx.Config = config
+	publishCtx.Src = []byte(config.String())
+	return publishCtx, nil
 }
 
 // publishCtxValidateConfig validates a source configuration from the publish
-// context.
+// context. Secret references are resolved to validate the values, but
+// the source configuration is not modified.
 func publishCtxValidateConfig(publishCtx PublishCtx, instance string) error {
-	if !publishCtx.Force {
-		return validateRawConfig(publishCtx.Config, instance)
+	if publishCtx.Force {
+		return nil
 	}
-	return nil
+	config := publishCtx.Config
+	if publishCtx.Secrets != nil {
+		var err error
+		if config, err = libcluster.ResolveSecrets(config, publishCtx.Secrets); err != nil {
+			return fmt.Errorf("failed to check secret references: %w", err)
+		}
+	}
+	return validateRawConfig(config, instance)
+}
+
+// confirmPublish prints a difference between the current and the new
+// configurations and asks for a confirmation if it is required by the publish
+// context. It returns false if the configuration should not be published.
+func confirmPublish(publishCtx PublishCtx, current, config *libcluster.Config) (bool, error) {
+	if !publishCtx.Diff && !publishCtx.Confirm {
+		return true, nil
+	}
+
+	entries := libcluster.Diff(current, config)
+	if len(entries) == 0 {
+		log.Info("The configuration is up to date, nothing to publish.")
+		return false, nil
+	}
+	if err := PrintDiff(os.Stdout, entries, DiffFormatText); err != nil {
+		return false, err
+	}
+	if !publishCtx.Confirm {
+		return true, nil
+	}
+
+	confirmed, err := askConfirm("Publish the configuration?")
+	if err != nil {
+		return false, fmt.Errorf("failed to get a confirmation: %w", err)
+	}
+	if !confirmed {
+		log.Info("Publishing is canceled.")
+	}
+	return confirmed, nil
+}
+
+// publishSource publishes the source configuration as is.
+func publishSource(publishCtx PublishCtx, collector libcluster.Collector,
+	publisher libcluster.DataPublisher,
+) error {
+	if publishCtx.Diff || publishCtx.Confirm {
+		current, err := collectCurrent(collector)
+		if err != nil {
+			return fmt.Errorf("failed to get a current configuration: %w", err)
+		}
+		if ok, err := confirmPublish(publishCtx, current, publishCtx.Config); !ok {
+			return err
+		}
+	}
+	return publisher.Publish(0, publishCtx.Src)
 }
 
 // setInstanceConfig sets an instance configuration in the collected
 // cluster configuration and republishes it.
-func setInstanceConfig(group, replicaset, instance string, config *libcluster.Config,
+func setInstanceConfig(publishCtx PublishCtx, instance string,
 	collector libcluster.Collector, publisher libcluster.DataPublisher,
 ) error {
+	group, replicaset := publishCtx.Group, publishCtx.Replicaset
 	src, err := collector.Collect()
 	if err != nil {
 		return fmt.Errorf("failed to get a cluster configuration to update "+
@@ -119,11 +225,15 @@ func setInstanceConfig(group, replicaset, instance string, config *libcluster.Co
 		if group != "" && group != gname {
 			return fmt.Errorf("wrong group name, expected %q, have %q", gname, group)
 		}
-		cconfig, err = libcluster.ReplaceInstanceConfig(cconfig, instance, config)
+		cconfig, err = libcluster.ReplaceInstanceConfig(cconfig, instance,
+			publishCtx.Config)
 		if err != nil {
 			return fmt.Errorf("failed to replace an instance %q configuration "+
 				"in a cluster configuration: %w", instance, err)
 		}
+		if ok, err := confirmPublish(publishCtx, src, cconfig.RawConfig); !ok {
+			return err
+		}
 		return libcluster.NewYamlConfigPublisher(publisher).Publish(cconfig.RawConfig)
 	}
 
@@ -140,10 +250,13 @@ func setInstanceConfig(group, replicaset, instance string, config *libcluster.Co
 		}
 	}
 	cconfig, err = libcluster.SetInstanceConfig(cconfig, group, replicaset,
-		instance, config)
+		instance, publishCtx.Config)
 	if err != nil {
 		return fmt.Errorf("failed to set an instance %q configuration: %w", instance, err)
 	}
+	if ok, err := confirmPublish(publishCtx, src, cconfig.RawConfig); !ok {
+		return err
+	}
 
 	return libcluster.NewYamlConfigPublisher(publisher).Publish(cconfig.RawConfig)
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+	"github.com/tarantool/tt/lib/connect"
+)
+
+func TestPublishCluster_secrets(t *testing.T) {
+	const config = `credentials:
+  users:
+    replicator:
+      password: '{{ secret "env:TT_TEST_PUBLISH_SECRET" }}'
+      roles: [replication]
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	publishCtx := PublishCtx{
+		Publishers: libcluster.NewDataPublisherFactory(),
+		Collectors: libcluster.NewCollectorFactory(libcluster.NewDataCollectorFactory()),
+		Src:        []byte(config),
+		Config:     collectYaml(t, config),
+		Secrets:    libcluster.NewSecretResolvers(),
+	}
+
+	err := PublishCluster(publishCtx, path, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check secret references")
+	assert.NoFileExists(t, path)
+
+	// The references are published as is.
+	t.Setenv("TT_TEST_PUBLISH_SECRET", "secret")
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, config, string(data))
+
+	// The references are not checked with the force flag.
+	os.Unsetenv("TT_TEST_PUBLISH_SECRET")
+	publishCtx.Force = true
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+}
+
+func TestPublishUri_secrets(t *testing.T) {
+	const config = `credentials:
+  users:
+    replicator:
+      password: '{{ secret "env:TT_TEST_PUBLISH_SECRET" }}'
+      roles: [replication]
+`
+	publishCtx := PublishCtx{
+		Src:     []byte(config),
+		Config:  collectYaml(t, config),
+		Secrets: libcluster.NewSecretResolvers(),
+	}
+	opts, err := connect.CreateUriOpts("http://localhost:2379/prefix")
+	require.NoError(t, err)
+
+	// Instances can not resolve the references from a remote storage.
+	err = PublishUri(publishCtx, opts)
+	assert.EqualError(t, err, "the configuration contains secret references, instances "+
+		"can not resolve them from a config storage: use --resolve-secrets to "+
+		"publish values of the secrets")
+
+	publishCtx.ResolveSecrets = true
+	_, err = publishCtxResolveSecrets(publishCtx)
+	assert.ErrorContains(t, err, "failed to resolve secret references")
+
+	t.Setenv("TT_TEST_PUBLISH_SECRET", "secret")
+	resolved, err := publishCtxResolveSecrets(publishCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "credentials:\n  users:\n    replicator:\n      password: secret\n"+
+		"      roles:\n        - replication\n", string(resolved.Src))
+	assert.Equal(t, config, string(publishCtx.Src))
+}
+
+func TestPublishCluster_resolveSecrets(t *testing.T) {
+	const config = `credentials:
+  users:
+    replicator:
+      password: '{{ secret "env:TT_TEST_PUBLISH_SECRET" }}'
+`
+	t.Setenv("TT_TEST_PUBLISH_SECRET", "secret")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	publishCtx := PublishCtx{
+		Publishers:     libcluster.NewDataPublisherFactory(),
+		Collectors:     libcluster.NewCollectorFactory(libcluster.NewDataCollectorFactory()),
+		Src:            []byte(config),
+		Config:         collectYaml(t, config),
+		Secrets:        libcluster.NewSecretResolvers(),
+		ResolveSecrets: true,
+		Force:          true,
+	}
+	require.NoError(t, PublishCluster(publishCtx, path, ""))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "credentials:\n  users:\n    replicator:\n      password: secret\n",
+		string(data))
+}
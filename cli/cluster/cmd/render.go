@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/tarantool/tt/cli/cluster"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// RenderCtx contains information about cluster render command execution
+// context.
+type RenderCtx struct {
+	// Opts defines overlays and variables to render a configuration.
+	Opts cluster.RenderOpts
+	// Collectors defines a used data collectors factory.
+	Collectors libcluster.DataCollectorFactory
+	// Validate defines whether the command will check the rendered
+	// configuration.
+	Validate bool
+	// Reveal defines whether secret references are resolved and values of
+	// the secrets are printed.
+	Reveal bool
+	// Writer is a writer to print the rendered configuration to.
+	Writer io.Writer
+}
+
+// Render renders a cluster configuration from the base configuration file
+// and prints it.
+func Render(renderCtx RenderCtx, path string) error {
+	opts := renderCtx.Opts
+	if renderCtx.Reveal {
+		opts.Secrets = cluster.NewSecretResolvers()
+	}
+	data, config, err := cluster.RenderConfig(renderCtx.Collectors, path, opts)
+	if err != nil {
+		return err
+	}
+	if renderCtx.Validate {
+		if err := validateRawConfig(config, ""); err != nil {
+			return err
+		}
+	}
+
+	_, err = renderCtx.Writer.Write(data)
+	return err
+}
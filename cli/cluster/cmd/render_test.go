@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/cluster"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	overlay := filepath.Join(dir, "prod.yaml")
+	require.NoError(t, os.WriteFile(base, []byte(`groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            iproto:
+              listen:
+              - uri: localhost:3301
+`), 0o644))
+	require.NoError(t, os.WriteFile(overlay, []byte(`groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            iproto:
+              listen:
+              - uri: ${RENDER_HOST}:3301
+`), 0o644))
+	t.Setenv("RENDER_HOST", "prod.example.com")
+
+	var buf bytes.Buffer
+	renderCtx := RenderCtx{
+		Opts:       cluster.RenderOpts{Overlays: []string{overlay}},
+		Collectors: libcluster.NewDataCollectorFactory(),
+		Validate:   true,
+		Writer:     &buf,
+	}
+	require.NoError(t, Render(renderCtx, base))
+	assert.Equal(t, `groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            iproto:
+              listen:
+                - uri: prod.example.com:3301
+`, buf.String())
+
+	require.NoError(t, os.WriteFile(overlay, []byte("iproto:\n  listen: 3301\n"), 0o644))
+	buf.Reset()
+	err := Render(renderCtx, base)
+	require.Error(t, err)
+	assert.Empty(t, buf.String())
+
+	renderCtx.Validate = false
+	require.NoError(t, Render(renderCtx, base))
+	assert.Contains(t, buf.String(), "listen: 3301")
+}
+
+func TestRender_reveal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`password: '{{ secret "env:TT_TEST_RENDER_SECRET" }}'`+"\n"), 0o644))
+	t.Setenv("TT_TEST_RENDER_SECRET", "secret")
+
+	var buf bytes.Buffer
+	renderCtx := RenderCtx{
+		Collectors: libcluster.NewDataCollectorFactory(),
+		Writer:     &buf,
+	}
+	require.NoError(t, Render(renderCtx, path))
+	assert.Equal(t, `password: '{{ secret "env:TT_TEST_RENDER_SECRET" }}'`+"\n", buf.String())
+
+	buf.Reset()
+	renderCtx.Reveal = true
+	require.NoError(t, Render(renderCtx, path))
+	assert.Equal(t, "password: secret\n", buf.String())
+}
@@ -57,6 +57,24 @@ func makeEtcdPublisher(factory libcluster.DataPublisherFactory,
 	})
 }
 
+// makeConsulPublisher creates publisher that publishes into Consul.
+func makeConsulPublisher(factory libcluster.DataPublisherFactory,
+	client *libcluster.ConsulClient, prefix string, timeout time.Duration,
+) replicaset.DataPublisher {
+	return dataKeyPublisher(func(key string, revision int64, data []byte) error {
+		var err error
+		key, err = libcluster.GetStorageKey(prefix, key)
+		if err != nil {
+			return err
+		}
+		publisher, err := factory.NewConsul(client, prefix, key, timeout)
+		if err != nil {
+			return err
+		}
+		return publisher.Publish(revision, data)
+	})
+}
+
 // PromoteCtx describes the context to promote an instance.
 type PromoteCtx struct {
 	// InstName is an instance name to promote.
@@ -147,8 +165,27 @@ func createDataCollectorAndKeyPublisher(
 		}
 		return nil
 	}
+	consulFunc := func(client *libcluster.ConsulClient) error {
+		closeFunc = func() { client.Close() }
+		if collectors != nil {
+			collector, err = collectors.NewConsul(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create consul collector: %w", err)
+			}
+		}
+		if publishers != nil {
+			publisher = makeConsulPublisher(publishers, client, prefix, timeout)
+		}
+		return nil
+	}
 
-	if err := libcluster.DoOnStorage(connOpts, opts, tarantoolFunc, etcdFunc); err != nil {
+	err = libcluster.DoOnStorageFuncs(connOpts, opts, libcluster.StorageFuncs{
+		Tarantool: tarantoolFunc,
+		Etcd:      etcdFunc,
+		Consul:    consulFunc,
+	})
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
@@ -19,6 +19,18 @@ type ShowCtx struct {
 	// Validate defines whether the command will check the showed
 	// configuration.
 	Validate bool
+	// Reveal defines whether secret references are resolved and values of
+	// the secrets are printed.
+	Reveal bool
+}
+
+// showCollectors returns collectors to show a configuration.
+func showCollectors(showCtx ShowCtx) libcluster.CollectorFactory {
+	if showCtx.Reveal {
+		return libcluster.NewSecretCollectorFactory(showCtx.Collectors,
+			cluster.NewSecretResolvers())
+	}
+	return showCtx.Collectors
 }
 
 // ShowUri shows a configuration from URI.
@@ -29,7 +41,7 @@ func ShowUri(showCtx ShowCtx, opts connect.UriOpts) error {
 	}
 	_, collector, cancel, err := createPublisherAndCollector(
 		nil,
-		showCtx.Collectors,
+		showCollectors(showCtx),
 		connOpts, opts)
 	if err != nil {
 		return err
@@ -53,7 +65,7 @@ func ShowUri(showCtx ShowCtx, opts connect.UriOpts) error {
 
 // ShowCluster shows a full cluster configuration for a configuration path.
 func ShowCluster(showCtx ShowCtx, path, name string) error {
-	config, err := cluster.GetClusterConfig(showCtx.Collectors, path)
+	config, err := cluster.GetClusterConfig(showCollectors(showCtx), path)
 	if err != nil {
 		return fmt.Errorf("failed to get a cluster configuration: %w", err)
 	}
@@ -1 +1,428 @@
 package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Graph formats of a cluster topology.
+const (
+	// TopologyFormatDot is a Graphviz DOT format.
+	TopologyFormatDot = "dot"
+	// TopologyFormatMermaid is a Mermaid flowchart format.
+	TopologyFormatMermaid = "mermaid"
+	// TopologyFormatSvg is an SVG image format.
+	TopologyFormatSvg = "svg"
+)
+
+// upstreamStatusFollow is a status of a healthy replication upstream.
+const upstreamStatusFollow = "follow"
+
+// TopologyGraph is a cluster topology to render as a graph.
+type TopologyGraph struct {
+	// Groups are groups of the cluster.
+	Groups []TopologyGroup
+}
+
+// TopologyGroup is a group of replicasets.
+type TopologyGroup struct {
+	// Name is a name of the group.
+	Name string
+	// Replicasets are replicasets of the group.
+	Replicasets []TopologyReplicaset
+}
+
+// TopologyReplicaset is a replicaset of instances.
+type TopologyReplicaset struct {
+	// Name is a name of the replicaset.
+	Name string
+	// Instances are instances of the replicaset.
+	Instances []TopologyInstance
+}
+
+// TopologyInstance is an instance of a replicaset.
+type TopologyInstance struct {
+	// Name is a name of the instance.
+	Name string
+	// UUID is an UUID of the instance if it is known.
+	UUID string
+	// Hostname is a hostname of the instance if it is known.
+	Hostname string
+	// Mode is a mode of the instance: rw, ro or unknown.
+	Mode string
+	// Roles are roles of the instance.
+	Roles []string
+	// Sharding are sharding roles of the instance.
+	Sharding []string
+	// Leader is true if the instance is a leader of the replicaset.
+	Leader bool
+	// Reachable is true if the instance is reachable.
+	Reachable bool
+	// Upstreams are replication upstreams of the instance. It is empty if
+	// the instance is not reachable.
+	Upstreams []TopologyUpstream
+}
+
+// TopologyUpstream is a replication upstream of an instance.
+type TopologyUpstream struct {
+	// Peer is a name of the upstream instance.
+	Peer string
+	// PeerUUID is an UUID of the upstream instance.
+	PeerUUID string
+	// Status is a status of the upstream: follow, disconnected and so on.
+	Status string
+}
+
+// topologyEdge is a replication edge from a leader to a replica.
+type topologyEdge struct {
+	// from is a name of the leader.
+	from string
+	// to is a name of the replica.
+	to string
+	// status is a status of the replica upstream from the leader or empty if
+	// it is unknown.
+	status string
+}
+
+// upstreamStatus returns a status of the instance upstream from the peer or
+// an empty string if it is unknown.
+func (inst TopologyInstance) upstreamStatus(peer TopologyInstance) string {
+	for _, upstream := range inst.Upstreams {
+		if upstream.Peer == peer.Name || peer.UUID != "" && upstream.PeerUUID == peer.UUID {
+			return upstream.Status
+		}
+	}
+	return ""
+}
+
+// label returns lines of a label of the instance.
+func (inst TopologyInstance) label() []string {
+	lines := []string{inst.Name}
+	status := inst.Mode
+	if inst.Leader {
+		status += ", leader"
+	}
+	if !inst.Reachable {
+		status += ", not reachable"
+	}
+	lines = append(lines, status)
+	if inst.Hostname != "" {
+		lines = append(lines, "host: "+inst.Hostname)
+	}
+	if len(inst.Roles) > 0 {
+		lines = append(lines, "roles: "+strings.Join(inst.Roles, ", "))
+	}
+	if len(inst.Sharding) > 0 {
+		lines = append(lines, "sharding: "+strings.Join(inst.Sharding, ", "))
+	}
+	return lines
+}
+
+// edges returns replication edges from leaders to replicas of the
+// replicaset.
+func (rs TopologyReplicaset) edges() []topologyEdge {
+	var edges []topologyEdge
+	for _, leader := range rs.Instances {
+		if !leader.Leader {
+			continue
+		}
+		for _, inst := range rs.Instances {
+			if inst.Name == leader.Name {
+				continue
+			}
+			edges = append(edges, topologyEdge{
+				from:   leader.Name,
+				to:     inst.Name,
+				status: inst.upstreamStatus(leader),
+			})
+		}
+	}
+	return edges
+}
+
+// RenderTopology writes the topology graph in the format.
+func RenderTopology(w io.Writer, graph TopologyGraph, format string) error {
+	var out string
+	switch format {
+	case TopologyFormatDot:
+		out = renderTopologyDot(graph)
+	case TopologyFormatMermaid:
+		out = renderTopologyMermaid(graph)
+	case TopologyFormatSvg:
+		out = renderTopologySvg(graph)
+	default:
+		return fmt.Errorf("unsupported topology graph format: %s", format)
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// dotQuote returns a quoted DOT string.
+func dotQuote(str string) string {
+	str = strings.ReplaceAll(str, `\`, `\\`)
+	str = strings.ReplaceAll(str, `"`, `\"`)
+	return `"` + strings.ReplaceAll(str, "\n", `\n`) + `"`
+}
+
+// edgeColor returns a color of an edge with the upstream status.
+func edgeColor(status string) string {
+	switch status {
+	case "":
+		return "gray"
+	case upstreamStatusFollow:
+		return "darkgreen"
+	}
+	return "red"
+}
+
+// renderTopologyDot renders the topology graph in the DOT format.
+func renderTopologyDot(graph TopologyGraph) string {
+	var b strings.Builder
+	var edges []topologyEdge
+
+	b.WriteString("digraph topology {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white];\n")
+	for _, group := range graph.Groups {
+		fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_group_"+group.Name))
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote("group: "+group.Name))
+		for _, rs := range group.Replicasets {
+			fmt.Fprintf(&b, "    subgraph %s {\n", dotQuote("cluster_replicaset_"+rs.Name))
+			fmt.Fprintf(&b, "      label=%s;\n", dotQuote("replicaset: "+rs.Name))
+			for _, inst := range rs.Instances {
+				attrs := []string{"label=" + dotQuote(strings.Join(inst.label(), "\n"))}
+				if inst.Leader {
+					attrs = append(attrs, `fillcolor="#c8e6c9"`)
+				}
+				if !inst.Reachable {
+					attrs = append(attrs, `style="rounded,dashed"`, "fontcolor=gray")
+				}
+				fmt.Fprintf(&b, "      %s [%s];\n", dotQuote(inst.Name),
+					strings.Join(attrs, ", "))
+			}
+			b.WriteString("    }\n")
+			edges = append(edges, rs.edges()...)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, edge := range edges {
+		attrs := []string{"color=" + edgeColor(edge.status)}
+		if edge.status == "" {
+			attrs = append(attrs, "style=dashed")
+		} else {
+			attrs = append(attrs, "label="+dotQuote(edge.status))
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.from), dotQuote(edge.to),
+			strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidQuote returns a quoted Mermaid label.
+func mermaidQuote(str string) string {
+	return `"` + strings.ReplaceAll(str, `"`, "#quot;") + `"`
+}
+
+// renderTopologyMermaid renders the topology graph as a Mermaid flowchart.
+func renderTopologyMermaid(graph TopologyGraph) string {
+	var (
+		b           strings.Builder
+		edges       []topologyEdge
+		ids         = map[string]string{}
+		leaders     []string
+		unreachable []string
+	)
+
+	b.WriteString("flowchart LR\n")
+	for i, group := range graph.Groups {
+		fmt.Fprintf(&b, "  subgraph g%d[%s]\n", i, mermaidQuote("group: "+group.Name))
+		for j, rs := range group.Replicasets {
+			fmt.Fprintf(&b, "    subgraph g%d_r%d[%s]\n", i, j,
+				mermaidQuote("replicaset: "+rs.Name))
+			for _, inst := range rs.Instances {
+				id := fmt.Sprintf("i%d", len(ids))
+				ids[inst.Name] = id
+				fmt.Fprintf(&b, "      %s[%s]\n", id,
+					mermaidQuote(strings.Join(inst.label(), "<br/>")))
+				if inst.Leader {
+					leaders = append(leaders, id)
+				}
+				if !inst.Reachable {
+					unreachable = append(unreachable, id)
+				}
+			}
+			b.WriteString("    end\n")
+			edges = append(edges, rs.edges()...)
+		}
+		b.WriteString("  end\n")
+	}
+	for _, edge := range edges {
+		if edge.status == "" {
+			fmt.Fprintf(&b, "  %s -.-> %s\n", ids[edge.from], ids[edge.to])
+		} else {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[edge.from],
+				mermaidQuote(edge.status), ids[edge.to])
+		}
+	}
+	for i, edge := range edges {
+		fmt.Fprintf(&b, "  linkStyle %d stroke:%s\n", i, edgeColor(edge.status))
+	}
+	b.WriteString("  classDef leader fill:#c8e6c9\n")
+	b.WriteString("  classDef unreachable stroke-dasharray:5 5,color:gray\n")
+	if len(leaders) > 0 {
+		fmt.Fprintf(&b, "  class %s leader\n", strings.Join(leaders, ","))
+	}
+	if len(unreachable) > 0 {
+		fmt.Fprintf(&b, "  class %s unreachable\n", strings.Join(unreachable, ","))
+	}
+	return b.String()
+}
+
+// Sizes of SVG topology elements.
+const (
+	svgMargin       = 20
+	svgPadding      = 10
+	svgGap          = 20
+	svgHeader       = 24
+	svgLineHeight   = 16
+	svgNodeWidth    = 240
+	svgEdgeGutter   = 100
+	svgCharWidth    = 7
+	svgFontSize     = 12
+	svgHeaderOffset = 17
+)
+
+// svgPoint is a point of an SVG image.
+type svgPoint struct {
+	x, y int
+}
+
+// svgNodeHeight returns a height of an instance node.
+func svgNodeHeight(inst TopologyInstance) int {
+	return len(inst.label())*svgLineHeight + svgPadding
+}
+
+// svgReplicasetNodeWidth returns a width of instance nodes of the replicaset.
+func svgReplicasetNodeWidth(rs TopologyReplicaset) int {
+	width := svgNodeWidth
+	for _, inst := range rs.Instances {
+		for _, line := range inst.label() {
+			width = max(width, len(line)*svgCharWidth+2*svgPadding)
+		}
+	}
+	return width
+}
+
+// svgText writes a text element.
+func svgText(b *strings.Builder, x, y int, attrs, text string) {
+	fmt.Fprintf(b, `<text x="%d" y="%d"%s>%s</text>`+"\n", x, y, attrs,
+		html.EscapeString(text))
+}
+
+// renderTopologySvg renders the topology graph as an SVG image. Groups are
+// placed one under another, replicasets of a group are placed in a row and
+// instances of a replicaset are placed in a column.
+func renderTopologySvg(graph TopologyGraph) string {
+	var (
+		body          strings.Builder
+		width, height = svgMargin, svgMargin
+	)
+
+	for _, group := range graph.Groups {
+		groupX, groupY := svgMargin, height
+		x, groupHeight := groupX+svgPadding, 0
+		var groupBody strings.Builder
+
+		for _, rs := range group.Replicasets {
+			nodeWidth := svgReplicasetNodeWidth(rs)
+			rsWidth := svgEdgeGutter + nodeWidth + svgPadding
+			rsY := groupY + svgHeader
+			y := rsY + svgHeader
+			nodeX := x + svgEdgeGutter
+
+			var nodes strings.Builder
+			anchors := map[string]svgPoint{}
+			for _, inst := range rs.Instances {
+				nodeHeight := svgNodeHeight(inst)
+				fill, stroke, dash, color := "white", "#333", "", "#000"
+				if inst.Leader {
+					fill = "#c8e6c9"
+				}
+				if !inst.Reachable {
+					stroke, dash, color = "gray", ` stroke-dasharray="5,5"`, "gray"
+				}
+				fmt.Fprintf(&nodes, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" `+
+					`fill="%s" stroke="%s"%s/>`+"\n",
+					nodeX, y, nodeWidth, nodeHeight, fill, stroke, dash)
+				for i, line := range inst.label() {
+					attrs := fmt.Sprintf(` fill="%s"`, color)
+					if i == 0 {
+						attrs += ` font-weight="bold"`
+					}
+					svgText(&nodes, nodeX+svgPadding, y+(i+1)*svgLineHeight, attrs, line)
+				}
+				anchors[inst.Name] = svgPoint{nodeX, y + nodeHeight/2}
+				y += nodeHeight + svgGap
+			}
+
+			var edges strings.Builder
+			for _, edge := range rs.edges() {
+				from, to := anchors[edge.from], anchors[edge.to]
+				color, dash := edgeColor(edge.status), ""
+				if edge.status == "" {
+					dash = ` stroke-dasharray="5,5"`
+				}
+				bend := from.x - svgEdgeGutter + svgPadding
+				fmt.Fprintf(&edges, `<path d="M %d %d C %d %d %d %d %d %d" fill="none" `+
+					`stroke="%s"%s marker-end="url(#arrow-%s)"/>`+"\n",
+					from.x, from.y, bend, from.y, bend, to.y, to.x, to.y, color, dash, color)
+				if edge.status != "" {
+					svgText(&edges, bend, (from.y+to.y)/2,
+						fmt.Sprintf(` fill="%s"`, color), edge.status)
+				}
+			}
+
+			rsHeight := y - rsY
+			fmt.Fprintf(&groupBody, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" `+
+				`fill="#f5f5f5" stroke="#999"/>`+"\n", x, rsY, rsWidth, rsHeight)
+			svgText(&groupBody, x+svgPadding, rsY+svgHeaderOffset, ` font-weight="bold"`,
+				"replicaset: "+rs.Name)
+			groupBody.WriteString(edges.String())
+			groupBody.WriteString(nodes.String())
+
+			x += rsWidth + svgGap
+			groupHeight = max(groupHeight, rsHeight)
+		}
+
+		groupWidth := max(x-svgGap+svgPadding-groupX, svgNodeWidth)
+		groupHeight += svgHeader + svgPadding
+		fmt.Fprintf(&body, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" `+
+			`fill="none" stroke="#666"/>`+"\n", groupX, groupY, groupWidth, groupHeight)
+		svgText(&body, groupX+svgPadding, groupY+svgHeaderOffset, ` font-weight="bold"`,
+			"group: "+group.Name)
+		body.WriteString(groupBody.String())
+
+		width = max(width, groupX+groupWidth)
+		height = groupY + groupHeight + svgGap
+	}
+	width += svgMargin
+	height += svgMargin - svgGap
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, svgFontSize)
+	b.WriteString("<defs>\n")
+	for _, color := range []string{"gray", "darkgreen", "red"} {
+		fmt.Fprintf(&b, `<marker id="arrow-%s" viewBox="0 0 10 10" refX="10" refY="5" `+
+			`markerWidth="6" markerHeight="6" orient="auto-start-reverse">`+
+			`<path d="M 0 0 L 10 5 L 0 10 z" fill="%s"/></marker>`+"\n", color, color)
+	}
+	b.WriteString("</defs>\n")
+	b.WriteString(body.String())
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/cluster/cmd"
+)
+
+// testTopologyGraph is a graph with a healthy, a broken and an unreachable
+// replica.
+var testTopologyGraph = cmd.TopologyGraph{
+	Groups: []cmd.TopologyGroup{
+		{
+			Name: "storages",
+			Replicasets: []cmd.TopologyReplicaset{
+				{
+					Name: "s-1",
+					Instances: []cmd.TopologyInstance{
+						{
+							Name:      "s-1-a",
+							UUID:      "uuid-a",
+							Hostname:  "host-a",
+							Mode:      "rw",
+							Roles:     []string{"app.metrics"},
+							Sharding:  []string{"storage"},
+							Leader:    true,
+							Reachable: true,
+						},
+						{
+							Name:      "s-1-b",
+							Mode:      "ro",
+							Reachable: true,
+							Upstreams: []cmd.TopologyUpstream{
+								{PeerUUID: "uuid-a", Status: "follow"},
+							},
+						},
+						{
+							Name:      "s-1-c",
+							Mode:      "ro",
+							Reachable: true,
+							Upstreams: []cmd.TopologyUpstream{
+								{Peer: "s-1-a", Status: "disconnected"},
+							},
+						},
+						{
+							Name: "s-1-d",
+							Mode: "unknown",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "routers",
+			Replicasets: []cmd.TopologyReplicaset{
+				{
+					Name: "r-1",
+					Instances: []cmd.TopologyInstance{
+						{Name: `r-1-"a"`, Mode: "unknown"},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestRenderTopology_dot(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, cmd.RenderTopology(&out, testTopologyGraph, cmd.TopologyFormatDot))
+	assert.Equal(t, `digraph topology {
+  rankdir=LR;
+  node [shape=box, style="rounded,filled", fillcolor=white];
+  subgraph "cluster_group_storages" {
+    label="group: storages";
+    subgraph "cluster_replicaset_s-1" {
+      label="replicaset: s-1";
+      "s-1-a" [label="s-1-a\nrw, leader\nhost: host-a\nroles: app.metrics\nsharding: storage", `+
+		`fillcolor="#c8e6c9"];
+      "s-1-b" [label="s-1-b\nro"];
+      "s-1-c" [label="s-1-c\nro"];
+      "s-1-d" [label="s-1-d\nunknown, not reachable", style="rounded,dashed", fontcolor=gray];
+    }
+  }
+  subgraph "cluster_group_routers" {
+    label="group: routers";
+    subgraph "cluster_replicaset_r-1" {
+      label="replicaset: r-1";
+      "r-1-\"a\"" [label="r-1-\"a\"\nunknown, not reachable", style="rounded,dashed", `+
+		`fontcolor=gray];
+    }
+  }
+  "s-1-a" -> "s-1-b" [color=darkgreen, label="follow"];
+  "s-1-a" -> "s-1-c" [color=red, label="disconnected"];
+  "s-1-a" -> "s-1-d" [color=gray, style=dashed];
+}
+`, out.String())
+}
+
+func TestRenderTopology_mermaid(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, cmd.RenderTopology(&out, testTopologyGraph, cmd.TopologyFormatMermaid))
+	assert.Equal(t, `flowchart LR
+  subgraph g0["group: storages"]
+    subgraph g0_r0["replicaset: s-1"]
+      i0["s-1-a<br/>rw, leader<br/>host: host-a<br/>roles: app.metrics<br/>sharding: storage"]
+      i1["s-1-b<br/>ro"]
+      i2["s-1-c<br/>ro"]
+      i3["s-1-d<br/>unknown, not reachable"]
+    end
+  end
+  subgraph g1["group: routers"]
+    subgraph g1_r0["replicaset: r-1"]
+      i4["r-1-#quot;a#quot;<br/>unknown, not reachable"]
+    end
+  end
+  i0 -->|"follow"| i1
+  i0 -->|"disconnected"| i2
+  i0 -.-> i3
+  linkStyle 0 stroke:darkgreen
+  linkStyle 1 stroke:red
+  linkStyle 2 stroke:gray
+  classDef leader fill:#c8e6c9
+  classDef unreachable stroke-dasharray:5 5,color:gray
+  class i0 leader
+  class i3,i4 unreachable
+`, out.String())
+}
+
+func TestRenderTopology_svg(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, cmd.RenderTopology(&out, testTopologyGraph, cmd.TopologyFormatSvg))
+
+	var texts []string
+	paths := 0
+	decoder := xml.NewDecoder(&out)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if elem.Name.Local == "path" {
+				paths++
+			}
+		case xml.CharData:
+			texts = append(texts, string(elem))
+		}
+	}
+	// 3 arrow markers and 3 edges.
+	assert.Equal(t, 6, paths)
+	for _, text := range []string{
+		"group: storages", "replicaset: s-1", "s-1-a", "rw, leader", "host: host-a",
+		"roles: app.metrics", "sharding: storage", "follow", "disconnected",
+		"unknown, not reachable", "group: routers", `r-1-"a"`,
+	} {
+		assert.Contains(t, texts, text)
+	}
+}
+
+func TestRenderTopology_unsupported(t *testing.T) {
+	err := cmd.RenderTopology(io.Discard, testTopologyGraph, "png")
+	assert.EqualError(t, err, "unsupported topology graph format: png")
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+	libconnect "github.com/tarantool/tt/lib/connect"
+)
+
+const (
+	// watchEventAdded is an event of a new key.
+	watchEventAdded = "added"
+	// watchEventChanged is an event of a changed key.
+	watchEventChanged = "changed"
+	// watchEventDeleted is an event of a deleted key.
+	watchEventDeleted = "deleted"
+)
+
+// WatchCtx contains information about cluster watch command execution context.
+type WatchCtx struct {
+	// Username defines a username for connection.
+	Username string
+	// Password defines a password for connection.
+	Password string
+	// Format defines an output format: text or json.
+	Format string
+	// Hook is a shell command to run on each change.
+	Hook string
+	// Writer is a writer to print changes to.
+	Writer io.Writer
+}
+
+// watchRecord is a change of a key.
+type watchRecord struct {
+	// Time is a moment when the change is received.
+	Time time.Time `json:"time"`
+	// Key is a changed key.
+	Key string `json:"key"`
+	// Revision is a modification revision of the change.
+	Revision int64 `json:"revision,omitempty"`
+	// Event is a kind of the change: added, changed or deleted.
+	Event string `json:"event"`
+	// Diff is a difference with the previous value of the key.
+	Diff []libcluster.DiffEntry `json:"diff"`
+}
+
+// watchNow returns a current time.
+var watchNow = time.Now
+
+// parseWatchValue parses a configuration from a value of a key. A missing
+// value is an empty configuration.
+func parseWatchValue(key string, value []byte) (*libcluster.Config, error) {
+	if value == nil {
+		return libcluster.NewConfig(), nil
+	}
+	config, err := libcluster.NewYamlCollector(value).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse a value of key %q: %w", key, err)
+	}
+	return config, nil
+}
+
+// makeWatchRecord makes a record of the change with a difference from
+// the previous value.
+func makeWatchRecord(event libcluster.CSWatchEvent, prev []byte, known bool,
+) (watchRecord, error) {
+	record := watchRecord{
+		Time:     watchNow(),
+		Key:      event.Key,
+		Revision: event.Revision,
+		Event:    watchEventChanged,
+	}
+	switch {
+	case event.Deleted:
+		record.Event = watchEventDeleted
+	case !known:
+		record.Event = watchEventAdded
+	}
+
+	old, err := parseWatchValue(event.Key, prev)
+	if err != nil {
+		return record, err
+	}
+	new, err := parseWatchValue(event.Key, event.Value)
+	if err != nil {
+		return record, err
+	}
+	record.Diff = libcluster.Diff(old, new)
+	if record.Diff == nil {
+		record.Diff = []libcluster.DiffEntry{}
+	}
+	return record, nil
+}
+
+// printWatchRecord prints the record in the format.
+func printWatchRecord(w io.Writer, record watchRecord, format string) error {
+	switch format {
+	case DiffFormatJSON:
+		// A record per line is convenient to append to an audit log.
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the change: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case DiffFormatText, "":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text or json)", format)
+	}
+
+	header := fmt.Sprintf("%s %s %s", record.Time.Format(time.RFC3339),
+		record.Key, record.Event)
+	if record.Revision != 0 {
+		header += fmt.Sprintf(", revision %d", record.Revision)
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return err
+	}
+	switch {
+	case record.Diff == nil:
+		// The difference is unavailable.
+		return nil
+	case len(record.Diff) == 0:
+		_, err := fmt.Fprintln(w, "  no changes")
+		return err
+	}
+	return PrintDiff(w, record.Diff, DiffFormatText)
+}
+
+// runWatchHook runs the hook command with a shell. The new value of the key
+// is passed to the standard input, the change is described with environment
+// variables.
+func runWatchHook(ctx context.Context, hook string, record watchRecord, value []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+	cmd.Env = append(os.Environ(),
+		"TT_CLUSTER_KEY="+record.Key,
+		"TT_CLUSTER_EVENT="+record.Event,
+		"TT_CLUSTER_REVISION="+strconv.FormatInt(record.Revision, 10),
+	)
+	cmd.Stdin = bytes.NewReader(value)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// watch prints changes from the channel until it is closed. The values are
+// current values of keys to compare changes with.
+func watch(ctx context.Context, watchCtx WatchCtx, values map[string][]byte,
+	events <-chan libcluster.CSWatchEvent,
+) error {
+	for event := range events {
+		prev, known := values[event.Key]
+		if event.Deleted {
+			delete(values, event.Key)
+		} else {
+			values[event.Key] = event.Value
+		}
+
+		record, err := makeWatchRecord(event, prev, known)
+		if err != nil {
+			log.Warnf("Unable to get a difference of a change: %s", err)
+		}
+		if err := printWatchRecord(watchCtx.Writer, record, watchCtx.Format); err != nil {
+			return err
+		}
+
+		if watchCtx.Hook != "" {
+			if err := runWatchHook(ctx, watchCtx.Hook, record, event.Value); err != nil {
+				log.Warnf("The hook command failed for key %q: %s", record.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// watchSnapshot reads current values of the key and starts watching for
+// changes after them.
+func watchSnapshot(ctx context.Context, conn libcluster.CSConnection, key string,
+	timeout time.Duration,
+) (map[string][]byte, <-chan libcluster.CSWatchEvent, error) {
+	getCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	values := map[string][]byte{}
+	if watcher, ok := conn.(libcluster.CSSnapshotWatcher); ok {
+		snapshot, err := watcher.Snapshot(getCtx, key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get current values: %w", err)
+		}
+		for _, value := range snapshot.Data {
+			values[value.Source] = value.Value
+		}
+		return values, watcher.WatchSince(ctx, key, snapshot), nil
+	}
+
+	data, err := conn.Get(getCtx, key)
+	// A missing key is not an error, it could be added later.
+	if err != nil && strings.HasSuffix(key, "/") {
+		return nil, nil, fmt.Errorf("failed to get current values: %w", err)
+	}
+	for _, value := range data {
+		values[value.Source] = value.Value
+	}
+	return values, conn.Watch(ctx, key), nil
+}
+
+// WatchUri prints changes of keys with a configuration prefix from URI until
+// the command is interrupted.
+func WatchUri(watchCtx WatchCtx, opts libconnect.UriOpts) error {
+	// Check the format before waiting for changes.
+	switch watchCtx.Format {
+	case DiffFormatText, DiffFormatJSON, "":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text or json)", watchCtx.Format)
+	}
+
+	connOpts := libcluster.ConnectOpts{
+		Username: watchCtx.Username,
+		Password: watchCtx.Password,
+	}
+	conn, err := libcluster.ConnectCStorage(opts, connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to connect to config storage: %w", err)
+	}
+	defer conn.Close()
+
+	// All keys with the prefix are watched without the 'key' argument.
+	key := libcluster.ConfigKey(opts.Prefix, opts.Params["key"])
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultEtcdTimeout
+	}
+	values, events, err := watchSnapshot(ctx, conn, key, timeout)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Watching for changes of %s, press Ctrl+C to stop.", key)
+	return watch(ctx, watchCtx, values, events)
+}
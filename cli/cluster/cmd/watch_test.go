@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// sendWatchEvents returns a closed channel with the events.
+func sendWatchEvents(events ...libcluster.CSWatchEvent) <-chan libcluster.CSWatchEvent {
+	ch := make(chan libcluster.CSWatchEvent, len(events))
+	for _, event := range events {
+		ch <- event
+	}
+	close(ch)
+	return ch
+}
+
+func stubWatchNow(t *testing.T) {
+	t.Helper()
+
+	origWatchNow := watchNow
+	t.Cleanup(func() { watchNow = origWatchNow })
+	watchNow = func() time.Time {
+		return time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	stubWatchNow(t)
+
+	values := map[string][]byte{"/tt/config/all": []byte(diffOldConfig)}
+	events := sendWatchEvents(
+		libcluster.CSWatchEvent{
+			Key:      "/tt/config/all",
+			Value:    []byte(diffNewConfig),
+			Revision: 5,
+		},
+		libcluster.CSWatchEvent{
+			Key:      "/tt/config/extra",
+			Value:    []byte("credentials:\n  users:\n    guest: {}\n"),
+			Revision: 6,
+		},
+		libcluster.CSWatchEvent{Key: "/tt/config/extra", Revision: 7, Deleted: true},
+		libcluster.CSWatchEvent{Key: "/tt/config/all", Value: []byte("[")},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, watch(context.Background(), WatchCtx{Writer: &buf}, values, events))
+	assert.Equal(t, `2026-10-18T10:00:00Z /tt/config/all changed, revision 5
+instance "s-001-a":
+  ~ iproto.listen: [{"uri":"localhost:3301"}] -> [{"uri":"localhost:3311"}]
+replicaset "s-001":
+  - instances.s-001-b: {}
+  + instances.s-001-c: {}
+global:
+  + replication: {"failover":"manual"}
+2026-10-18T10:00:00Z /tt/config/extra added, revision 6
+global:
+  + credentials: {"users":{"guest":{}}}
+2026-10-18T10:00:00Z /tt/config/extra deleted, revision 7
+global:
+  - credentials: {"users":{"guest":{}}}
+2026-10-18T10:00:00Z /tt/config/all changed
+`, buf.String())
+	assert.Equal(t, map[string][]byte{"/tt/config/all": []byte("[")}, values)
+}
+
+func TestWatch_json(t *testing.T) {
+	stubWatchNow(t)
+
+	values := map[string][]byte{"/tt/config/all": []byte(diffNewConfig)}
+	events := sendWatchEvents(libcluster.CSWatchEvent{
+		Key:      "/tt/config/all",
+		Value:    []byte(diffNewConfig + "# A comment.\n"),
+		Revision: 5,
+	})
+
+	var buf bytes.Buffer
+	watchCtx := WatchCtx{Writer: &buf, Format: DiffFormatJSON}
+	require.NoError(t, watch(context.Background(), watchCtx, values, events))
+	assert.JSONEq(t, `{
+		"time": "2026-10-18T10:00:00Z",
+		"key": "/tt/config/all",
+		"revision": 5,
+		"event": "changed",
+		"diff": []
+	}`, buf.String())
+
+	err := watch(context.Background(), WatchCtx{Writer: &buf, Format: "yaml"}, values,
+		sendWatchEvents(libcluster.CSWatchEvent{Key: "/tt/config/all"}))
+	assert.EqualError(t, err, "unsupported format: yaml (use text or json)")
+}
+
+func TestWatch_hook(t *testing.T) {
+	stubWatchNow(t)
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	events := sendWatchEvents(
+		libcluster.CSWatchEvent{Key: "/tt/config/all", Value: []byte("a: 1\n"), Revision: 5},
+		libcluster.CSWatchEvent{Key: "/tt/config/all", Revision: 6, Deleted: true},
+	)
+
+	var buf bytes.Buffer
+	watchCtx := WatchCtx{
+		Writer: &buf,
+		Hook: `echo "$TT_CLUSTER_KEY $TT_CLUSTER_EVENT $TT_CLUSTER_REVISION" >> ` + out +
+			` && cat >> ` + out + ` && exit 1`,
+	}
+	require.NoError(t, watch(context.Background(), watchCtx, map[string][]byte{}, events))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "/tt/config/all added 5\na: 1\n/tt/config/all deleted 6\n", string(data))
+}
+
+// mockWatchConn is a config storage connection that records calls.
+type mockWatchConn struct {
+	calls []string
+	data  []libcluster.Data
+}
+
+func (conn *mockWatchConn) Close() error {
+	return nil
+}
+
+func (conn *mockWatchConn) Get(ctx context.Context, key string) ([]libcluster.Data, error) {
+	conn.calls = append(conn.calls, "Get")
+	return conn.data, nil
+}
+
+func (conn *mockWatchConn) Put(ctx context.Context, key, value string) error {
+	return nil
+}
+
+func (conn *mockWatchConn) Watch(ctx context.Context,
+	key string,
+) <-chan libcluster.CSWatchEvent {
+	conn.calls = append(conn.calls, "Watch")
+	return sendWatchEvents()
+}
+
+// mockSnapshotWatchConn is a config storage connection that watches changes
+// since a snapshot and records calls.
+type mockSnapshotWatchConn struct {
+	mockWatchConn
+	since libcluster.CSSnapshot
+}
+
+func (conn *mockSnapshotWatchConn) Snapshot(ctx context.Context,
+	key string,
+) (libcluster.CSSnapshot, error) {
+	conn.calls = append(conn.calls, "Snapshot")
+	return libcluster.CSSnapshot{Data: conn.data, Revision: 7}, nil
+}
+
+func (conn *mockSnapshotWatchConn) WatchSince(ctx context.Context, key string,
+	snapshot libcluster.CSSnapshot,
+) <-chan libcluster.CSWatchEvent {
+	conn.calls = append(conn.calls, "WatchSince")
+	conn.since = snapshot
+	return sendWatchEvents()
+}
+
+func TestWatchSnapshot(t *testing.T) {
+	data := []libcluster.Data{{Source: "/tt/config/all", Value: []byte("a"), Revision: 5}}
+
+	conn := &mockSnapshotWatchConn{mockWatchConn: mockWatchConn{data: data}}
+	values, _, err := watchSnapshot(context.Background(), conn, "/tt/config/", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"/tt/config/all": []byte("a")}, values)
+	assert.Equal(t, []string{"Snapshot", "WatchSince"}, conn.calls)
+	assert.Equal(t, int64(7), conn.since.Revision)
+
+	// The values are read before the watching starts.
+	plain := &mockWatchConn{data: data}
+	values, _, err = watchSnapshot(context.Background(), plain, "/tt/config/", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"/tt/config/all": []byte("a")}, values)
+	assert.Equal(t, []string{"Get", "Watch"}, plain.calls)
+}
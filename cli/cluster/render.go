@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// RenderOpts describes how to render a cluster configuration.
+type RenderOpts struct {
+	// Overlays are paths to configuration files merged on top of a base
+	// configuration file in the order.
+	Overlays []string
+	// VarsFile is a path to a YAML file with variables. The variables from
+	// the file have a higher priority than environment variables.
+	VarsFile string
+	// Secrets resolve secret references in the rendered configuration if
+	// set. The references are kept as is otherwise.
+	Secrets libcluster.SecretResolvers
+}
+
+// IsSet returns true if the options require to render a configuration.
+func (opts RenderOpts) IsSet() bool {
+	return len(opts.Overlays) > 0 || opts.VarsFile != ""
+}
+
+// readData reads a whole data from a file with the data collectors.
+func readData(collectors libcluster.DataCollectorFactory, path string) ([]byte, error) {
+	collector, err := collectors.NewFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a file collector: %w", err)
+	}
+	data, err := collector.Collect()
+	if err != nil {
+		return nil, err
+	}
+	var ret []byte
+	for _, d := range data {
+		ret = append(ret, d.Value...)
+	}
+	return ret, nil
+}
+
+// readVars reads variables from a YAML file.
+func readVars(collectors libcluster.DataCollectorFactory, path string) (map[string]string, error) {
+	data, err := readData(collectors, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables from %q: %w", path, err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse variables from %q: %w", path, err)
+	}
+
+	vars := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch value.(type) {
+		case map[string]any, []any:
+			return nil, fmt.Errorf("variable %q from %q must be a scalar value", name, path)
+		case nil:
+			vars[name] = ""
+		default:
+			vars[name] = fmt.Sprint(value)
+		}
+	}
+	return vars, nil
+}
+
+// RenderConfig renders a cluster configuration from the base configuration
+// file and the overlay files. "${NAME}" references in string values of the
+// files are substituted with values of variables from the variables file or
+// from environment variables. Secret references are resolved if the resolvers are
+// set. It returns the rendered YAML and the configuration.
+func RenderConfig(collectors libcluster.DataCollectorFactory, path string,
+	opts RenderOpts,
+) ([]byte, *libcluster.Config, error) {
+	vars := map[string]string{}
+	if opts.VarsFile != "" {
+		var err error
+		if vars, err = readVars(collectors, opts.VarsFile); err != nil {
+			return nil, nil, err
+		}
+	}
+	lookup := func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	var configs []*libcluster.Config
+	for _, file := range append([]string{path}, opts.Overlays...) {
+		data, err := readData(collectors, file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read a configuration from %q: %w",
+				file, err)
+		}
+		config, err := libcluster.NewYamlCollector(data).Collect()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse a configuration from %q: %w",
+				file, err)
+		}
+		if config, err = libcluster.ExpandVars(config, lookup); err != nil {
+			return nil, nil, fmt.Errorf("failed to render a configuration from %q: %w",
+				file, err)
+		}
+		configs = append(configs, config)
+	}
+
+	config := libcluster.MergeOverlays(configs[0], configs[1:]...)
+	if opts.Secrets != nil {
+		var err error
+		if config, err = libcluster.ResolveSecrets(config, opts.Secrets); err != nil {
+			return nil, nil, fmt.Errorf("failed to render a configuration: %w", err)
+		}
+	}
+	return []byte(config.String()), config, nil
+}
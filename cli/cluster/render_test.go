@@ -0,0 +1,185 @@
+package cluster_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/cluster"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+// writeRenderFiles writes files into a temporary directory and returns
+// the directory.
+func writeRenderFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	return dir
+}
+
+func TestRenderConfig(t *testing.T) {
+	dir := writeRenderFiles(t, map[string]string{
+		"config.yaml": `credentials:
+  users:
+    admin:
+      password: ${ADMIN_PASSWORD}
+iproto:
+  listen:
+  - uri: ${HOST}:3301
+groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i: {}
+`,
+		"prod.yaml": `iproto:
+  listen:
+  - uri: ${HOST}:${PORT}
+log:
+  level: ${LOG_LEVEL}
+`,
+		"vars.yaml": `HOST: prod.example.com
+PORT: 3401
+`,
+	})
+	t.Setenv("HOST", "localhost")
+	t.Setenv("ADMIN_PASSWORD", "secret")
+	t.Setenv("LOG_LEVEL", "5")
+
+	collectors := libcluster.NewDataCollectorFactory()
+	data, config, err := cluster.RenderConfig(collectors, filepath.Join(dir, "config.yaml"),
+		cluster.RenderOpts{
+			Overlays: []string{filepath.Join(dir, "prod.yaml")},
+			VarsFile: filepath.Join(dir, "vars.yaml"),
+		})
+	require.NoError(t, err)
+	expected := `credentials:
+  users:
+    admin:
+      password: secret
+groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i: {}
+iproto:
+  listen:
+    - uri: prod.example.com:3401
+log:
+  level: "5"
+`
+	assert.Equal(t, expected, string(data))
+	assert.Equal(t, expected, config.String())
+
+	// Without the overlay and the variables file.
+	data, _, err = cluster.RenderConfig(collectors, filepath.Join(dir, "config.yaml"),
+		cluster.RenderOpts{})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "uri: localhost:3301")
+}
+
+func TestRenderConfig_errors(t *testing.T) {
+	dir := writeRenderFiles(t, map[string]string{
+		"config.yaml":    "a: ${RENDER_UNDEFINED}\n",
+		"invalid.yaml":   "a: [\n",
+		"valid.yaml":     "a: b\n",
+		"map_vars.yaml":  "A:\n  b: c\n",
+		"list_vars.yaml": "- a\n",
+	})
+	collectors := libcluster.NewDataCollectorFactory()
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	cases := []struct {
+		name string
+		path string
+		opts cluster.RenderOpts
+		err  string
+	}{
+		{
+			name: "undefined variable",
+			path: path("config.yaml"),
+			err: `failed to render a configuration from "` + path("config.yaml") +
+				`": undefined variables: RENDER_UNDEFINED`,
+		},
+		{
+			name: "invalid overlay",
+			path: path("valid.yaml"),
+			opts: cluster.RenderOpts{Overlays: []string{path("invalid.yaml")}},
+			err:  `failed to parse a configuration from "` + path("invalid.yaml") + `"`,
+		},
+		{
+			name: "missing overlay",
+			path: path("valid.yaml"),
+			opts: cluster.RenderOpts{Overlays: []string{path("missing.yaml")}},
+			err:  `failed to read a configuration from "` + path("missing.yaml") + `"`,
+		},
+		{
+			name: "not a scalar variable",
+			path: path("valid.yaml"),
+			opts: cluster.RenderOpts{VarsFile: path("map_vars.yaml")},
+			err: `variable "A" from "` + path("map_vars.yaml") +
+				`" must be a scalar value`,
+		},
+		{
+			name: "not a map of variables",
+			path: path("valid.yaml"),
+			opts: cluster.RenderOpts{VarsFile: path("list_vars.yaml")},
+			err:  `failed to parse variables from "` + path("list_vars.yaml") + `"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := cluster.RenderConfig(collectors, tc.path, tc.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestGetRenderedClusterConfig(t *testing.T) {
+	dir := writeRenderFiles(t, map[string]string{
+		"config.yaml": `groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            foo: 1
+`,
+		"prod.yaml": `groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            foo: ${FOO}
+`,
+	})
+	t.Setenv("FOO", "2")
+
+	config, err := cluster.GetRenderedClusterConfig(libcluster.NewDataCollectorFactory(),
+		filepath.Join(dir, "config.yaml"),
+		cluster.RenderOpts{Overlays: []string{filepath.Join(dir, "prod.yaml")}})
+	require.NoError(t, err)
+	require.Contains(t, config.Groups, "g")
+	require.Contains(t, config.Groups["g"].Replicasets, "r")
+	require.Contains(t, config.Groups["g"].Replicasets["r"].Instances, "i")
+	assert.Equal(t, "foo: \"2\"\n",
+		config.Groups["g"].Replicasets["r"].Instances["i"].RawConfig.String())
+
+	_, err = cluster.GetRenderedClusterConfig(libcluster.NewDataCollectorFactory(), "",
+		cluster.RenderOpts{})
+	assert.EqualError(t, err, "a configuration file must be set")
+}
@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"os"
+
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+const (
+	// vaultAddrEnv is an environment variable with an address of
+	// a Vault-compatible secret storage.
+	vaultAddrEnv = "VAULT_ADDR"
+	// vaultTokenEnv is an environment variable with a token for the storage.
+	vaultTokenEnv = "VAULT_TOKEN"
+	// vaultSecretScheme is a scheme of secret references to the storage.
+	vaultSecretScheme = "vault"
+)
+
+// NewSecretResolvers creates resolvers of secret references. The "file" and
+// the "env" schemes are always supported, the "vault" scheme is supported if
+// the VAULT_ADDR environment variable is set.
+func NewSecretResolvers() libcluster.SecretResolvers {
+	resolvers := libcluster.NewSecretResolvers()
+	if addr := os.Getenv(vaultAddrEnv); addr != "" {
+		resolvers[vaultSecretScheme] = libcluster.NewHTTPSecretResolver(addr,
+			os.Getenv(vaultTokenEnv))
+	}
+	return resolvers
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/apex/log"
@@ -32,6 +33,10 @@ var (
 	connectInteractive bool
 	connectBinary      bool
 	connectEvaler      string
+	connectRecord      string
+	connectReplay      string
+	connectDryRun      bool
+	connectKeepGoing   bool
 )
 
 // NewConnectCmd creates connect command.
@@ -52,6 +57,9 @@ func NewConnectCmd() *cobra.Command {
 			"  * \\set language <language> - set language (lua or sql)\n" +
 			"  * \\set output <format> - set output format (lua[,line|block] or yaml)\n" +
 			"  * \\set delimiter <delimiter> - set expression delimiter\n" +
+			"  * \\set var <name> <value> - set a variable for :name placeholders\n" +
+			"  * \\gset [prefix] - set variables from columns of the last result\n" +
+			"  * \\edit [file] - edit the statement or the file in $EDITOR and execute it\n" +
 			"  * \\help - show available backslash commands\n" +
 			"  * \\quit - quit interactive console",
 		Short: "Connect to the tarantool instance",
@@ -59,7 +67,14 @@ func NewConnectCmd() *cobra.Command {
 			libconnect.EnvTarantoolCredentialsHelp + "\n\n" +
 			"You could pass command line arguments to the interpreted SCRIPT" +
 			" or COMMAND passed via -f flag:\n\n" +
-			`echo "print(...)" | tt connect user:pass@localhost:3013 -f- 1, 2, 3`,
+			`echo "print(...)" | tt connect user:pass@localhost:3013 -f- 1, 2, 3` + "\n\n" +
+			"The instance could be reached through an SSH server with a URI in the " +
+			"ssh://[user@]ssh_host[:port]/host:port[?ssh_key_file=...] format:\n\n" +
+			"tt connect ssh://deploy@bastion/10.0.0.5:3301\n\n" +
+			"The console session could be recorded with --record and replayed " +
+			"non-interactively on another instance with --replay:\n\n" +
+			"tt connect app:storage001 --record session.jsonl\n" +
+			"tt connect app:storage002 --replay session.jsonl --dry-run",
 		Run:  RunModuleFunc(internalConnectModule),
 		Args: cobra.MinimumNArgs(1),
 		ValidArgsFunction: func(
@@ -103,6 +118,14 @@ func NewConnectCmd() *cobra.Command {
 If the evaler code is prefixed with @, the rest should be a file name to read the evaler
 code from`)
 	connectCmd.Flags().MarkHidden("evaler")
+	connectCmd.Flags().StringVar(&connectRecord, "record", "",
+		`record executed inputs, outputs and timestamps into the file (JSON Lines)`)
+	connectCmd.Flags().StringVar(&connectReplay, "replay", "",
+		`execute inputs of a recorded session file non-interactively`)
+	connectCmd.Flags().BoolVar(&connectDryRun, "dry-run", false,
+		`print inputs of the replayed session without execution`)
+	connectCmd.Flags().BoolVar(&connectKeepGoing, "continue-on-error", false,
+		`continue the session replay after a failed input`)
 
 	return connectCmd
 }
@@ -161,6 +184,20 @@ func resolveConnectOpts(cmdCtx *cmdcontext.CmdCtx, cliOpts *config.CliOpts,
 				connector.UnixNetwork, runningCtx.Instances[0].ConsoleSocket, *connectCtx,
 			)
 		}
+	} else if strings.HasPrefix(target, "ssh://") {
+		var uriOpts libconnect.UriOpts
+		if uriOpts, err = libconnect.CreateUriOpts(target); err != nil {
+			return connOpts, err
+		}
+		// Environment variables do not overwrite values.
+		if connectCtx.Username == "" {
+			connectCtx.Username = os.Getenv(libconnect.TarantoolUsernameEnv)
+		}
+		if connectCtx.Password == "" {
+			connectCtx.Password = os.Getenv(libconnect.TarantoolPasswordEnv)
+		}
+		connOpts = makeConnOpts(connector.TCPNetwork, uriOpts.Host, *connectCtx)
+		connOpts.Ssh = connector.SshOpts(uriOpts.Ssh)
 	} else if libconnect.IsCredentialsURI(target) {
 		if connectCtx.Username != "" || connectCtx.Password != "" {
 			err = fmt.Errorf("username and password are specified with" +
@@ -207,6 +244,7 @@ func internalConnectModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		Interactive: connectInteractive,
 		Binary:      connectBinary,
 		Evaler:      connectEvaler,
+		RecordFile:  connectRecord,
 	}
 
 	var ok bool
@@ -217,11 +255,29 @@ func internalConnectModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		return util.NewArgError(fmt.Sprintf("unsupported output format: %s", connectFormat))
 	}
 
+	if connectReplay == "" && (connectDryRun || connectKeepGoing) {
+		return util.NewArgError("--dry-run and --continue-on-error require --replay")
+	}
+	if connectReplay != "" && (connectFile != "" || connectInteractive) {
+		return util.NewArgError("--replay can not be used with --file or --interactive")
+	}
+
 	connOpts, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[0])
 	if err != nil {
 		return err
 	}
 
+	if connectReplay != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("should be specified one connection string")
+		}
+		return connect.Replay(connectCtx, connOpts, connect.ReplayOpts{
+			File:            connectReplay,
+			DryRun:          connectDryRun,
+			ContinueOnError: connectKeepGoing,
+		})
+	}
+
 	if connectFile != "" {
 		res, err := connect.Eval(connectCtx, connOpts, args[1:])
 		if err != nil {
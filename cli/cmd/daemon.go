@@ -6,7 +6,9 @@ import (
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/daemon"
+	"github.com/tarantool/tt/cli/daemon/api"
 	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
 )
 
 // NewDaemonCmd creates daemon command.
@@ -71,6 +73,24 @@ func internalDaemonRestartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error
 	return nil
 }
 
+// daemonInstancesProvider returns a provider of the tt environment instances
+// for the daemon REST API.
+func daemonInstancesProvider(cmdCtx *cmdcontext.CmdCtx) api.InstancesProvider {
+	return func(target string) ([]running.InstanceCtx, error) {
+		var args []string
+		if target != "" {
+			args = []string{target}
+		}
+		// The context and the options are copied since requests are handled
+		// concurrently.
+		ctx := *cmdCtx
+		opts := cliOpts.Clone()
+		var runningCtx running.RunningCtx
+		err := running.FillCtx(opts, &ctx, &runningCtx, args, running.ConfigLoadSkip)
+		return runningCtx.Instances, err
+	}
+}
+
 // internalDaemonStartModule is a default start module.
 func internalDaemonStartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	opts, err := configure.GetDaemonOpts(cmdCtx.Cli.DaemonCfgPath)
@@ -79,6 +99,7 @@ func internalDaemonStartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	}
 
 	daemonCtx := daemon.NewDaemonCtx(opts)
+	daemonCtx.Instances = daemonInstancesProvider(cmdCtx)
 	if err := daemon.RunHTTPServerOnBackground(daemonCtx); err != nil {
 		log.Fatalf(err.Error())
 	}
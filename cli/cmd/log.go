@@ -2,14 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/tt/cli/cmd/internal"
 	"github.com/tarantool/tt/cli/cmdcontext"
@@ -18,8 +22,28 @@ import (
 )
 
 var logOpts struct {
-	nLines int  // How many lines to print.
-	follow bool // Follow logs output.
+	nLines    int      // How many lines to print.
+	follow    bool     // Follow logs output.
+	level     string   // The least severe log level to print.
+	since     string   // Print lines since the moment.
+	until     string   // Print lines until the moment.
+	grep      string   // Print lines containing the substring.
+	regexp    string   // Print lines matching the regular expression.
+	instances []string // Print logs of the instances only.
+	merge     bool     // Merge logs of instances in chronological order.
+	format    string   // Output format: plain or json.
+}
+
+// formatPlain is a format of raw log lines output.
+const formatPlain = "plain"
+
+// logTimeLayouts are accepted layouts of --since and --until values.
+var logTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
 }
 
 const logRootCheckInterval = 100 * time.Millisecond
@@ -46,6 +70,25 @@ func NewLogCmd() *cobra.Command {
 		"Count of last lines to output")
 	logCmd.Flags().BoolVarP(&logOpts.follow, "follow", "f", false,
 		"Output appended data as the log file grows")
+	logCmd.Flags().StringVar(&logOpts.level, "level", "",
+		"Output lines of the log level or more severe: "+
+			"fatal, syserror, error, crit, warn, info, verbose or debug")
+	logCmd.Flags().StringVar(&logOpts.since, "since", "",
+		"Output lines since the moment: a timestamp like \"2024-01-02 15:04:05\" "+
+			"or a duration ago like \"10m\"")
+	logCmd.Flags().StringVar(&logOpts.until, "until", "",
+		"Output lines until the moment: a timestamp like \"2024-01-02 15:04:05\" "+
+			"or a duration ago like \"10m\"")
+	logCmd.Flags().StringVar(&logOpts.grep, "grep", "",
+		"Output lines containing the substring")
+	logCmd.Flags().StringVar(&logOpts.regexp, "regexp", "",
+		"Output lines matching the regular expression")
+	logCmd.Flags().StringSliceVar(&logOpts.instances, "instance", nil,
+		"Output logs of the instances only")
+	logCmd.Flags().BoolVar(&logOpts.merge, "merge", false,
+		"Merge logs of all instances in chronological order")
+	logCmd.Flags().StringVar(&logOpts.format, "format", formatPlain,
+		"Output format: plain or json")
 
 	return logCmd
 }
@@ -93,7 +136,187 @@ func monitorLogRoot(ctx context.Context, root string, cancel context.CancelFunc)
 	}
 }
 
-func follow(instances []running.InstanceCtx, n int) error {
+// parseLogTime parses a value of --since or --until option. The value is
+// a timestamp in local time or a duration before the now moment.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range logTimeLayouts {
+		if ts, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a timestamp like "+
+		"\"2024-01-02 15:04:05\" or a duration like \"10m\"", value)
+}
+
+// isLogFilterSet returns true if log lines must be parsed to filter or format them.
+func isLogFilterSet() bool {
+	return logOpts.level != "" || logOpts.since != "" || logOpts.until != "" ||
+		logOpts.grep != "" || logOpts.regexp != "" || logOpts.merge ||
+		logOpts.format != formatPlain
+}
+
+// makeLogFilter creates a log filter from the command options.
+func makeLogFilter(now time.Time) (tail.LogFilter, error) {
+	var err error
+	filter := tail.NewLogFilter()
+	if logOpts.level != "" {
+		if filter.Level, err = tail.ParseLogLevel(logOpts.level); err != nil {
+			return filter, err
+		}
+	}
+	if filter.Since, err = parseLogTime(logOpts.since, now); err != nil {
+		return filter, fmt.Errorf("invalid --since value: %w", err)
+	}
+	if filter.Until, err = parseLogTime(logOpts.until, now); err != nil {
+		return filter, fmt.Errorf("invalid --until value: %w", err)
+	}
+	filter.Substring = logOpts.grep
+	if logOpts.regexp != "" {
+		if filter.Regexp, err = regexp.Compile(logOpts.regexp); err != nil {
+			return filter, fmt.Errorf("invalid --regexp value: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// filterLogInstances keeps the instances with the names only.
+func filterLogInstances(instances []running.InstanceCtx,
+	names []string,
+) ([]running.InstanceCtx, error) {
+	if len(names) == 0 {
+		return instances, nil
+	}
+	filtered := make([]running.InstanceCtx, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(instances, func(inst running.InstanceCtx) bool {
+			return inst.InstName == name
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf("instance %q is not found", name)
+		}
+		filtered = append(filtered, instances[idx])
+	}
+	return filtered, nil
+}
+
+// logEntryFormatter formats parsed log entries of instances.
+type logEntryFormatter struct {
+	format     string
+	formatters map[string]tail.LogFormatter
+}
+
+// newLogEntryFormatter creates a new formatter of the instances log entries.
+func newLogEntryFormatter(instances []running.InstanceCtx, format string) logEntryFormatter {
+	formatter := logEntryFormatter{
+		format:     format,
+		formatters: make(map[string]tail.LogFormatter, len(instances)),
+	}
+	nextColor := tail.DefaultColorPicker()
+	for _, inst := range instances {
+		name := running.GetAppInstanceName(inst)
+		formatter.formatters[name] = tail.NewLogFormatter(name+": ", nextColor())
+	}
+	return formatter
+}
+
+// Format returns the output line of the entry. The raw line is returned if
+// the entry cannot be encoded.
+func (formatter logEntryFormatter) Format(entry tail.LogEntry) string {
+	if formatter.format == formatJSON {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return entry.Line
+		}
+		return string(data)
+	}
+	return formatter.formatters[entry.Instance](entry.Line)
+}
+
+// printFilteredLastN prints the last n log entries passing the filter for each
+// instance. The entries of all instances are merged if needed.
+func printFilteredLastN(ctx context.Context, instances []running.InstanceCtx, n int,
+	filter tail.LogFilter, formatter logEntryFormatter, merge bool,
+) error {
+	instEntries := make([][]tail.LogEntry, 0, len(instances))
+	for _, inst := range instances {
+		entries, err := tail.ReadEntries(ctx, inst.Log, running.GetAppInstanceName(inst),
+			filter, n)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("cannot read log file %q: %s", inst.Log, err)
+		}
+		instEntries = append(instEntries, entries)
+	}
+	if merge {
+		merged := tail.MergeEntries(instEntries...)
+		instEntries = [][]tail.LogEntry{merged[max(0, len(merged)-n):]}
+	}
+	for _, entries := range instEntries {
+		for _, entry := range entries {
+			fmt.Println(formatter.Format(entry))
+		}
+	}
+	return nil
+}
+
+// filterLines parses log lines of the instance and sends formatted entries passing
+// the filter to the out channel.
+func filterLines(in <-chan string, out chan<- string, instance string,
+	filter tail.LogFilter, formatter logEntryFormatter,
+) {
+	parser := tail.NewLogParser(instance)
+	for line := range in {
+		entry := parser.Parse(line)
+		if filter.Match(entry) {
+			out <- formatter.Format(entry)
+		}
+	}
+}
+
+// logFilterCtx describes filtering of followed log lines.
+type logFilterCtx struct {
+	filter    tail.LogFilter
+	formatter logEntryFormatter
+}
+
+func followLog(ctx context.Context, out chan<- string, inst running.InstanceCtx,
+	color color.Color, n int, filterCtx *logFilterCtx, wg *sync.WaitGroup,
+) error {
+	instName := running.GetAppInstanceName(inst)
+	if filterCtx == nil {
+		return tail.Follow(ctx, out, tail.NewLogFormatter(instName+": ", color),
+			inst.Log, n, wg)
+	}
+
+	const logLinesChannelCapacity = 64
+	lines := make(chan string, logLinesChannelCapacity)
+	var linesWg sync.WaitGroup
+	err := tail.Follow(ctx, lines, func(str string) string { return str }, inst.Log, n,
+		&linesWg)
+	if err != nil {
+		return err
+	}
+	go func() {
+		linesWg.Wait()
+		close(lines)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		filterLines(lines, out, instName, filterCtx.filter, filterCtx.formatter)
+	}()
+	return nil
+}
+
+func follow(instances []running.InstanceCtx, n int, filterCtx *logFilterCtx) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
@@ -120,9 +343,7 @@ func follow(instances []running.InstanceCtx, n int) error {
 			go monitorLogRoot(rootCtx.ctx, root, rootCtx.cancel)
 		}
 
-		err := tail.Follow(rootCtx.ctx, logLines,
-			tail.NewLogFormatter(running.GetAppInstanceName(inst)+": ", color),
-			inst.Log, n, &wg)
+		err := followLog(rootCtx.ctx, logLines, inst, color, n, filterCtx, &wg)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
 				continue
@@ -181,9 +402,39 @@ func internalLogModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		return err
 	}
 
-	if logOpts.follow {
-		return follow(runningCtx.Instances, logOpts.nLines)
+	instances, err := filterLogInstances(runningCtx.Instances, logOpts.instances)
+	if err != nil {
+		return err
+	}
+
+	if !isLogFilterSet() {
+		if logOpts.follow {
+			return follow(instances, logOpts.nLines, nil)
+		}
+		return printLastN(instances, logOpts.nLines)
 	}
 
-	return printLastN(runningCtx.Instances, logOpts.nLines)
+	if logOpts.format != formatPlain && logOpts.format != formatJSON {
+		return fmt.Errorf("unsupported format %q: expected %q or %q",
+			logOpts.format, formatPlain, formatJSON)
+	}
+	if logOpts.nLines < 0 {
+		return fmt.Errorf("negative lines count is not supported")
+	}
+	filter, err := makeLogFilter(time.Now())
+	if err != nil {
+		return err
+	}
+	formatter := newLogEntryFormatter(instances, logOpts.format)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// The last lines are read before following to filter and merge them. New lines
+	// of all instances are printed in order of arrival.
+	err = printFilteredLastN(ctx, instances, logOpts.nLines, filter, formatter, logOpts.merge)
+	if err != nil || !logOpts.follow {
+		return err
+	}
+	stop()
+	return follow(instances, 0, &logFilterCtx{filter: filter, formatter: formatter})
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tarantool/tt/cli/running"
 )
@@ -47,3 +48,57 @@ func TestMonitorLogRoot(t *testing.T) {
 		require.Fail(t, "log root removal did not cancel the follow context")
 	}
 }
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"10m", now.Add(-10 * time.Minute), false},
+		{"1h30m", now.Add(-90 * time.Minute), false},
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), false},
+		{"2024-01-01 10:11:12", time.Date(2024, 1, 1, 10, 11, 12, 0, time.Local), false},
+		{
+			"2024-01-01 10:11:12.500",
+			time.Date(2024, 1, 1, 10, 11, 12, 500000000, time.Local), false,
+		},
+		{"2024-01-01T10:11:12", time.Date(2024, 1, 1, 10, 11, 12, 0, time.Local), false},
+		{"2024-01-01T10:11:12Z", time.Date(2024, 1, 1, 10, 11, 12, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			ts, err := parseLogTime(tt.value, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(ts), "%s != %s", tt.want, ts)
+		})
+	}
+}
+
+func TestFilterLogInstances(t *testing.T) {
+	instances := []running.InstanceCtx{
+		{AppName: "app", InstName: "inst1"},
+		{AppName: "app", InstName: "inst2"},
+		{AppName: "app", InstName: "inst3"},
+	}
+
+	filtered, err := filterLogInstances(instances, nil)
+	require.NoError(t, err)
+	assert.Equal(t, instances, filtered)
+
+	filtered, err = filterLogInstances(instances, []string{"inst3", "inst1"})
+	require.NoError(t, err)
+	assert.Equal(t, []running.InstanceCtx{instances[2], instances[0]}, filtered)
+
+	_, err = filterLogInstances(instances, []string{"inst4"})
+	require.EqualError(t, err, `instance "inst4" is not found`)
+}
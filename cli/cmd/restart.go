@@ -3,16 +3,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/tt/cli/cmd/internal"
 	"github.com/tarantool/tt/cli/cmdcontext"
+	replicasetcmd "github.com/tarantool/tt/cli/replicaset/cmd"
 	"github.com/tarantool/tt/cli/running"
 	"github.com/tarantool/tt/cli/util"
+	"github.com/tarantool/tt/lib/integrity"
 )
 
-var autoYes bool
+var (
+	autoYes bool
+	// restartRolling is true if instances are restarted one by one without
+	// losing the write master.
+	restartRolling bool
+	// restartSwitchBack is true if the original masters are promoted back
+	// after the rolling restart.
+	restartSwitchBack bool
+	// restartTimeout is a timeout of each rolling restart step in seconds.
+	restartTimeout int
+)
 
 // NewRestartCmd creates start command.
 func NewRestartCmd() *cobra.Command {
@@ -35,6 +48,14 @@ func NewRestartCmd() *cobra.Command {
 
 	restartCmd.Flags().BoolVarP(&autoYes, "yes", "y", false,
 		`Automatic yes to confirmation prompt`)
+	restartCmd.Flags().BoolVar(&restartRolling, "rolling", false,
+		`Restart replicasets instances one by one without losing the write master`)
+	restartCmd.Flags().BoolVar(&restartSwitchBack, "switch-back", false,
+		`Promote the original masters back after the rolling restart`)
+	restartCmd.Flags().IntVar(&restartTimeout, "timeout",
+		replicasetcmd.RollingRestartDefaultTimeout,
+		`Timeout of each rolling restart step in seconds`)
+	integrity.RegisterWithIntegrityFlag(restartCmd.Flags(), &replicasetIntegrityPrivateKey)
 
 	return restartCmd
 }
@@ -49,6 +70,14 @@ func internalRestartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		return fmt.Errorf("tarantool binary is not found")
 	}
 
+	if restartSwitchBack && !restartRolling {
+		return fmt.Errorf("--switch-back can be used only with --rolling")
+	}
+	if restartRolling && (len(args) != 1 ||
+		strings.Contains(args[0], string(running.InstanceDelimiter))) {
+		return fmt.Errorf("rolling restart requires an application name")
+	}
+
 	if !autoYes {
 		instancesToConfirm := ""
 		if len(args) == 0 {
@@ -67,6 +96,10 @@ func internalRestartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		}
 	}
 
+	if restartRolling {
+		return rollingRestart(cmdCtx, args[0])
+	}
+
 	if err := internalStopModule(cmdCtx, args); err != nil {
 		return err
 	}
@@ -77,3 +110,34 @@ func internalRestartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 
 	return nil
 }
+
+// rollingRestart restarts the application replicaset by replicaset without
+// losing the write master.
+func rollingRestart(cmdCtx *cmdcontext.CmdCtx, appName string) error {
+	var runningCtx running.RunningCtx
+	err := running.FillCtx(cliOpts, cmdCtx, &runningCtx, []string{appName},
+		running.ConfigLoadAll)
+	if err != nil {
+		return err
+	}
+
+	collectors, publishers, err := createDataCollectorsAndDataPublishers(
+		cmdCtx.Integrity, replicasetIntegrityPrivateKey)
+	if err != nil {
+		return err
+	}
+
+	return replicasetcmd.RollingRestart(replicasetcmd.RollingRestartCtx{
+		RunningCtx: runningCtx,
+		Collectors: collectors,
+		Publishers: publishers,
+		Restart: func(instance running.InstanceCtx) error {
+			if err := running.Stop([]running.InstanceCtx{instance}); err != nil {
+				return err
+			}
+			return startInstancesUnderWatchdog(cmdCtx, []running.InstanceCtx{instance})
+		},
+		SwitchBack: restartSwitchBack,
+		Timeout:    restartTimeout,
+	})
+}
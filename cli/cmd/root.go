@@ -185,6 +185,8 @@ After that tt will be able to manage the application using 'replicaset_example'
 		NewStartCmd(),
 		NewStopCmd(),
 		NewStatusCmd(),
+		NewTopCmd(),
+		NewSystemdCmd(),
 		NewRestartCmd(),
 		NewLogrotateCmd(),
 		NewCheckCmd(),
@@ -9,12 +9,15 @@ import (
 	"strconv"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
+	clustercli "github.com/tarantool/tt/cli/cluster"
 	"github.com/tarantool/tt/cli/cmd/internal"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/status"
 	"github.com/tarantool/tt/cli/tail"
 	"github.com/tarantool/tt/lib/integrity"
 )
@@ -31,14 +34,38 @@ var (
 	// watchdog children start and waits for them to complete. Also all logging is performed
 	// to standard output.
 	startInteractive bool
+	// startWait is true if tt waits until the started instances are ready.
+	startWait bool
+	// startWaitTimeout is a timeout of waiting for the instances readiness
+	// in seconds.
+	startWaitTimeout int
+	// startRenderOpts defines overlays and variables to render cluster
+	// configurations of the started instances.
+	startRenderOpts clustercli.RenderOpts
 )
 
+// defaultStartWaitTimeout is a default timeout of waiting for the instances
+// readiness in seconds.
+const defaultStartWaitTimeout = 60
+
 // NewStartCmd creates start command.
 func NewStartCmd() *cobra.Command {
 	startCmd := &cobra.Command{
 		Use:   "start [<APP_NAME> | <APP_NAME:INSTANCE_NAME>]",
 		Short: "Start tarantool instance(s)",
-		Run:   RunModuleFunc(internalStartModule),
+		Long: "Start tarantool instance(s).\n\n" +
+			"If a cluster configuration contains secret references or cluster " +
+			"config render options are set, the configuration is rendered into " +
+			"the run directory of an instance. The rendered file contains values " +
+			"of the secrets in plain text, it is readable only by the owner and it " +
+			"is removed after the instance stops.",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// The timeout makes sense only with waiting, so it implies --wait.
+			if cmd.Flags().Changed("timeout") {
+				startWait = true
+			}
+		},
+		Run: RunModuleFunc(internalStartModule),
 		ValidArgsFunction: func(
 			cmd *cobra.Command,
 			args []string,
@@ -54,6 +81,12 @@ func NewStartCmd() *cobra.Command {
 	startCmd.Flags().BoolVar(&watchdog, "watchdog", false, "")
 	startCmd.Flags().MarkHidden("watchdog")
 	startCmd.Flags().BoolVarP(&startInteractive, "interactive", "i", false, "")
+	startCmd.Flags().BoolVar(&startWait, "wait", false,
+		"Wait until the started instances are running and their configuration is applied")
+	startCmd.Flags().IntVar(&startWaitTimeout, "timeout", defaultStartWaitTimeout,
+		"Timeout of waiting for the instances readiness in seconds, implies --wait")
+
+	registerRenderFlags(startCmd, &startRenderOpts)
 
 	integrity.RegisterIntegrityCheckPeriodFlag(startCmd.Flags(), &cmdCtx.Cli.IntegrityCheckPeriod)
 
@@ -125,6 +158,9 @@ func internalStartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	}
 
 	var runningCtx running.RunningCtx
+	if startRenderOpts.IsSet() {
+		runningCtx.ClusterRender = &startRenderOpts
+	}
 	err := running.FillCtx(cliOpts, cmdCtx, &runningCtx, args, running.ConfigLoadAll)
 	if err != nil {
 		return err
@@ -134,10 +170,26 @@ func internalStartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		return errors.New(reason)
 	}
 
+	if startRenderOpts.IsSet() {
+		// The options are stored for the watchdog processes and other
+		// commands working with the application.
+		err := running.SetClusterRenderOpts(runningCtx.Instances, startRenderOpts)
+		if err != nil {
+			return fmt.Errorf("failed to set cluster config render options: %w", err)
+		}
+	}
+
 	if !watchdog {
+		if startWait && startInteractive {
+			return fmt.Errorf("--wait cannot be used with --interactive")
+		}
 		if err := startInstances(cmdCtx, runningCtx.Instances); err != nil {
 			return err
 		}
+		if startWait {
+			return status.WaitReady(runningCtx.Instances,
+				time.Duration(startWaitTimeout)*time.Second)
+		}
 		return nil
 	}
 
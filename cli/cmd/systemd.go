@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmd/internal"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/systemd"
+)
+
+var (
+	systemdUserManager bool
+	systemdRunAs       string
+	systemdOutputDir   string
+	systemdUnitDir     string
+)
+
+// newSystemdSubCmd creates a systemd subcommand.
+func newSystemdSubCmd(use, short string,
+	fn func(*cmdcontext.CmdCtx, []string) error,
+) *cobra.Command {
+	subCmd := &cobra.Command{
+		Use:   use + " [<APP_NAME>]",
+		Short: short,
+		Run:   RunModuleFunc(fn),
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string,
+		) ([]string, cobra.ShellCompDirective) {
+			return internal.ValidArgsFunction(
+				cliOpts, &cmdCtx, cmd, toComplete,
+				running.ExtractAppNames,
+				running.ExtractInstanceNames)
+		},
+	}
+	subCmd.Flags().BoolVar(&systemdUserManager, "user", false,
+		"generate units for the user service manager")
+	subCmd.Flags().StringVar(&systemdRunAs, "run-as", "",
+		"user and group to run instances by the system service manager: USER[:GROUP]")
+	return subCmd
+}
+
+// NewSystemdCmd creates systemd command.
+func NewSystemdCmd() *cobra.Command {
+	systemdCmd := &cobra.Command{
+		Use:   "systemd",
+		Short: "Manage systemd units of the applications",
+		Long: `Generate, install and uninstall systemd template units of the applications.
+Units start and stop instances using tt of the current environment and apply
+the restart policy of tt.yaml. The unit template is the same as the one used
+by 'tt pack'. Unit parameters may be overridden in the systemd-unit-params.yml
+file of the application directory.`,
+	}
+
+	generateCmd := newSystemdSubCmd("generate", "Print or write systemd units",
+		internalSystemdGenerateModule)
+	generateCmd.Flags().StringVarP(&systemdOutputDir, "output", "o", "",
+		"directory to write units to instead of stdout")
+
+	installCmd := newSystemdSubCmd("install", "Install and enable systemd units",
+		internalSystemdInstallModule)
+	installCmd.Flags().StringVar(&systemdUnitDir, "unit-dir", "",
+		"directory to install units to instead of the service manager default")
+
+	uninstallCmd := newSystemdSubCmd("uninstall", "Disable and remove systemd units",
+		internalSystemdUninstallModule)
+	uninstallCmd.Flags().StringVar(&systemdUnitDir, "unit-dir", "",
+		"directory to remove units from instead of the service manager default")
+
+	systemdCmd.AddCommand(generateCmd, installCmd, uninstallCmd)
+	return systemdCmd
+}
+
+// newSystemdCtx creates a context of the systemd command.
+func newSystemdCtx(cmdCtx *cmdcontext.CmdCtx, args []string) (*systemd.SystemdCtx, error) {
+	if !isConfigExist(cmdCtx) {
+		return nil, errNoConfig
+	}
+
+	user, group, _ := strings.Cut(systemdRunAs, ":")
+	if systemdRunAs != "" && user == "" {
+		return nil, fmt.Errorf("invalid --run-as value %q: expected USER[:GROUP]", systemdRunAs)
+	}
+	if systemdUserManager && systemdRunAs != "" {
+		return nil, fmt.Errorf("--run-as cannot be used with --user")
+	}
+
+	var runningCtx running.RunningCtx
+	err := running.FillCtx(cliOpts, cmdCtx, &runningCtx, args, running.ConfigLoadSkip)
+	if err != nil {
+		return nil, err
+	}
+
+	ttBin, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return &systemd.SystemdCtx{
+		Instances:   runningCtx.Instances,
+		TT:          ttBin,
+		ConfigPath:  cmdCtx.Cli.ConfigDir,
+		UserManager: systemdUserManager,
+		User:        user,
+		Group:       group,
+		UnitDir:     systemdUnitDir,
+	}, nil
+}
+
+// internalSystemdGenerateModule is a default systemd generate module.
+func internalSystemdGenerateModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	systemdCtx, err := newSystemdCtx(cmdCtx, args)
+	if err != nil {
+		return err
+	}
+	return systemd.Generate(systemdCtx, systemdOutputDir, os.Stdout)
+}
+
+// internalSystemdInstallModule is a default systemd install module.
+func internalSystemdInstallModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	systemdCtx, err := newSystemdCtx(cmdCtx, args)
+	if err != nil {
+		return err
+	}
+	return systemd.Install(systemdCtx)
+}
+
+// internalSystemdUninstallModule is a default systemd uninstall module.
+func internalSystemdUninstallModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	systemdCtx, err := newSystemdCtx(cmdCtx, args)
+	if err != nil {
+		return err
+	}
+	return systemd.Uninstall(systemdCtx)
+}
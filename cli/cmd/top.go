@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmd/internal"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/top"
+)
+
+var (
+	topOnce     bool
+	topFormat   string
+	topSort     string
+	topInterval int
+)
+
+// NewTopCmd creates top command.
+func NewTopCmd() *cobra.Command {
+	topCmd := &cobra.Command{
+		Use:   "top [<APP_NAME> | <APP_NAME:INSTANCE_NAME>]",
+		Short: "Live dashboard of the running tarantool instance(s)",
+		Long: `The 'top' command periodically polls running instances over their console
+sockets and shows per-instance metrics.
+
+Columns:
+- RPS: Requests per second of all types.
+- RPS HISTORY: A sparkline of recent RPS values.
+- ARENA: Memory used and allocated for tuples.
+- LUA: Memory used by the Lua runtime.
+- FIBERS: The number of fibers.
+- LAG: The maximum replication lag of upstreams in seconds.
+- VCLOCK: The vclock of the instance.
+- WAL ROWS: Rows written since the last checkpoint.`,
+		Run: RunModuleFunc(internalTopModule),
+		ValidArgsFunction: func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string,
+		) ([]string, cobra.ShellCompDirective) {
+			return internal.ValidArgsFunction(
+				cliOpts, &cmdCtx, cmd, toComplete,
+				running.ExtractAppNames,
+				running.ExtractInstanceNames)
+		},
+	}
+
+	topCmd.Flags().BoolVar(&topOnce, "once", false,
+		"collect metrics once and print them without the interactive dashboard")
+	topCmd.Flags().StringVar(&topFormat, "format", formatTable,
+		"output format of --once: table or json")
+	topCmd.Flags().StringVar(&topSort, "sort", "name",
+		"sort instances by: name, rps, memory, fibers or lag")
+	topCmd.Flags().IntVar(&topInterval, "interval", 2,
+		"metrics polling interval in seconds")
+
+	return topCmd
+}
+
+// internalTopModule is a default top module.
+func internalTopModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if !isConfigExist(cmdCtx) {
+		return errNoConfig
+	}
+
+	sortKey, err := top.ParseSortKey(topSort)
+	if err != nil {
+		return err
+	}
+	if topFormat != formatTable && topFormat != formatJSON {
+		return fmt.Errorf("unsupported format %q: expected %q or %q",
+			topFormat, formatTable, formatJSON)
+	}
+	if topFormat != formatTable && !topOnce {
+		return fmt.Errorf("--format can be used with --once only")
+	}
+	if topInterval <= 0 {
+		return fmt.Errorf("polling interval must be positive")
+	}
+
+	var runningCtx running.RunningCtx
+	err = running.FillCtx(cliOpts, cmdCtx, &runningCtx, args, running.ConfigLoadSkip)
+	if err != nil {
+		return err
+	}
+
+	if topOnce {
+		return top.Once(runningCtx.Instances, sortKey, topFormat, os.Stdout)
+	}
+	return top.Run(runningCtx.Instances, sortKey, time.Duration(topInterval)*time.Second)
+}
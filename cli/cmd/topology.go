@@ -3,7 +3,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -11,6 +14,7 @@ import (
 	"github.com/apex/log"
 
 	clustercli "github.com/tarantool/tt/cli/cluster"
+	clustercmd "github.com/tarantool/tt/cli/cluster/cmd"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/connect"
 	"github.com/tarantool/tt/cli/connector"
@@ -46,11 +50,39 @@ type topologyInstanceOutput struct {
 // hostnameExpr fetches the instance UUID and the hostname of the node.
 const hostnameExpr = `return box.info.uuid, box.info.hostname`
 
+// upstreamsExpr fetches replication upstreams of the node.
+const upstreamsExpr = `local upstreams = {}
+for _, peer in pairs(box.info.replication) do
+    if peer.uuid ~= box.info.uuid and peer.upstream ~= nil then
+        table.insert(upstreams, {
+            name = peer.name,
+            uuid = peer.uuid,
+            status = peer.upstream.status,
+        })
+    end
+end
+return upstreams`
+
 type topologyDiscoveryResult struct {
 	topology     *replicaset.Replicasets
 	instanceUUID string
 	hostname     string
 	connected    bool
+	upstreams    []clustercmd.TopologyUpstream
+}
+
+// topologyDiscovery is a discovered cluster topology.
+type topologyDiscovery struct {
+	// config is the cluster configuration.
+	config libcluster.ClusterConfig
+	// merged is the topology merged from the configuration and instances.
+	merged replicaset.Replicasets
+	// hostnames are hostnames by instance UUIDs.
+	hostnames map[string]string
+	// reachable are reachable instances by UUIDs and names.
+	reachable map[string]bool
+	// upstreams are replication upstreams by instance names.
+	upstreams map[string][]clustercmd.TopologyUpstream
 }
 
 func discoverInstanceTopology(
@@ -92,6 +124,10 @@ func discoverInstanceTopology(
 		result.instanceUUID, _ = hostData[0].(string)
 		result.hostname, _ = hostData[1].(string)
 	}
+	upstreamsData, err := conn.Eval(upstreamsExpr, []any{}, connector.RequestOpts{})
+	if err == nil && len(upstreamsData) > 0 {
+		result.upstreams = parseTopologyUpstreams(upstreamsData[0])
+	}
 
 	orchestrator, err := replicaset.EvalOrchestrator(conn)
 	if err != nil {
@@ -115,6 +151,24 @@ func discoverInstanceTopology(
 	return result
 }
 
+// parseTopologyUpstreams parses replication upstreams of an instance.
+func parseTopologyUpstreams(data any) []clustercmd.TopologyUpstream {
+	list, _ := data.([]any)
+	upstreams := make([]clustercmd.TopologyUpstream, 0, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[any]any)
+		if !ok {
+			continue
+		}
+		upstream := clustercmd.TopologyUpstream{}
+		upstream.Peer, _ = fields["name"].(string)
+		upstream.PeerUUID, _ = fields["uuid"].(string)
+		upstream.Status, _ = fields["status"].(string)
+		upstreams = append(upstreams, upstream)
+	}
+	return upstreams
+}
+
 func connectTopologyInstance(opts connector.ConnectOpts) (connector.Connector, error) {
 	if opts.Network == connector.UnixNetwork {
 		topologyUnixConnectMutex.Lock()
@@ -127,7 +181,12 @@ func connectTopologyInstance(opts connector.ConnectOpts) (connector.Connector, e
 func discoverInstancesParallel(
 	instanceNames []string,
 	discover func(string) topologyDiscoveryResult,
-) ([]replicaset.Replicasets, map[string]string, map[string]bool) {
+) (
+	[]replicaset.Replicasets,
+	map[string]string,
+	map[string]bool,
+	map[string][]clustercmd.TopologyUpstream,
+) {
 	results := make([]topologyDiscoveryResult, len(instanceNames))
 	var wg sync.WaitGroup
 
@@ -145,12 +204,14 @@ func discoverInstancesParallel(
 	topologies := make([]replicaset.Replicasets, 0, len(results))
 	hostnames := map[string]string{}
 	reachable := map[string]bool{}
+	upstreams := map[string][]clustercmd.TopologyUpstream{}
 	for i, result := range results {
 		if result.topology != nil {
 			topologies = append(topologies, *result.topology)
 		}
 		if result.connected {
 			reachable[instanceNames[i]] = true
+			upstreams[instanceNames[i]] = result.upstreams
 			if result.instanceUUID != "" {
 				reachable[result.instanceUUID] = true
 				hostnames[result.instanceUUID] = result.hostname
@@ -158,15 +219,18 @@ func discoverInstancesParallel(
 		}
 	}
 
-	return topologies, hostnames, reachable
+	return topologies, hostnames, reachable, upstreams
 }
 
 // internalClusterTopologyModule is an entrypoint for cluster topology command.
 func internalClusterTopologyModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	switch topologyFormat {
 	case formatJSON, formatTable, "":
+	case clustercmd.TopologyFormatDot, clustercmd.TopologyFormatMermaid,
+		clustercmd.TopologyFormatSvg:
 	default:
-		return fmt.Errorf("unsupported format: %s (use table or json)", topologyFormat)
+		return fmt.Errorf("unsupported format: %s (use table, json, dot, mermaid or svg)",
+			topologyFormat)
 	}
 
 	connectCtx := connect.ConnectCtx{
@@ -178,16 +242,12 @@ func internalClusterTopologyModule(cmdCtx *cmdcontext.CmdCtx, args []string) err
 		SslCiphers:  replicasetSslCiphers,
 	}
 
-	merged, hostnames, reachable, err := discoverClusterTopology(
-		cmdCtx,
-		topologyConfigPath,
-		connectCtx,
-	)
+	discovery, err := discoverTopology(cmdCtx, topologyConfigPath, connectCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover cluster topology: %w", err)
 	}
 
-	if err := printTopology(merged, hostnames, reachable); err != nil {
+	if err := printTopology(discovery); err != nil {
 		return fmt.Errorf("failed to print topology: %w", err)
 	}
 
@@ -202,19 +262,31 @@ func discoverClusterTopology(
 	configPath string,
 	connectCtx connect.ConnectCtx,
 ) (replicaset.Replicasets, map[string]string, map[string]bool, error) {
+	discovery, err := discoverTopology(cmdCtx, configPath, connectCtx)
+	if err != nil {
+		return replicaset.Replicasets{}, nil, nil, err
+	}
+	return discovery.merged, discovery.hostnames, discovery.reachable, nil
+}
+
+// discoverTopology loads the cluster config and discovers the live topology
+// from all instances.
+func discoverTopology(
+	cmdCtx *cmdcontext.CmdCtx,
+	configPath string,
+	connectCtx connect.ConnectCtx,
+) (topologyDiscovery, error) {
 	clusterConfig, configDir, err := loadTopologyConfig(cmdCtx, configPath)
 	if err != nil {
-		return replicaset.Replicasets{}, nil, nil,
-			fmt.Errorf("failed to load topology config: %w", err)
+		return topologyDiscovery{}, fmt.Errorf("failed to load topology config: %w", err)
 	}
 
 	instanceNames := libcluster.Instances(clusterConfig)
 	if len(instanceNames) == 0 {
-		return replicaset.Replicasets{}, nil, nil,
-			fmt.Errorf("no instances found in the cluster config")
+		return topologyDiscovery{}, fmt.Errorf("no instances found in the cluster config")
 	}
 
-	allTopologies, hostnames, reachable := discoverInstancesParallel(
+	allTopologies, hostnames, reachable, upstreams := discoverInstancesParallel(
 		instanceNames,
 		func(instName string) topologyDiscoveryResult {
 			return discoverInstanceTopology(
@@ -233,7 +305,13 @@ func discoverClusterTopology(
 	)
 	merged := mergeReplicasets(allTopologies)
 
-	return merged, hostnames, reachable, nil
+	return topologyDiscovery{
+		config:    clusterConfig,
+		merged:    merged,
+		hostnames: hostnames,
+		reachable: reachable,
+		upstreams: upstreams,
+	}, nil
 }
 
 func loadTopologyConfig(
@@ -285,20 +363,105 @@ func loadTopologyConfig(
 	return clusterConfig, filepath.Dir(source), nil
 }
 
-func printTopology(
-	merged replicaset.Replicasets,
-	hostnames map[string]string,
-	reachable map[string]bool,
-) error {
+func printTopology(discovery topologyDiscovery) error {
+	merged, hostnames, reachable := discovery.merged, discovery.hostnames, discovery.reachable
 	switch topologyFormat {
 	case formatJSON:
 		topology := replicasetsToTopology(merged, hostnames, reachable)
 		return printTopologyJSON(topology) //nolint:wrapcheck
+	case clustercmd.TopologyFormatDot, clustercmd.TopologyFormatMermaid,
+		clustercmd.TopologyFormatSvg:
+		graph := makeTopologyGraph(discovery)
+		return clustercmd.RenderTopology(os.Stdout, graph, topologyFormat) //nolint:wrapcheck
 	default:
 		return printTopologyTable(merged, hostnames, reachable) //nolint:wrapcheck
 	}
 }
 
+// configStrings returns a list of strings by the path in the configuration.
+func configStrings(config *libcluster.Config, path []string) []string {
+	data, _ := config.Get(path)
+	list, _ := data.([]any)
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+// findMergedInstance finds a discovered replicaset and instance by names.
+func findMergedInstance(
+	merged replicaset.Replicasets,
+	rsName, instName string,
+) (replicaset.Replicaset, replicaset.Instance) {
+	for _, rs := range merged.Replicasets {
+		if rs.Alias != rsName {
+			continue
+		}
+		for _, inst := range rs.Instances {
+			if inst.Alias == instName {
+				return rs, inst
+			}
+		}
+		return rs, replicaset.Instance{Alias: instName}
+	}
+	return replicaset.Replicaset{}, replicaset.Instance{Alias: instName}
+}
+
+// makeTopologyGraph makes a graph of the discovered topology. Groups,
+// replicasets, instances and roles are taken from the cluster configuration,
+// modes and replication upstreams are taken from reachable instances.
+func makeTopologyGraph(discovery topologyDiscovery) clustercmd.TopologyGraph {
+	var graph clustercmd.TopologyGraph
+
+	for _, groupName := range slices.Sorted(maps.Keys(discovery.config.Groups)) {
+		group := discovery.config.Groups[groupName]
+		graphGroup := clustercmd.TopologyGroup{Name: groupName}
+
+		for _, rsName := range slices.Sorted(maps.Keys(group.Replicasets)) {
+			graphReplicaset := clustercmd.TopologyReplicaset{Name: rsName}
+
+			instNames := slices.Sorted(maps.Keys(group.Replicasets[rsName].Instances))
+			for _, instName := range instNames {
+				rs, inst := findMergedInstance(discovery.merged, rsName, instName)
+				instConfig := libcluster.Instantiate(discovery.config, instName)
+				graphReplicaset.Instances = append(graphReplicaset.Instances,
+					clustercmd.TopologyInstance{
+						Name:     instName,
+						UUID:     inst.UUID,
+						Hostname: lookupHostname(inst.UUID, discovery.hostnames),
+						Mode:     formatMode(inst.Mode),
+						Roles:    configStrings(instConfig, []string{"roles"}),
+						Sharding: configStrings(instConfig, []string{"sharding", "roles"}),
+						Leader: inst.Mode == replicaset.ModeRW ||
+							inst.UUID != "" && inst.UUID == rs.LeaderUUID,
+						Reachable: discovery.reachable[instName] ||
+							discovery.reachable[inst.UUID],
+						Upstreams: discovery.upstreams[instName],
+					})
+			}
+			// Leaders first as in the table output.
+			slices.SortStableFunc(graphReplicaset.Instances,
+				func(a, b clustercmd.TopologyInstance) int {
+					if a.Leader == b.Leader {
+						return 0
+					}
+					if a.Leader {
+						return -1
+					}
+					return 1
+				})
+
+			graphGroup.Replicasets = append(graphGroup.Replicasets, graphReplicaset)
+		}
+		graph.Groups = append(graph.Groups, graphGroup)
+	}
+
+	return graph
+}
+
 func topologyFromConfig(clusterConfig libcluster.ClusterConfig) replicaset.Replicasets {
 	var topology replicaset.Replicasets
 
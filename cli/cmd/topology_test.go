@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	clustercmd "github.com/tarantool/tt/cli/cluster/cmd"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/replicaset"
 	libcluster "github.com/tarantool/tt/lib/cluster"
@@ -22,8 +23,9 @@ func TestDiscoverInstancesParallel(t *testing.T) {
 	var topologies []replicaset.Replicasets
 	var hostnames map[string]string
 	var reachable map[string]bool
+	var upstreams map[string][]clustercmd.TopologyUpstream
 	go func() {
-		topologies, hostnames, reachable = discoverInstancesParallel(
+		topologies, hostnames, reachable, upstreams = discoverInstancesParallel(
 			instanceNames,
 			func(instName string) topologyDiscoveryResult {
 				started <- struct{}{}
@@ -35,6 +37,9 @@ func TestDiscoverInstancesParallel(t *testing.T) {
 					instanceUUID: instName,
 					hostname:     instName + "-host",
 					connected:    true,
+					upstreams: []clustercmd.TopologyUpstream{
+						{Peer: instName + "-peer", Status: "follow"},
+					},
 				}
 			},
 		)
@@ -63,6 +68,9 @@ func TestDiscoverInstancesParallel(t *testing.T) {
 		assert.Equal(t, instName, topologies[i].Replicasets[0].Alias)
 		assert.Equal(t, instName+"-host", hostnames[instName])
 		assert.True(t, reachable[instName])
+		assert.Equal(t, []clustercmd.TopologyUpstream{
+			{Peer: instName + "-peer", Status: "follow"},
+		}, upstreams[instName])
 	}
 }
 
@@ -137,3 +145,116 @@ func TestReplicasetsToTopology(t *testing.T) {
 	assert.Equal(t, "ro", instances[2].Mode)
 	assert.Equal(t, topologyStatusNotReachable, instances[2].Status)
 }
+
+func TestParseTopologyUpstreams(t *testing.T) {
+	upstreams := parseTopologyUpstreams([]any{
+		map[any]any{"name": "instance-2", "uuid": "uuid-2", "status": "follow"},
+		map[any]any{"uuid": "uuid-3", "status": "disconnected"},
+		"invalid",
+	})
+	assert.Equal(t, []clustercmd.TopologyUpstream{
+		{Peer: "instance-2", PeerUUID: "uuid-2", Status: "follow"},
+		{PeerUUID: "uuid-3", Status: "disconnected"},
+	}, upstreams)
+
+	assert.Empty(t, parseTopologyUpstreams(nil))
+}
+
+func TestMakeTopologyGraph(t *testing.T) {
+	config, err := libcluster.NewYamlCollector([]byte(`roles: [app.metrics]
+groups:
+  storages:
+    sharding:
+      roles: [storage]
+    replicasets:
+      s-1:
+        instances:
+          s-1-b: {}
+          s-1-a:
+            roles: [app.metrics, app.storage]
+  routers:
+    replicasets:
+      r-1:
+        instances:
+          r-1-a: {}
+`)).Collect()
+	require.NoError(t, err)
+	clusterConfig, err := libcluster.MakeClusterConfig(config)
+	require.NoError(t, err)
+
+	upstreams := []clustercmd.TopologyUpstream{{Peer: "s-1-a", Status: "follow"}}
+	graph := makeTopologyGraph(topologyDiscovery{
+		config: clusterConfig,
+		merged: replicaset.Replicasets{
+			Replicasets: []replicaset.Replicaset{
+				{
+					Alias: "s-1",
+					Instances: []replicaset.Instance{
+						{Alias: "s-1-b", UUID: "uuid-b", Mode: replicaset.ModeRead},
+						{Alias: "s-1-a", UUID: "uuid-a", Mode: replicaset.ModeRW},
+					},
+				},
+				{
+					Alias:      "r-1",
+					LeaderUUID: "uuid-r",
+					Instances:  []replicaset.Instance{{Alias: "r-1-a", UUID: "uuid-r"}},
+				},
+			},
+		},
+		hostnames: map[string]string{"uuid-a": "host-a"},
+		reachable: map[string]bool{"uuid-a": true, "s-1-b": true},
+		upstreams: map[string][]clustercmd.TopologyUpstream{"s-1-b": upstreams},
+	})
+
+	assert.Equal(t, clustercmd.TopologyGraph{
+		Groups: []clustercmd.TopologyGroup{
+			{
+				Name: "routers",
+				Replicasets: []clustercmd.TopologyReplicaset{
+					{
+						Name: "r-1",
+						Instances: []clustercmd.TopologyInstance{
+							{
+								Name:     "r-1-a",
+								UUID:     "uuid-r",
+								Mode:     "unknown",
+								Roles:    []string{"app.metrics"},
+								Sharding: []string{},
+								Leader:   true,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "storages",
+				Replicasets: []clustercmd.TopologyReplicaset{
+					{
+						Name: "s-1",
+						Instances: []clustercmd.TopologyInstance{
+							{
+								Name:      "s-1-a",
+								UUID:      "uuid-a",
+								Hostname:  "host-a",
+								Mode:      "rw",
+								Roles:     []string{"app.metrics", "app.storage"},
+								Sharding:  []string{"storage"},
+								Leader:    true,
+								Reachable: true,
+							},
+							{
+								Name:      "s-1-b",
+								UUID:      "uuid-b",
+								Mode:      "ro",
+								Roles:     []string{"app.metrics"},
+								Sharding:  []string{"storage"},
+								Reachable: true,
+								Upstreams: upstreams,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, graph)
+}
@@ -1,5 +1,7 @@
 package config
 
+import "slices"
+
 // CliOpts stores information about Tarantool CLI configuration.
 // Filled in when parsing the tt.yaml configuration file.
 //
@@ -8,6 +10,19 @@ package config
 //    instances_enabled: path
 //    tarantoolctl_layout: false
 //    restart_on_failure: bool
+//    restart_policy:
+//      delay: seconds
+//      max_delay: seconds
+//      max_restarts: number
+//      window: seconds
+//    resource_limits:
+//      memory_max: size
+//      cpu_quota: percents
+//      io_weight: number
+//      cgroup_root: path
+//    coredump:
+//      capture: bool
+//      dir: path
 //  modules:
 //    directory: path/to
 //  app:
@@ -63,6 +78,12 @@ type TtEnvOpts struct {
 	// Restartable - if set the instance is started under the watchdog it should
 	// restart on if it crashes.
 	Restartable bool `mapstructure:"restart_on_failure" yaml:"restart_on_failure"`
+	// RestartPolicy describes restarts of failed instances.
+	RestartPolicy *RestartPolicyOpts `mapstructure:"restart_policy" yaml:"restart_policy,omitempty"`
+	// Coredump describes capturing of core dumps of crashed instances.
+	Coredump *CoredumpOpts `mapstructure:"coredump" yaml:"coredump,omitempty"`
+	// ResourceLimits describes resource limits of instances.
+	ResourceLimits *LimitsOpts `mapstructure:"resource_limits" yaml:"resource_limits,omitempty"`
 	// TarantoolctlLayout enables artifact files layout compatibility with tarantoolctl:
 	// application sub-directories are not created for runtime artifacts like
 	// control socket, pid files and logs.
@@ -72,6 +93,44 @@ type TtEnvOpts struct {
 	OutputHistoryMax int `mapstructure:"output_history_max" yaml:"output_history_max"`
 }
 
+// RestartPolicyOpts describes how the watchdog restarts a failed instance.
+type RestartPolicyOpts struct {
+	// Delay is a delay before the first restart in seconds.
+	Delay int `mapstructure:"delay" yaml:"delay"`
+	// MaxDelay is a maximum delay in seconds. The delay is doubled after
+	// each restart until it reaches MaxDelay.
+	MaxDelay int `mapstructure:"max_delay" yaml:"max_delay"`
+	// MaxRestarts is a maximum number of restarts within Window after which
+	// the watchdog gives up. Zero means no limit.
+	MaxRestarts int `mapstructure:"max_restarts" yaml:"max_restarts"`
+	// Window is a period to count restarts in seconds. Zero means that all
+	// restarts are counted.
+	Window int `mapstructure:"window" yaml:"window"`
+}
+
+// LimitsOpts describes resource limits applied to each instance by
+// placing it into a dedicated cgroup v2.
+type LimitsOpts struct {
+	// MemoryMax is a memory usage hard limit, like `512M` or `2G`.
+	MemoryMax string `mapstructure:"memory_max" yaml:"memory_max,omitempty"`
+	// CPUQuota is a CPU time limit in percents of one CPU.
+	CPUQuota int `mapstructure:"cpu_quota" yaml:"cpu_quota,omitempty"`
+	// IOWeight is a relative IO weight from 1 to 10000.
+	IOWeight int `mapstructure:"io_weight" yaml:"io_weight,omitempty"`
+	// CgroupRoot is a parent cgroup of instances cgroups.
+	CgroupRoot string `mapstructure:"cgroup_root" yaml:"cgroup_root,omitempty"`
+}
+
+// CoredumpOpts describes how the watchdog captures a core dump of an instance
+// crashed by a signal.
+type CoredumpOpts struct {
+	// Capture enables packing of the core dump into the instance run directory.
+	Capture bool `mapstructure:"capture" yaml:"capture"`
+	// Dir is a directory to search core dumps in before the locations from
+	// the kernel core pattern.
+	Dir string `mapstructure:"dir" yaml:"dir,omitempty"`
+}
+
 // TemplateOpts contains configuration for applications templates.
 type TemplateOpts struct {
 	// Path is a directory to search template in.
@@ -101,3 +160,37 @@ type CliOpts struct {
 	// Repo is a struct used to store paths to local files.
 	Repo *RepoOpts
 }
+
+// clonePtr returns a pointer to a copy of the value or nil.
+func clonePtr[T any](ptr *T) *T {
+	if ptr == nil {
+		return nil
+	}
+	clone := *ptr
+	return &clone
+}
+
+// Clone returns a deep copy of the options. It allows to use the options
+// concurrently with modifications.
+func (opts *CliOpts) Clone() *CliOpts {
+	if opts == nil {
+		return nil
+	}
+	clone := &CliOpts{
+		Env:       clonePtr(opts.Env),
+		Modules:   clonePtr(opts.Modules),
+		App:       clonePtr(opts.App),
+		EE:        clonePtr(opts.EE),
+		Templates: slices.Clone(opts.Templates),
+		Repo:      clonePtr(opts.Repo),
+	}
+	if clone.Env != nil {
+		clone.Env.RestartPolicy = clonePtr(clone.Env.RestartPolicy)
+		clone.Env.Coredump = clonePtr(clone.Env.Coredump)
+		clone.Env.ResourceLimits = clonePtr(clone.Env.ResourceLimits)
+	}
+	if clone.Modules != nil {
+		clone.Modules.Directories = slices.Clone(clone.Modules.Directories)
+	}
+	return clone
+}
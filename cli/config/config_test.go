@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCliOpts_Clone(t *testing.T) {
+	assert.Nil(t, (*CliOpts)(nil).Clone())
+	assert.Equal(t, &CliOpts{}, (&CliOpts{}).Clone())
+
+	opts := &CliOpts{
+		Env: &TtEnvOpts{
+			BinDir:         "bin",
+			RestartPolicy:  &RestartPolicyOpts{MaxRestarts: 3},
+			Coredump:       &CoredumpOpts{Capture: true},
+			ResourceLimits: &LimitsOpts{CPUQuota: 50},
+		},
+		Modules:   &ModulesOpts{Directories: NewSingleOrArray("modules")},
+		App:       &AppOpts{RunDir: "var/run"},
+		EE:        &EEOpts{CredPath: "creds"},
+		Templates: []TemplateOpts{{Path: "templates"}},
+		Repo:      &RepoOpts{Rocks: "rocks"},
+	}
+	clone := opts.Clone()
+	require.Equal(t, opts, clone)
+
+	clone.Env.BinDir = "other"
+	clone.Env.RestartPolicy.MaxRestarts = 5
+	clone.Env.Coredump.Capture = false
+	clone.Env.ResourceLimits.CPUQuota = 10
+	clone.Modules.Directories[0] = "other"
+	clone.App.RunDir = "other"
+	clone.EE.CredPath = "other"
+	clone.Templates[0].Path = "other"
+	clone.Repo.Rocks = "other"
+
+	assert.Equal(t, &CliOpts{
+		Env: &TtEnvOpts{
+			BinDir:         "bin",
+			RestartPolicy:  &RestartPolicyOpts{MaxRestarts: 3},
+			Coredump:       &CoredumpOpts{Capture: true},
+			ResourceLimits: &LimitsOpts{CPUQuota: 50},
+		},
+		Modules:   &ModulesOpts{Directories: NewSingleOrArray("modules")},
+		App:       &AppOpts{RunDir: "var/run"},
+		EE:        &EEOpts{CredPath: "creds"},
+		Templates: []TemplateOpts{{Path: "templates"}},
+		Repo:      &RepoOpts{Rocks: "rocks"},
+	}, opts)
+}
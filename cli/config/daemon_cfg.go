@@ -18,6 +18,17 @@ type DaemonCfg struct {
 //	listen_interface: string
 //	port: num
 //	pidfile: string (file name)
+//	audit_log: string (file name)
+//	tls:
+//	  cert_file: path
+//	  key_file: path
+//	  ca_file: path
+//	auth:
+//	  - name: string
+//	    token: string
+//	    common_name: string
+//	    commands: [string]
+//	    instances: [string]
 type DaemonOpts struct {
 	// PIDFile is name of file contains pid of daemon process.
 	PIDFile string `mapstructure:"pidfile"`
@@ -33,4 +44,40 @@ type DaemonOpts struct {
 	// RunDir is a path to directory that stores various instance
 	// runtime artifacts like console socket, PID file, etc.
 	RunDir string `mapstructure:"run_dir" yaml:"run_dir"`
+	// AuditLog is a name of file contains audit log of daemon http API requests.
+	AuditLog string `mapstructure:"audit_log" yaml:"audit_log"`
+	// TLS contains TLS options of daemon http server. TLS is disabled if nil.
+	TLS *DaemonTLSOpts `mapstructure:"tls" yaml:"tls"`
+	// Auth is a list of clients allowed to use daemon http API. Any client
+	// is allowed if the list is empty.
+	Auth []DaemonClientOpts `mapstructure:"auth" yaml:"auth"`
+}
+
+// DaemonTLSOpts stores TLS options of tt daemon http server.
+type DaemonTLSOpts struct {
+	// CertFile is a path to the server certificate file.
+	CertFile string `mapstructure:"cert_file" yaml:"cert_file"`
+	// KeyFile is a path to the server private key file.
+	KeyFile string `mapstructure:"key_file" yaml:"key_file"`
+	// CaFile is a path to the CA certificates file to verify client
+	// certificates. Clients must present a certificate if it is set.
+	CaFile string `mapstructure:"ca_file" yaml:"ca_file"`
+}
+
+// DaemonClientOpts describes a client of tt daemon http API.
+type DaemonClientOpts struct {
+	// Name is a name of the client used in the audit log.
+	Name string `mapstructure:"name" yaml:"name"`
+	// Token is a bearer token of the client. It requires TLS since the token
+	// is sent in plain text.
+	Token string `mapstructure:"token" yaml:"token"`
+	// CommonName is a common name of the client certificate.
+	CommonName string `mapstructure:"common_name" yaml:"common_name"`
+	// Commands is a list of commands allowed for the client. All commands
+	// are allowed if the list is empty.
+	Commands []string `mapstructure:"commands" yaml:"commands"`
+	// Instances is a list of applications and instances allowed for the
+	// client in the `app` or `app:instance` form. All instances are allowed
+	// if the list is empty.
+	Instances []string `mapstructure:"instances" yaml:"instances"`
 }
@@ -31,6 +31,8 @@ const (
 	defaultDaemonPidFile = "tt_daemon.pid"
 	defaultDaemonLogFile = "tt_daemon.log"
 
+	defaultDaemonAuditLogFile = "tt_daemon_audit.log"
+
 	daemonCfgPath     = "tt_daemon.yaml"
 	configHomeEnvName = "XDG_CONFIG_HOME"
 )
@@ -155,9 +157,33 @@ func getDefaultDaemonOpts() *config.DaemonOpts {
 		LogDir:          VarLogPath,
 		LogFile:         defaultDaemonLogFile,
 		ListenInterface: "",
+		AuditLog:        defaultDaemonAuditLogFile,
 	}
 }
 
+// validateDaemonAuth checks authentication options of tt daemon.
+func validateDaemonAuth(opts *config.DaemonOpts) error {
+	if opts.TLS != nil && (opts.TLS.CertFile == "" || opts.TLS.KeyFile == "") {
+		return fmt.Errorf("both cert_file and key_file must be set in the tls section")
+	}
+	for i, client := range opts.Auth {
+		if (client.Token == "") == (client.CommonName == "") {
+			return fmt.Errorf("auth client #%d: exactly one of token and common_name "+
+				"must be set", i+1)
+		}
+		// Bearer tokens are sent in plain text without TLS.
+		if client.Token != "" && opts.TLS == nil {
+			return fmt.Errorf("auth client #%d: token requires the tls section to protect "+
+				"the token in transit", i+1)
+		}
+		if client.CommonName != "" && (opts.TLS == nil || opts.TLS.CaFile == "") {
+			return fmt.Errorf("auth client #%d: common_name requires tls.ca_file to verify "+
+				"client certificates", i+1)
+		}
+	}
+	return nil
+}
+
 // adjustPathWithConfigLocation adjust provided filePath with configDir.
 // Absolute filePath is returned as is. Relative filePath is calculated relative to configDir.
 // If filePath is empty, defaultDirName is appended to configDir.
@@ -374,6 +400,22 @@ func GetDaemonOpts(configurePath string) (*config.DaemonOpts, error) {
 			VarLogPath)
 	}
 
+	if cfg.DaemonConfig.AuditLog == "" {
+		cfg.DaemonConfig.AuditLog = defaultDaemonAuditLogFile
+	}
+
+	if tls := cfg.DaemonConfig.TLS; tls != nil {
+		for _, path := range []*string{&tls.CertFile, &tls.KeyFile, &tls.CaFile} {
+			if *path != "" && !filepath.IsAbs(*path) {
+				*path = filepath.Join(filepath.Dir(configurePath), *path)
+			}
+		}
+	}
+
+	if err := validateDaemonAuth(cfg.DaemonConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon configuration: %s", err)
+	}
+
 	return cfg.DaemonConfig, nil
 }
 
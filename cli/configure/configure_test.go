@@ -404,3 +404,82 @@ func TestGetCliOpts_modules_directory(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDaemonOpts_auth(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     string
+		wantErr string
+	}{
+		{
+			name: "token and certificate",
+			cfg: `daemon:
+  tls:
+    cert_file: server.crt
+    key_file: server.key
+    ca_file: /etc/ca.crt
+  auth:
+    - token: secret
+      commands: [status]
+    - common_name: ops
+`,
+		},
+		{
+			name: "no credentials",
+			cfg: `daemon:
+  auth:
+    - name: ci
+`,
+			wantErr: "auth client #1: exactly one of token and common_name must be set",
+		},
+		{
+			name: "token without tls",
+			cfg: `daemon:
+  auth:
+    - token: secret
+`,
+			wantErr: "auth client #1: token requires the tls section",
+		},
+		{
+			name: "certificate without ca",
+			cfg: `daemon:
+  auth:
+    - common_name: ops
+`,
+			wantErr: "auth client #1: common_name requires tls.ca_file",
+		},
+		{
+			name: "no key",
+			cfg: `daemon:
+  tls:
+    cert_file: server.crt
+`,
+			wantErr: "both cert_file and key_file must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfgPath := filepath.Join(dir, "tt_daemon.yaml")
+			require.NoError(t, os.WriteFile(cfgPath, []byte(tt.cfg), 0o644))
+
+			opts, err := GetDaemonOpts(cfgPath)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, defaultDaemonAuditLogFile, opts.AuditLog)
+			assert.Equal(t, &config.DaemonTLSOpts{
+				CertFile: filepath.Join(dir, "server.crt"),
+				KeyFile:  filepath.Join(dir, "server.key"),
+				CaFile:   "/etc/ca.crt",
+			}, opts.TLS)
+			assert.Equal(t, []config.DaemonClientOpts{
+				{Token: "secret", Commands: []string{"status"}},
+				{CommonName: "ops"},
+			}, opts.Auth)
+		})
+	}
+}
@@ -3,13 +3,16 @@ package connect
 import (
 	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
 
 	"github.com/tarantool/tt/cli/formatter"
+	"github.com/tarantool/tt/cli/util"
 )
 
 // cmd is the interface that must be implemented by a console command.
@@ -27,6 +30,7 @@ var (
 	_ cmd = argSetCmdDecorator{}
 	_ cmd = argUnsignedCmdDecorator{}
 	_ cmd = argBooleanCmdDecorator{}
+	_ cmd = rawArgsCmdDecorator{}
 )
 
 var (
@@ -238,6 +242,32 @@ func (command argBooleanCmdDecorator) Run(console *Console,
 	return command.base.Run(console, cmd, args)
 }
 
+// rawArgsCmdDecorator is a decorator for a command that accepts arguments
+// as is, without conversion to the lower case.
+type rawArgsCmdDecorator struct {
+	base cmd
+}
+
+// newRawArgsCmdDecorator creates a new rawArgsCmdDecorator object from a
+// base command.
+func newRawArgsCmdDecorator(base cmd) rawArgsCmdDecorator {
+	return rawArgsCmdDecorator{
+		base: base,
+	}
+}
+
+// Aliases returns aliases of the base command.
+func (command rawArgsCmdDecorator) Aliases() []string {
+	return command.base.Aliases()
+}
+
+// Run runs the base command.
+func (command rawArgsCmdDecorator) Run(console *Console,
+	cmd string, args []string,
+) (string, error) {
+	return command.base.Run(console, cmd, args)
+}
+
 // cmdInfo describes an additional information about a command.
 type cmdInfo struct {
 	// Short is a short help description for the command.
@@ -426,6 +456,133 @@ func getHistoryFunc(console *Console, _ string, _ []string) (string, error) {
 	return strings.Join(outputData, "\n-----\n"), nil
 }
 
+// setVarFunc sets, unsets or shows variables for statement placeholders.
+func setVarFunc(console *Console, cmd string, args []string) (string, error) {
+	if len(args) == 0 {
+		if len(console.vars) == 0 {
+			return "", nil
+		}
+		out, err := yaml.Marshal(console.vars)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode variables: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	name := args[0]
+	if !isValidVarName(name) {
+		return "", fmt.Errorf("invalid variable name: %s", name)
+	}
+	if len(args) == 1 {
+		delete(console.vars, name)
+		return "", nil
+	}
+	if console.vars == nil {
+		console.vars = map[string]interface{}{}
+	}
+	console.vars[name] = parseVarValue(strings.Join(args[1:], " "))
+	return "", nil
+}
+
+// setVarsFromResultFunc sets variables from named columns of the last result.
+// An optional argument is a prefix for the variable names.
+func setVarsFromResultFunc(console *Console, cmd string, args []string) (string, error) {
+	prefix := ""
+	switch len(args) {
+	case 0:
+	case 1:
+		prefix = args[0]
+	default:
+		return "", fmt.Errorf("the command expects zero or single argument")
+	}
+	if console.lastResult == "" {
+		return "", fmt.Errorf("there is no result of a previous statement")
+	}
+
+	vars, err := getResultVars(console.lastResult)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !isValidVarName(prefix + name) {
+			return "", fmt.Errorf("invalid variable name: %s", prefix+name)
+		}
+	}
+	if console.vars == nil {
+		console.vars = map[string]interface{}{}
+	}
+	for name, value := range vars {
+		console.vars[prefix+name] = value
+	}
+	return "", nil
+}
+
+// isEditCmd returns true if the input is an edit command.
+func isEditCmd(in string) bool {
+	tokens := strings.Fields(in)
+	return len(tokens) > 0 && util.Find(editStmt, strings.ToLower(tokens[0])) != -1
+}
+
+// editFunc opens the file, the current unfinished statement or the last
+// executed statement in a text editor. The saved text is set as a completed
+// statement of the console to execute.
+func editFunc(console *Console, cmd string, args []string) (string, error) {
+	var path string
+	switch len(args) {
+	case 0:
+		ext := ".lua"
+		if console.language == SQLLanguage {
+			ext = ".sql"
+		}
+		file, err := os.CreateTemp("", "tt_connect_*"+ext)
+		if err != nil {
+			return "", fmt.Errorf("failed to create a temporary file: %w", err)
+		}
+		path = file.Name()
+		defer os.Remove(path)
+
+		text := console.input
+		if text == "" && console.history != nil && len(console.history.commands) > 0 {
+			text = console.history.commands[len(console.history.commands)-1]
+		}
+		_, err = file.WriteString(text)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write a temporary file: %w", err)
+		}
+	case 1:
+		path = args[0]
+	default:
+		return "", fmt.Errorf("the command expects zero or single argument")
+	}
+
+	if err := util.EditFile(path); err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the edited file: %w", err)
+	}
+
+	stmt, _ := cleanupDelimiter(string(content), console.delimiter)
+	stmt = strings.TrimSpace(stmt)
+	if stmt == "" {
+		console.input = ""
+		console.livePrefixEnabled = false
+		return "", nil
+	}
+	console.input = stmt
+	console.edited = true
+	return stmt, nil
+}
+
 // setQuitFunc sets the quit flag for the console.
 func setQuitFunc(console *Console, cmd string, arg []string) (string, error) {
 	console.quit = true
@@ -495,6 +652,20 @@ var cmdInfos = []cmdInfo{
 			[]string{},
 		),
 	},
+	{
+		Short: setVar + " [<name> [<value>]]",
+		Long:  "set, unset or show variables for :name placeholders",
+		Cmd: newRawArgsCmdDecorator(
+			newBaseCmd([]string{setVar}, setVarFunc),
+		),
+	},
+	{
+		Short: setVarsFromResult + " [<prefix>]",
+		Long:  "set variables from columns of the last single row result",
+		Cmd: newRawArgsCmdDecorator(
+			newBaseCmd([]string{setVarsFromResult}, setVarsFromResultFunc),
+		),
+	},
 	{
 		Short: setTableColumnWidthMaxShort + " <width>",
 		Long:  "set max column width for table/ttable",
@@ -577,6 +748,13 @@ var cmdInfos = []cmdInfo{
 			newBaseCmd([]string{getHistoryList}, getHistoryFunc),
 		),
 	},
+	{
+		Short: strings.Join(editStmt, ", ") + " [file]",
+		Long:  "edit the statement or the file in $EDITOR and execute it",
+		Cmd: newRawArgsCmdDecorator(
+			newBaseCmd(editStmt, editFunc),
+		),
+	},
 	// The Tarantool console has `\quit` command, but it requires execute
 	// access.
 	{
@@ -633,7 +811,11 @@ func (executor cmdExecutor) Execute(console *Console, in string) bool {
 	for i := len(tokens); i > 0; i-- {
 		key := strings.Join(tokens[:i], " ")
 		if cmd, ok := executor.cmds[key]; ok {
-			msg, err := cmd.Run(console, key, lowerTokens[i:])
+			args := lowerTokens[i:]
+			if _, ok := cmd.(rawArgsCmdDecorator); ok {
+				args = tokens[i:]
+			}
+			msg, err := cmd.Run(console, key, args)
 			if err != nil {
 				log.Errorf("%s\n", err)
 			} else if msg != "" {
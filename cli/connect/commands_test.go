@@ -1,10 +1,14 @@
 package connect
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/util"
 )
 
 func TestConsoleHistoryOutput(t *testing.T) {
@@ -59,3 +63,148 @@ func TestConsoleHistoryOutput(t *testing.T) {
 		assert.Equal(t, "test2\n-----\ntest3\n-----\ntest4", actual)
 	})
 }
+
+func TestIsEditCmd(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected bool
+	}{
+		{"\\e", true},
+		{"  \\edit  ", true},
+		{"\\E file.lua", true},
+		{"\\edit file.lua", true},
+		{"\\editor", false},
+		{"\\x", false},
+		{"", false},
+		{"return 1", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isEditCmd(tc.in))
+		})
+	}
+}
+
+func TestEditFunc(t *testing.T) {
+	tmpDir := t.TempDir()
+	// The editor saves the original text and replaces it with a new one.
+	editor := filepath.Join(tmpDir, "editor.sh")
+	require.NoError(t, os.WriteFile(editor, []byte(`#!/bin/sh
+cp "$1" "`+filepath.Join(tmpDir, "original")+`"
+printf 'return 42;;\n' > "$1"
+`), 0o755))
+	t.Setenv(util.EditorEnv, editor)
+
+	readOriginal := func() string {
+		data, err := os.ReadFile(filepath.Join(tmpDir, "original"))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("last statement", func(t *testing.T) {
+		history, err := newCommandHistory("test", 100)
+		require.NoError(t, err)
+		history.appendCommand("return 1")
+		history.appendCommand("return 2")
+		console := Console{history: history, delimiter: ";;"}
+
+		msg, err := editFunc(&console, "\\e", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "return 2", readOriginal())
+		assert.Equal(t, "return 42", msg)
+		assert.Equal(t, "return 42", console.input)
+		assert.True(t, console.edited)
+	})
+
+	t.Run("current statement", func(t *testing.T) {
+		history, err := newCommandHistory("test", 100)
+		require.NoError(t, err)
+		history.appendCommand("return 1")
+		console := Console{history: history, input: "function f()\nreturn 3"}
+
+		msg, err := editFunc(&console, "\\edit", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "function f()\nreturn 3", readOriginal())
+		assert.Equal(t, "return 42;;", msg)
+		assert.Equal(t, "return 42;;", console.input)
+		assert.True(t, console.edited)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "Script.lua")
+		require.NoError(t, os.WriteFile(path, []byte("return 0"), 0o644))
+		console := Console{}
+
+		msg, err := editFunc(&console, "\\e", []string{path})
+		require.NoError(t, err)
+		assert.Equal(t, "return 0", readOriginal())
+		assert.Equal(t, "return 42;;", msg)
+		assert.Equal(t, "return 42;;", console.input)
+		assert.True(t, console.edited)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "empty.sh")
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n: > \"$1\"\n"), 0o755))
+		t.Setenv(util.EditorEnv, path)
+		console := Console{input: "return", livePrefixEnabled: true}
+
+		msg, err := editFunc(&console, "\\e", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "", msg)
+		assert.Equal(t, "", console.input)
+		assert.False(t, console.livePrefixEnabled)
+		assert.False(t, console.edited)
+	})
+
+	t.Run("too many arguments", func(t *testing.T) {
+		console := Console{}
+		_, err := editFunc(&console, "\\e", []string{"a", "b"})
+		assert.ErrorContains(t, err, "the command expects zero or single argument")
+	})
+}
+
+func TestSetVarFunc(t *testing.T) {
+	console := Console{}
+
+	_, err := setVarFunc(&console, setVar, []string{"id", "42"})
+	require.NoError(t, err)
+	_, err = setVarFunc(&console, setVar, []string{"Name", "'John", "Smith'"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": int64(42), "Name": "John Smith"},
+		console.vars)
+
+	out, err := setVarFunc(&console, setVar, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Name: John Smith\nid: 42", out)
+
+	_, err = setVarFunc(&console, setVar, []string{"id"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": "John Smith"}, console.vars)
+
+	_, err = setVarFunc(&console, setVar, []string{"1id", "1"})
+	assert.EqualError(t, err, "invalid variable name: 1id")
+}
+
+func TestSetVarsFromResultFunc(t *testing.T) {
+	console := Console{}
+	_, err := setVarsFromResultFunc(&console, setVarsFromResult, nil)
+	assert.EqualError(t, err, "there is no result of a previous statement")
+
+	console.lastResult = "---\n- {id: 1, name: abc}\n...\n"
+	_, err = setVarsFromResultFunc(&console, setVarsFromResult, nil)
+	require.NoError(t, err)
+	_, err = setVarsFromResultFunc(&console, setVarsFromResult, []string{"last_"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id": 1, "name": "abc", "last_id": 1, "last_name": "abc",
+	}, console.vars)
+
+	_, err = setVarsFromResultFunc(&console, setVarsFromResult, []string{"1"})
+	assert.EqualError(t, err, "invalid variable name: 1id")
+
+	console.lastResult = "---\n- 1\n...\n"
+	_, err = setVarsFromResultFunc(&console, setVarsFromResult, nil)
+	assert.ErrorIs(t, err, errNotSingleRow)
+}
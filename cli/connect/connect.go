@@ -43,6 +43,8 @@ type ConnectCtx struct {
 	Binary bool
 	// Evaler lua expression.
 	Evaler string
+	// RecordFile is a path to a file to record the console session into.
+	RecordFile string
 }
 
 const (
@@ -106,11 +108,12 @@ func Eval(connectCtx ConnectCtx, connOpts connector.ConnectOpts, args []string)
 		if err := ChangeLanguage(conn, connectCtx.Language); err != nil {
 			return nil, fmt.Errorf("unable to change a language: %s", err)
 		}
-		evalArgs = append(evalArgs, false)
+		// There are no placeholder values for the script.
+		evalArgs = append(evalArgs, false, nil)
 	} else {
 		needMetaInfo := connectCtx.Format == formatter.TableFormat ||
 			connectCtx.Format == formatter.TTableFormat
-		evalArgs = append(evalArgs, needMetaInfo)
+		evalArgs = append(evalArgs, needMetaInfo, nil)
 		for i := range args {
 			evalArgs = append(evalArgs, args[i])
 		}
@@ -2,6 +2,7 @@ package connect
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"github.com/tarantool/tt/cli/connect/internal/luabody"
 	"github.com/tarantool/tt/cli/connector"
 	"github.com/tarantool/tt/cli/formatter"
+	"github.com/tarantool/tt/cli/util"
 )
 
 // EvalFunc defines a function type for evaluating an expression via connection.
@@ -37,6 +39,10 @@ var (
 	ControlRightBytes []byte
 )
 
+// errConnectionClosed is returned if the instance closes the connection
+// without a result.
+var errConnectionClosed = errors.New("connection closed")
+
 func init() {
 	ControlLeftBytes = []byte{0x1b, 0x62}
 	ControlRightBytes = []byte{0x1b, 0x66}
@@ -53,8 +59,20 @@ type Console struct {
 	format     formatter.Format
 	formatOpts formatter.Opts
 	quit       bool
+	// edited is true if the input is set by the edit command and it should
+	// be executed as a completed statement.
+	edited bool
+	// vars are values for :name placeholders in statements.
+	vars map[string]interface{}
+	// lastResult is the YAML result of the last executed statement.
+	lastResult string
+	// customEvaler is true if the statements are evaluated with a custom
+	// evaler. Placeholders are not supported in this case.
+	customEvaler bool
 
 	history *commandHistory
+	// recorder writes executed inputs into a session file if set.
+	recorder *sessionRecorder
 
 	prefix            string
 	livePrefixEnabled bool
@@ -94,7 +112,9 @@ func NewConsole(connOpts connector.ConnectOpts, connectCtx ConnectCtx, title str
 			ColumnWidthMax: 0,
 			TableDialect:   formatter.DefaultTableDialect,
 		},
-		quit: false,
+		quit:         false,
+		vars:         map[string]interface{}{},
+		customEvaler: connectCtx.Evaler != "",
 	}
 
 	var err error
@@ -110,6 +130,13 @@ func NewConsole(connOpts connector.ConnectOpts, connectCtx ConnectCtx, title str
 		log.Debugf("Failed to initialize console history: %s", err)
 	}
 
+	if connectCtx.RecordFile != "" {
+		console.recorder, err = newSessionRecorder(connectCtx.RecordFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Connect to specified address.
 	console.conn, err = connector.Connect(connOpts)
 	if err != nil {
@@ -185,6 +212,77 @@ func (console *Console) Close() {
 	if console.conn != nil {
 		console.conn.Close()
 	}
+	if console.recorder != nil {
+		console.recorder.close()
+		console.recorder = nil
+	}
+}
+
+// recordInput writes the executed input and its result into the session file
+// if the recording is enabled.
+func (console *Console) recordInput(input, output string, err error) {
+	if console.recorder == nil {
+		return
+	}
+	entry := SessionEntry{
+		Time:     time.Now(),
+		Instance: console.title,
+		Language: console.language.String(),
+		Input:    input,
+		Output:   output,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := console.recorder.record(entry); err != nil {
+		log.Warn(err.Error())
+	}
+}
+
+// eval executes the completed statement on the instance and returns the
+// result in YAML. Placeholders of the statement are passed as request
+// parameters.
+func (console *Console) eval(evalBody, input string) (string, error) {
+	var binds interface{}
+	if !console.customEvaler {
+		stmt, values, err := bindVars(input, console.language, console.vars)
+		if err != nil {
+			return formatErrorResult(err), nil
+		}
+		input = stmt
+		if values != nil {
+			binds = values
+		}
+	}
+
+	var results []string
+	needMetaInfo := console.format == formatter.TableFormat ||
+		console.format == formatter.TTableFormat
+	args := []interface{}{
+		input, console.language == SQLLanguage,
+		needMetaInfo, binds,
+	}
+	opts := connector.RequestOpts{
+		PushCallback: func(pushedData interface{}) {
+			encodedData, err := yaml.Marshal(pushedData)
+			if err != nil {
+				log.Warnf("Failed to encode pushed data: %s", err)
+				return
+			}
+
+			fmt.Printf("%s\n", encodedData)
+		},
+		ResData: &results,
+	}
+
+	if _, err := console.conn.Eval(evalBody, args, opts); err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", errConnectionClosed
+	}
+	console.lastResult = results[0]
+	return results[0], nil
 }
 
 // getExecutor returns command executor.
@@ -196,26 +294,8 @@ func getExecutor(console *Console, connectCtx ConnectCtx) (func(string), error)
 		return nil, err
 	}
 
-	executor := func(in string) {
-		if console.input == "" {
-			if commandsExecutor.Execute(console, in) {
-				if console.quit {
-					console.Close()
-					log.Infof("Quit from the console")
-					os.Exit(0)
-				}
-				return
-			}
-		}
-
-		var completed bool
-		validator := console.validators[console.language]
-		console.input, completed = AddStmtPart(console.input, in, console.delimiter, validator)
-		if !completed {
-			console.livePrefixEnabled = true
-			return
-		}
-
+	// execute executes the completed statement from the console input.
+	execute := func() {
 		trimmedInput := strings.TrimSpace(console.input)
 		if console.history != nil {
 			console.history.appendCommand(trimmedInput)
@@ -230,42 +310,19 @@ func getExecutor(console *Console, connectCtx ConnectCtx) (func(string), error)
 			}
 		}
 
-		var results []string
-		needMetaInfo := console.format == formatter.TableFormat ||
-			console.format == formatter.TTableFormat
-		args := []interface{}{
-			console.input, console.language == SQLLanguage,
-			needMetaInfo,
-		}
-		opts := connector.RequestOpts{
-			PushCallback: func(pushedData interface{}) {
-				encodedData, err := yaml.Marshal(pushedData)
-				if err != nil {
-					log.Warnf("Failed to encode pushed data: %s", err)
-					return
-				}
-
-				fmt.Printf("%s\n", encodedData)
-			},
-			ResData: &results,
-		}
-
-		var data string
-		if _, err := console.conn.Eval(evalBody, args, opts); err != nil {
-			if err == io.EOF {
-				// We need to call 'console.Close()' here because in some cases (e.g 'os.exit()')
-				// it won't be called from 'defer console.Close' in 'connect.runConsole()'.
-				console.Close()
-				log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
-			} else {
-				log.Fatalf("Failed to execute command: %s", err)
-			}
-		} else if len(results) == 0 {
+		data, err := console.eval(evalBody, console.input)
+		console.recordInput(trimmedInput, data, err)
+		if err == io.EOF {
+			// We need to call 'console.Close()' here because in some cases (e.g 'os.exit()')
+			// it won't be called from 'defer console.Close' in 'connect.runConsole()'.
+			console.Close()
+			log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
+		} else if err == errConnectionClosed {
 			console.Close()
 			log.Infof("Connection closed")
 			os.Exit(0)
-		} else {
-			data = results[0]
+		} else if err != nil {
+			log.Fatalf("Failed to execute command: %s", err)
 		}
 
 		output, err := formatter.MakeOutput(console.format, data, console.formatOpts)
@@ -280,6 +337,37 @@ func getExecutor(console *Console, connectCtx ConnectCtx) (func(string), error)
 		console.livePrefixEnabled = false
 	}
 
+	executor := func(in string) {
+		// The edit command works with an unfinished statement too.
+		if console.input == "" || isEditCmd(in) {
+			if commandsExecutor.Execute(console, in) {
+				if console.edited {
+					console.edited = false
+					execute()
+					return
+				}
+				if !isEditCmd(in) {
+					console.recordInput(strings.TrimSpace(in), "", nil)
+				}
+				if console.quit {
+					console.Close()
+					log.Infof("Quit from the console")
+					os.Exit(0)
+				}
+				return
+			}
+		}
+
+		var completed bool
+		validator := console.validators[console.language]
+		console.input, completed = AddStmtPart(console.input, in, console.delimiter, validator)
+		if !completed {
+			console.livePrefixEnabled = true
+			return
+		}
+		execute()
+	}
+
 	signaller_executor := func(in string) {
 		// Signal handler.
 		handleSignals := func(console *Console, stop chan struct{}) {
@@ -425,5 +513,11 @@ func getPromptOptions(console *Console) []prompt.Option {
 		options = append(options, prompt.OptionHistory(console.history.commands))
 	}
 
+	// The syntax of a custom evaler input is unknown.
+	if util.IsColorEnabled() && !console.customEvaler {
+		options = append(options,
+			prompt.OptionWriter(newHighlightWriter(prompt.NewStdoutWriter(), console)))
+	}
+
 	return options
 }
@@ -22,6 +22,13 @@ const setTableColumnWidthMaxLong = "\\set table_column_width"
 // setDelimiter set a custom expression delimiter for Tarantool console.
 const setDelimiter = "\\set delimiter"
 
+// setVar is a command to set a variable for statement placeholders.
+const setVar = "\\set var"
+
+// setVarsFromResult is a command to set variables from columns of the last
+// result.
+const setVarsFromResult = "\\gset"
+
 // setTableColumnWidthMaxShort is a short command to set a maximum columns
 // width for tables.
 const setTableColumnWidthMaxShort = "\\xw"
@@ -58,6 +65,10 @@ const getShortcutsList = "\\shortcuts"
 // getHistoryList is a command to get history of executed commands.
 const getHistoryList = "\\history"
 
+// editStmt is a command to edit a statement or a file in a text editor and
+// execute it.
+var editStmt = []string{"\\edit", "\\e"}
+
 // getHelpCmd is a command to get a help message.
 var getHelp = []string{"\\help", "?"}
 
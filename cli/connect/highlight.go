@@ -0,0 +1,310 @@
+package connect
+
+import (
+	"strings"
+
+	"github.com/tarantool/go-prompt"
+)
+
+// tokenKind is a kind of a highlighted token.
+type tokenKind int
+
+const (
+	tokenText tokenKind = iota
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenComment
+	tokenUnmatched
+)
+
+// tokenColors are colors of the highlighted tokens.
+var tokenColors = map[tokenKind]struct {
+	fg   prompt.Color
+	bg   prompt.Color
+	bold bool
+}{
+	tokenText:      {prompt.DefaultColor, prompt.DefaultColor, false},
+	tokenKeyword:   {prompt.Purple, prompt.DefaultColor, true},
+	tokenString:    {prompt.DarkGreen, prompt.DefaultColor, false},
+	tokenNumber:    {prompt.Cyan, prompt.DefaultColor, false},
+	tokenComment:   {prompt.DarkGray, prompt.DefaultColor, false},
+	tokenUnmatched: {prompt.White, prompt.DarkRed, true},
+}
+
+// toSet creates a set from the words.
+func toSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+var (
+	// luaHighlightKeywords are reserved words of Lua.
+	luaHighlightKeywords = toSet(
+		"and", "break", "do", "else", "elseif", "end", "false", "for",
+		"function", "goto", "if", "in", "local", "nil", "not", "or", "repeat",
+		"return", "then", "true", "until", "while",
+	)
+	// sqlHighlightKeywords are common keywords of Tarantool SQL in upper case.
+	sqlHighlightKeywords = toSet(
+		"ADD", "ALL", "ALTER", "AND", "ANY", "ARRAY", "AS", "ASC",
+		"AUTOINCREMENT", "BEGIN", "BETWEEN", "BOOLEAN", "BY", "CASE", "CAST",
+		"CHECK", "COLLATE", "COMMIT", "CONSTRAINT", "CREATE", "DECIMAL",
+		"DEFAULT", "DELETE", "DESC", "DISTINCT", "DOUBLE", "DROP", "ELSE",
+		"END", "ENGINE", "EXISTS", "EXPLAIN", "FALSE", "FOREIGN", "FROM",
+		"GROUP", "HAVING", "IF", "IN", "INDEX", "INNER", "INSERT", "INTEGER",
+		"INTO", "IS", "JOIN", "KEY", "LEFT", "LIKE", "LIMIT", "MAP", "NOT",
+		"NULL", "NUMBER", "OFFSET", "ON", "OR", "ORDER", "OUTER", "PRAGMA",
+		"PRIMARY", "REFERENCES", "RELEASE", "RENAME", "REPLACE", "RIGHT",
+		"ROLLBACK", "SAVEPOINT", "SCALAR", "SELECT", "SET", "SHOW", "STRING",
+		"TABLE", "THEN", "TO", "TRANSACTION", "TRIGGER", "TRUE", "UNION",
+		"UNIQUE", "UNSIGNED", "UPDATE", "UUID", "VALUES", "VARBINARY",
+		"VARCHAR", "VIEW", "WHEN", "WHERE", "WITH",
+	)
+)
+
+// closingBrackets maps closing brackets to the opening ones.
+var closingBrackets = map[string]string{
+	")": "(",
+	"]": "[",
+	"}": "{",
+}
+
+// blockOpener is an opened bracket or a Lua block.
+type blockOpener struct {
+	// token is a bracket or a keyword that opens the block.
+	token string
+	// hasDo is true if a loop block already has the `do` keyword.
+	hasDo bool
+}
+
+// blockMatcher finds unmatched closing brackets and Lua blocks.
+type blockMatcher struct {
+	stack []blockOpener
+}
+
+// open opens a new block.
+func (matcher *blockMatcher) open(token string) {
+	matcher.stack = append(matcher.stack, blockOpener{token: token})
+}
+
+// top returns the last opened block or nil if there is no opened blocks.
+func (matcher *blockMatcher) top() *blockOpener {
+	if len(matcher.stack) == 0 {
+		return nil
+	}
+	return &matcher.stack[len(matcher.stack)-1]
+}
+
+// close closes the last opened block if it is one of the expected blocks.
+// It returns false if the closing token is unmatched.
+func (matcher *blockMatcher) close(expected ...string) bool {
+	top := matcher.top()
+	if top == nil {
+		return false
+	}
+	for _, token := range expected {
+		if top.token == token {
+			matcher.stack = matcher.stack[:len(matcher.stack)-1]
+			return true
+		}
+	}
+	return false
+}
+
+// luaWord processes a Lua keyword. It returns false if the keyword closes an
+// unmatched block.
+func (matcher *blockMatcher) luaWord(word string) bool {
+	switch word {
+	case "function", "if", "repeat":
+		matcher.open(word)
+	case "for", "while":
+		matcher.open("loop")
+	case "do":
+		// The `do` keyword is a part of the loop or a separate block.
+		if top := matcher.top(); top != nil && top.token == "loop" && !top.hasDo {
+			top.hasDo = true
+		} else {
+			matcher.open(word)
+		}
+	case "end":
+		return matcher.close("function", "if", "loop", "do")
+	case "until":
+		return matcher.close("repeat")
+	}
+	return true
+}
+
+// skipNumber returns a position after the number starting at the position.
+func skipNumber(text string, pos int) int {
+	isHex := strings.HasPrefix(text[pos:], "0x") || strings.HasPrefix(text[pos:], "0X")
+	end := pos
+	for end < len(text) {
+		c := text[end]
+		if isIdentChar(c) || c == '.' {
+			end++
+			continue
+		}
+		// A sign of the exponent.
+		if c == '+' || c == '-' {
+			prev := text[end-1] | 0x20
+			if !isHex && prev == 'e' || isHex && prev == 'p' {
+				end++
+				continue
+			}
+		}
+		break
+	}
+	return end
+}
+
+// highlight returns a token kind of each byte of the text in the language.
+// Backslash commands are not highlighted.
+func highlight(text string, lang Language) []tokenKind {
+	kinds := make([]tokenKind, len(text))
+	if strings.HasPrefix(strings.TrimSpace(text), "\\") {
+		return kinds
+	}
+
+	isSql := lang == SQLLanguage
+	mark := func(start, end int, kind tokenKind) {
+		for i := start; i < end && i < len(kinds); i++ {
+			kinds[i] = kind
+		}
+	}
+
+	matcher := blockMatcher{}
+	for pos := 0; pos < len(text); {
+		c := text[pos]
+		switch {
+		case c == '\'' || c == '"':
+			end := skipQuoted(text, pos, !isSql)
+			if isSql && c == '"' {
+				// A quoted SQL identifier.
+				pos = end
+				continue
+			}
+			mark(pos, end, tokenString)
+			pos = end
+		case strings.HasPrefix(text[pos:], "--") ||
+			isSql && strings.HasPrefix(text[pos:], "/*"):
+			end := skipLiteral(text, pos, lang)
+			mark(pos, end, tokenComment)
+			pos = end
+		case !isSql && c == '[' && skipLuaLongBracket(text, pos) != pos:
+			end := skipLuaLongBracket(text, pos)
+			mark(pos, end, tokenString)
+			pos = end
+		case c >= '0' && c <= '9' ||
+			c == '.' && pos+1 < len(text) && text[pos+1] >= '0' && text[pos+1] <= '9':
+			end := skipNumber(text, pos)
+			mark(pos, end, tokenNumber)
+			pos = end
+		case isIdentChar(c):
+			end := pos
+			for end < len(text) && isIdentChar(text[end]) {
+				end++
+			}
+			word := text[pos:end]
+			if isSql {
+				if _, ok := sqlHighlightKeywords[strings.ToUpper(word)]; ok {
+					mark(pos, end, tokenKeyword)
+				}
+			} else if _, ok := luaHighlightKeywords[word]; ok {
+				// A keyword after a dot or a colon is a field name.
+				if pos > 0 && (text[pos-1] == '.' || text[pos-1] == ':') {
+					pos = end
+					continue
+				}
+				mark(pos, end, tokenKeyword)
+				if !matcher.luaWord(word) {
+					mark(pos, end, tokenUnmatched)
+				}
+			}
+			pos = end
+		case strings.ContainsRune("([{", rune(c)):
+			matcher.open(string(c))
+			pos++
+		case strings.ContainsRune(")]}", rune(c)):
+			if !matcher.close(closingBrackets[string(c)]) {
+				mark(pos, pos+1, tokenUnmatched)
+			}
+			pos++
+		default:
+			pos++
+		}
+	}
+	return kinds
+}
+
+// highlightWriter is a prompt.ConsoleWriter that highlights the syntax of
+// the console input. The input is written by the prompt right after the
+// prompt prefix.
+type highlightWriter struct {
+	prompt.ConsoleWriter
+	console *Console
+	// afterPrefix is true if the prompt prefix has just been written.
+	afterPrefix bool
+}
+
+// newHighlightWriter creates a new highlightWriter object.
+func newHighlightWriter(writer prompt.ConsoleWriter, console *Console) *highlightWriter {
+	return &highlightWriter{
+		ConsoleWriter: writer,
+		console:       console,
+	}
+}
+
+// WriteStr writes the string. It highlights the string if it is the input
+// after the prompt prefix.
+func (writer *highlightWriter) WriteStr(data string) {
+	if writer.afterPrefix {
+		writer.afterPrefix = false
+		writer.writeHighlighted(data)
+		return
+	}
+	if data != "" && (data == writer.console.prefix || data == writer.console.livePrefix) {
+		writer.afterPrefix = true
+	}
+	writer.ConsoleWriter.WriteStr(data)
+}
+
+// writeHighlighted writes the highlighted input. The unfinished statement is
+// used as a context of the input. The prompt splits wide lines with new line
+// characters, so they are excluded from the highlighted text.
+func (writer *highlightWriter) writeHighlighted(data string) {
+	context := ""
+	if writer.console.input != "" {
+		context = writer.console.input + "\n"
+	}
+	kinds := highlight(context+strings.ReplaceAll(data, "\n", ""), writer.console.language)
+	kinds = kinds[len(context):]
+
+	idx := 0
+	start := 0
+	current := tokenText
+	flush := func(end int) {
+		if start == end {
+			return
+		}
+		color := tokenColors[current]
+		writer.ConsoleWriter.SetColor(color.fg, color.bg, color.bold)
+		writer.ConsoleWriter.WriteStr(data[start:end])
+		start = end
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			continue
+		}
+		if kinds[idx] != current {
+			flush(i)
+			current = kinds[idx]
+		}
+		idx++
+	}
+	flush(len(data))
+	writer.ConsoleWriter.SetColor(prompt.DefaultColor, prompt.DefaultColor, false)
+}
@@ -0,0 +1,146 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tarantool/go-prompt"
+)
+
+// kindsString returns a string representation of the token kinds: one letter
+// for each byte.
+func kindsString(kinds []tokenKind) string {
+	letters := map[tokenKind]byte{
+		tokenText:      '.',
+		tokenKeyword:   'k',
+		tokenString:    's',
+		tokenNumber:    'n',
+		tokenComment:   'c',
+		tokenUnmatched: 'u',
+	}
+	var builder strings.Builder
+	for _, kind := range kinds {
+		builder.WriteByte(letters[kind])
+	}
+	return builder.String()
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		text     string
+		lang     Language
+		expected string
+	}{
+		{
+			text:     "local x = 'a' -- c",
+			lang:     LuaLanguage,
+			expected: "kkkkk.....sss.cccc",
+		},
+		{
+			text:     `return "a\"b", [[x]], 0x1F, 1.5e-3, x1`,
+			lang:     DefaultLanguage,
+			expected: `kkkkkk.ssssss..sssss..nnnn..nnnnnn....`,
+		},
+		{
+			text:     "if x then f(t.end) end end",
+			lang:     LuaLanguage,
+			expected: "kk...kkkk..........kkk.uuu",
+		},
+		{
+			text:     "for i = 1, 2 do end while 1 do end do end",
+			lang:     LuaLanguage,
+			expected: "kkk.....n..n.kk.kkk.kkkkk.n.kk.kkk.kk.kkk",
+		},
+		{
+			text:     "repeat until x until",
+			lang:     LuaLanguage,
+			expected: "kkkkkk.kkkkk...uuuuu",
+		},
+		{
+			text:     "f({1, 2)]",
+			lang:     LuaLanguage,
+			expected: "...n..nuu",
+		},
+		{
+			text:     "function f() return (1 end",
+			lang:     LuaLanguage,
+			expected: "kkkkkkkk.....kkkkkk..n.uuu",
+		},
+		{
+			text:     "select \"end\", 'x''y' from t where id = 1 /* c */ -- end)",
+			lang:     SQLLanguage,
+			expected: "kkkkkk........ssssss.kkkk...kkkkk......n.ccccccc.ccccccc",
+		},
+		{
+			text:     "Select (1))",
+			lang:     SQLLanguage,
+			expected: "kkkkkk..n.u",
+		},
+		{
+			text:     "\\set language sql",
+			lang:     LuaLanguage,
+			expected: ".................",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, kindsString(highlight(tc.text, tc.lang)))
+		})
+	}
+}
+
+// recordWriter is a prompt.ConsoleWriter that records written strings and
+// colors.
+type recordWriter struct {
+	prompt.ConsoleWriter
+	records []string
+}
+
+func (writer *recordWriter) WriteStr(data string) {
+	writer.records = append(writer.records, data)
+}
+
+func (writer *recordWriter) SetColor(fg, bg prompt.Color, bold bool) {
+	writer.records = append(writer.records, fmt.Sprintf("<%d,%d,%t>", fg, bg, bold))
+}
+
+func TestHighlightWriter(t *testing.T) {
+	console := &Console{
+		prefix:     "app> ",
+		livePrefix: "   > ",
+		language:   LuaLanguage,
+	}
+	recorder := &recordWriter{}
+	writer := newHighlightWriter(recorder, console)
+
+	def := fmt.Sprintf("<%d,%d,false>", prompt.DefaultColor, prompt.DefaultColor)
+	keyword := fmt.Sprintf("<%d,%d,true>", prompt.Purple, prompt.DefaultColor)
+	number := fmt.Sprintf("<%d,%d,false>", prompt.Cyan, prompt.DefaultColor)
+	unmatched := fmt.Sprintf("<%d,%d,true>", prompt.White, prompt.DarkRed)
+
+	// Not an input.
+	writer.WriteStr("return 1")
+	assert.Equal(t, []string{"return 1"}, recorder.records)
+
+	// The input split by the prompt.
+	recorder.records = nil
+	writer.WriteStr("app> ")
+	writer.SetColor(prompt.DefaultColor, prompt.DefaultColor, false)
+	writer.WriteStr("ret\nurn 1\n")
+	assert.Equal(t, []string{
+		"app> ", def, keyword, "ret\nurn", def, " ", number, "1\n", def,
+	}, recorder.records)
+
+	// The input with a context of the unfinished statement.
+	recorder.records = nil
+	console.input = "if x then"
+	writer.WriteStr("   > ")
+	writer.WriteStr("end end")
+	assert.Equal(t, []string{
+		"   > ", keyword, "end", def, " ", unmatched, "end", def,
+	}, recorder.records)
+}
@@ -1,26 +1,42 @@
 local yaml = require('yaml')
 yaml.cfg{ encode_use_tostring = true }
-local args = {...}
-local cmd = table.remove(args, 1)
-local is_sql_language = table.remove(args, 1)
-local need_metainfo = table.remove(args, 1)
+-- Arguments are unpacked by position: a nil placeholder value must not break
+-- the script arguments after it.
+local cmd, is_sql_language, need_metainfo, binds = ...
+local args = {n = math.max(select('#', ...) - 4, 0), select(5, ...)}
 
 local function is_command(line)
     return line:sub(1, 1) == '\\'
 end
 
-if is_command(cmd) or is_sql_language == true then
+if is_command(cmd) then
     return require('console').eval(cmd)
 end
 
+if is_sql_language == true then
+    if binds == nil then
+        return require('console').eval(cmd)
+    end
+    local res, err = box.execute(cmd, binds)
+    if err ~= nil then
+        return yaml.encode({ {error = err} })
+    end
+    return yaml.encode({res})
+end
+
 {{ if .evaler }}
 local function fun()
     {{ .evaler }}
 end
 {{ else }}
-local fun, errmsg = loadstring("return "..cmd)
+local prefix = ''
+if binds ~= nil then
+    prefix = 'local __tt_binds = ...; '
+    args = {n = 1, binds}
+end
+local fun, errmsg = loadstring(prefix.."return "..cmd)
 if not fun then
-    fun, errmsg = loadstring(cmd)
+    fun, errmsg = loadstring(prefix..cmd)
 end
 if not fun then
     return yaml.encode({ {error = errmsg} })
@@ -44,7 +60,7 @@ local function format_equal(f1, f2)
     return true
 end
 
-local ret = table_pack(pcall(fun, unpack(args)))
+local ret = table_pack(pcall(fun, unpack(args, 1, args.n)))
 if not ret[1] then
     local err = unpack(ret, 2, ret.n)
     if err == nil then
@@ -0,0 +1,101 @@
+package luabody
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// evalStubs replaces Tarantool modules used by the eval function: yaml.encode
+// returns the encoded table as is.
+const evalStubs = `
+package.preload.yaml = function()
+    return {cfg = function() end, encode = function(t) return t end}
+end
+box = {NULL = 'box.NULL', tuple = {}}
+`
+
+// runEvalFuncBody executes the eval function body with arguments and returns
+// values passed to yaml.encode.
+func runEvalFuncBody(t *testing.T, args ...lua.LValue) []lua.LValue {
+	t.Helper()
+	body, err := GetEvalFuncBody("")
+	require.NoError(t, err)
+
+	L := lua.NewState()
+	defer L.Close()
+	require.NoError(t, L.DoString(evalStubs))
+
+	fn, err := L.LoadString(body)
+	require.NoError(t, err)
+	L.Push(fn)
+	for _, arg := range args {
+		L.Push(arg)
+	}
+	require.NoError(t, L.PCall(len(args), 1, nil))
+
+	encoded, ok := L.Get(-1).(*lua.LTable)
+	require.True(t, ok, "unexpected result: %s", L.Get(-1))
+	var values []lua.LValue
+	for i := 1; i <= encoded.MaxN(); i++ {
+		values = append(values, encoded.RawGetInt(i))
+	}
+	return values
+}
+
+func TestGetEvalFuncBody_scriptArgs(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []lua.LValue
+		expected []lua.LValue
+	}{
+		{
+			name:     "no_args",
+			args:     []lua.LValue{lua.LString("return 42")},
+			expected: []lua.LValue{lua.LNumber(42)},
+		},
+		{
+			name: "no_binds",
+			args: []lua.LValue{
+				lua.LString("return ..."), lua.LFalse, lua.LFalse, lua.LNil,
+				lua.LString("a"), lua.LString("b"),
+			},
+			expected: []lua.LValue{lua.LString("a"), lua.LString("b")},
+		},
+		{
+			name: "nil_arg",
+			args: []lua.LValue{
+				lua.LString("return ..."), lua.LFalse, lua.LFalse, lua.LNil,
+				lua.LString("a"), lua.LNil, lua.LString("c"),
+			},
+			expected: []lua.LValue{lua.LString("a"), lua.LString("box.NULL"), lua.LString("c")},
+		},
+		{
+			name: "select_count",
+			args: []lua.LValue{
+				lua.LString("return select('#', ...)"), lua.LFalse, lua.LFalse, lua.LNil,
+				lua.LNil, lua.LNil,
+			},
+			expected: []lua.LValue{lua.LNumber(2)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, runEvalFuncBody(t, tc.args...))
+		})
+	}
+}
+
+func TestGetEvalFuncBody_binds(t *testing.T) {
+	L := lua.NewState()
+	defer L.Close()
+	binds := L.NewTable()
+	binds.RawSetString("name", lua.LString("value"))
+
+	values := runEvalFuncBody(t, lua.LString("return __tt_binds.name"),
+		lua.LFalse, lua.LFalse, binds, lua.LString("ignored"))
+	assert.Equal(t, []lua.LValue{lua.LString("value")}, values)
+}
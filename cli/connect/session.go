@@ -0,0 +1,196 @@
+package connect
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/apex/log"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/tt/cli/connect/internal/luabody"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/formatter"
+)
+
+// SessionEntry is a single executed input of a recorded console session.
+type SessionEntry struct {
+	// Time is a moment when the input was executed.
+	Time time.Time `json:"time"`
+	// Instance is a connection target: URI or instance name.
+	Instance string `json:"instance"`
+	// Language is a language of the input.
+	Language string `json:"language"`
+	// Input is a complete statement or a backslash command.
+	Input string `json:"input"`
+	// Output is a YAML result of the statement.
+	Output string `json:"output,omitempty"`
+	// Error is an execution error of the statement.
+	Error string `json:"error,omitempty"`
+}
+
+// sessionRecorder writes executed inputs of a console session into a file
+// in the JSON Lines format.
+type sessionRecorder struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// newSessionRecorder creates a new session recorder. The records are
+// appended to the file if it already exists.
+func newSessionRecorder(path string) (*sessionRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	return &sessionRecorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// record writes the entry into the session file.
+func (recorder *sessionRecorder) record(entry SessionEntry) error {
+	if err := recorder.encoder.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write session entry: %w", err)
+	}
+	return nil
+}
+
+// close closes the session file.
+func (recorder *sessionRecorder) close() error {
+	return recorder.file.Close()
+}
+
+// ReadSession reads a recorded console session from the file.
+func ReadSession(path string) ([]SessionEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []SessionEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry SessionEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse session file %q line %d: %w",
+				path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session file %q: %w", path, err)
+	}
+	return entries, nil
+}
+
+// getResultError returns an error message if the YAML result of a statement
+// is an error.
+func getResultError(data string) string {
+	var result []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &result); err != nil {
+		return ""
+	}
+	if len(result) != 1 || len(result[0]) != 1 {
+		return ""
+	}
+	if msg, ok := result[0]["error"]; ok {
+		return fmt.Sprint(msg)
+	}
+	return ""
+}
+
+// formatErrorResult returns the error as a YAML result of a statement in the
+// same format as the instance does.
+func formatErrorResult(err error) string {
+	out, _ := yaml.Marshal([]map[string]string{{"error": err.Error()}})
+	return "---\n" + string(out) + "...\n"
+}
+
+// ReplayOpts describes options of a console session replay.
+type ReplayOpts struct {
+	// File is a path to the recorded session file.
+	File string
+	// DryRun prints inputs without execution.
+	DryRun bool
+	// ContinueOnError continues the replay after a failed input.
+	ContinueOnError bool
+}
+
+// Replay executes inputs of a recorded console session on the instance
+// non-interactively.
+func Replay(connectCtx ConnectCtx, connOpts connector.ConnectOpts, opts ReplayOpts) error {
+	entries, err := ReadSession(opts.File)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		for _, entry := range entries {
+			fmt.Printf("%s> %s\n", genConsoleTitle(connOpts, connectCtx), entry.Input)
+		}
+		return nil
+	}
+
+	console, err := NewConsole(connOpts, connectCtx, "")
+	if err != nil {
+		return fmt.Errorf("failed to create new console: %s", err)
+	}
+	defer console.Close()
+
+	evalBody, err := luabody.GetEvalFuncBody(connectCtx.Evaler)
+	if err != nil {
+		return err
+	}
+	commandsExecutor := newCmdExecutor()
+
+	var failed int
+	for i, entry := range entries {
+		fmt.Printf("%s> %s\n", console.title, entry.Input)
+		if commandsExecutor.Execute(console, entry.Input) {
+			console.recordInput(entry.Input, "", nil)
+			if console.quit {
+				break
+			}
+			continue
+		}
+
+		data, err := console.eval(evalBody, entry.Input)
+		console.recordInput(entry.Input, data, err)
+		if err == nil {
+			output, fmtErr := formatter.MakeOutput(console.format, data, console.formatOpts)
+			if fmtErr != nil {
+				log.Errorf("Unable to format output: %s", fmtErr)
+				log.Infof("Source YAML:\n%s", data)
+			} else {
+				fmt.Print(output)
+			}
+			if msg := getResultError(data); msg != "" {
+				err = errors.New(msg)
+			}
+		}
+		if err != nil {
+			if !opts.ContinueOnError {
+				return fmt.Errorf("failed to replay input %d: %w", i+1, err)
+			}
+			log.Errorf("Failed to replay input %d: %s", i+1, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to replay %d of %d inputs", failed, len(entries))
+	}
+	return nil
+}
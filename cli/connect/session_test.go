@@ -0,0 +1,85 @@
+package connect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRecordRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	entries := []SessionEntry{
+		{
+			Time:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Instance: "app:storage001",
+			Language: "lua",
+			Input:    "box.info.ro",
+			Output:   "---\n- false\n...\n",
+		},
+		{
+			Time:     time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC),
+			Instance: "app:storage001",
+			Language: "lua",
+			Input:    "\\set language sql",
+		},
+		{
+			Time:     time.Date(2026, 1, 2, 3, 4, 7, 0, time.UTC),
+			Instance: "app:storage001",
+			Language: "sql",
+			Input:    "select 1",
+			Error:    "connection closed",
+		},
+	}
+
+	recorder, err := newSessionRecorder(path)
+	require.NoError(t, err)
+	for _, entry := range entries[:2] {
+		require.NoError(t, recorder.record(entry))
+	}
+	require.NoError(t, recorder.close())
+
+	// A new recorder appends entries to the existing file.
+	recorder, err = newSessionRecorder(path)
+	require.NoError(t, err)
+	require.NoError(t, recorder.record(entries[2]))
+	require.NoError(t, recorder.close())
+
+	actual, err := ReadSession(path)
+	require.NoError(t, err)
+	assert.Equal(t, entries, actual)
+}
+
+func TestReadSession_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"input\":\"1\"}\n\nnot a json\n"), 0o644))
+
+	_, err := ReadSession(path)
+	assert.ErrorContains(t, err, "line 3")
+
+	_, err = ReadSession(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.ErrorContains(t, err, "failed to open session file")
+}
+
+func TestGetResultError(t *testing.T) {
+	cases := []struct {
+		data     string
+		expected string
+	}{
+		{"---\n- error: 'boom'\n...\n", "boom"},
+		{"---\n- 1\n...\n", ""},
+		{"---\n- error: 'boom'\n  code: 1\n...\n", ""},
+		{"---\n- error: 'boom'\n- 2\n...\n", ""},
+		{"---\n...\n", ""},
+		{"", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.data, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getResultError(tc.data))
+		})
+	}
+}
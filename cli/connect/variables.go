@@ -0,0 +1,280 @@
+package connect
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// luaBindsVar is a name of the Lua variable with values of the statement
+// parameters. The eval function declares it if the parameters are passed.
+const luaBindsVar = "__tt_binds"
+
+var (
+	// varNameRe matches a valid variable name.
+	varNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// numberRe matches a decimal number.
+	numberRe = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+)
+
+// luaKeywords are Lua keywords that could precede an expression.
+var luaKeywords = map[string]struct{}{
+	"and": {}, "do": {}, "else": {}, "elseif": {}, "if": {}, "in": {},
+	"not": {}, "or": {}, "return": {}, "then": {}, "until": {}, "while": {},
+}
+
+var errNotSingleRow = errors.New("the result should be a single row with named columns")
+
+// isValidVarName returns true if the string is a valid variable name.
+func isValidVarName(name string) bool {
+	return varNameRe.MatchString(name)
+}
+
+// parseVarValue converts a string representation of a variable value into a
+// number, a boolean or a string. A quoted value is always a string.
+func parseVarValue(str string) interface{} {
+	if len(str) >= 2 && (str[0] == '\'' || str[0] == '"') && str[len(str)-1] == str[0] {
+		return str[1 : len(str)-1]
+	}
+	if numberRe.MatchString(str) {
+		if val, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return val
+		}
+		if val, err := strconv.ParseFloat(str, 64); err == nil {
+			return val
+		}
+	}
+	switch str {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return str
+}
+
+// isIdentChar returns true if the character could be a part of an identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// skipLuaLongBracket returns a position after the Lua long bracket string
+// or comment `[==[ ... ]==]` starting at the position or the position itself
+// if there is no long bracket.
+func skipLuaLongBracket(stmt string, pos int) int {
+	if pos >= len(stmt) || stmt[pos] != '[' {
+		return pos
+	}
+	level := 0
+	for pos+1+level < len(stmt) && stmt[pos+1+level] == '=' {
+		level++
+	}
+	if pos+1+level >= len(stmt) || stmt[pos+1+level] != '[' {
+		return pos
+	}
+	closing := "]" + strings.Repeat("=", level) + "]"
+	if end := strings.Index(stmt[pos+2+level:], closing); end >= 0 {
+		return pos + 2 + level + end + len(closing)
+	}
+	return len(stmt)
+}
+
+// skipQuoted returns a position after the quoted string starting at the
+// position. A backslash escapes the next character if escapes is true,
+// otherwise a doubled quote is an escaped quote.
+func skipQuoted(stmt string, pos int, escapes bool) int {
+	quote := stmt[pos]
+	for i := pos + 1; i < len(stmt); i++ {
+		switch {
+		case escapes && stmt[i] == '\\':
+			i++
+		case stmt[i] == quote:
+			if !escapes && i+1 < len(stmt) && stmt[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(stmt)
+}
+
+// skipLiteral returns a position after a string literal or a comment
+// starting at the position or the position itself if there is none.
+func skipLiteral(stmt string, pos int, lang Language) int {
+	isSql := lang == SQLLanguage
+	switch c := stmt[pos]; {
+	case c == '\'' || c == '"':
+		return skipQuoted(stmt, pos, !isSql)
+	case c == '-' && strings.HasPrefix(stmt[pos:], "--"):
+		if !isSql {
+			if end := skipLuaLongBracket(stmt, pos+2); end != pos+2 {
+				return end
+			}
+		}
+		if end := strings.IndexByte(stmt[pos:], '\n'); end >= 0 {
+			return pos + end + 1
+		}
+		return len(stmt)
+	case isSql && c == '/' && strings.HasPrefix(stmt[pos:], "/*"):
+		if end := strings.Index(stmt[pos+2:], "*/"); end >= 0 {
+			return pos + 2 + end + 2
+		}
+		return len(stmt)
+	case !isSql && c == '[':
+		return skipLuaLongBracket(stmt, pos)
+	}
+	return pos
+}
+
+// isPlaceholderPos returns true if the colon at the position starts a
+// placeholder. In Lua a colon after an expression is a method call.
+func isPlaceholderPos(stmt string, pos int, lang Language) bool {
+	if pos+1 >= len(stmt) || !isIdentChar(stmt[pos+1]) ||
+		stmt[pos+1] >= '0' && stmt[pos+1] <= '9' {
+		return false
+	}
+	prev := strings.TrimRight(stmt[:pos], " \t\r\n")
+	if prev == "" {
+		return true
+	}
+	last := prev[len(prev)-1]
+	if last == ':' {
+		return false
+	}
+	if lang == SQLLanguage {
+		return true
+	}
+	if isIdentChar(last) {
+		// A keyword could not be followed by a method call.
+		start := len(prev)
+		for start > 0 && isIdentChar(prev[start-1]) {
+			start--
+		}
+		_, isKeyword := luaKeywords[prev[start:]]
+		return isKeyword
+	}
+	return !strings.ContainsRune(")]}'\"", rune(last))
+}
+
+// bindVars replaces `:name` placeholders in the statement with request
+// parameters: `?` for SQL and an element of the parameters table for Lua.
+// It returns the statement and values of the parameters or nil if there are
+// no placeholders. Placeholders inside string literals and comments are
+// ignored.
+func bindVars(stmt string, lang Language,
+	vars map[string]interface{},
+) (string, []interface{}, error) {
+	var (
+		builder strings.Builder
+		binds   []interface{}
+		luaIdx  = map[string]int{}
+	)
+
+	last := 0
+	for pos := 0; pos < len(stmt); {
+		if end := skipLiteral(stmt, pos, lang); end != pos {
+			pos = end
+			continue
+		}
+		if stmt[pos] != ':' || !isPlaceholderPos(stmt, pos, lang) {
+			pos++
+			continue
+		}
+
+		end := pos + 1
+		for end < len(stmt) && isIdentChar(stmt[end]) {
+			end++
+		}
+		name := stmt[pos+1 : end]
+		value, ok := vars[name]
+		if !ok {
+			return "", nil, fmt.Errorf("variable %q is not defined", name)
+		}
+
+		builder.WriteString(stmt[last:pos])
+		if lang == SQLLanguage {
+			binds = append(binds, value)
+			builder.WriteString("?")
+		} else {
+			idx, ok := luaIdx[name]
+			if !ok {
+				binds = append(binds, value)
+				idx = len(binds)
+				luaIdx[name] = idx
+			}
+			builder.WriteString(fmt.Sprintf("%s[%d]", luaBindsVar, idx))
+		}
+		last = end
+		pos = end
+	}
+
+	if binds == nil {
+		return stmt, nil, nil
+	}
+	builder.WriteString(stmt[last:])
+	return builder.String(), binds, nil
+}
+
+// isScalar returns true if the YAML value is not a map or a sequence.
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+// getResultVars returns named columns of the YAML result of a statement. The
+// result must be a single SQL row or a single map with scalar values.
+func getResultVars(data string) (map[string]interface{}, error) {
+	var results []interface{}
+	if err := yaml.Unmarshal([]byte(data), &results); err != nil {
+		return nil, fmt.Errorf("failed to parse the result: %w", err)
+	}
+	if len(results) != 1 {
+		return nil, errNotSingleRow
+	}
+	result, ok := results[0].(map[interface{}]interface{})
+	if !ok {
+		return nil, errNotSingleRow
+	}
+	if msg := getResultError(data); msg != "" {
+		return nil, fmt.Errorf("the result is an error: %s", msg)
+	}
+
+	vars := map[string]interface{}{}
+	metadata, hasMetadata := result["metadata"].([]interface{})
+	rows, hasRows := result["rows"].([]interface{})
+	if hasMetadata && hasRows {
+		if len(rows) != 1 {
+			return nil, errNotSingleRow
+		}
+		row, ok := rows[0].([]interface{})
+		if !ok || len(row) != len(metadata) {
+			return nil, errNotSingleRow
+		}
+		for i, column := range metadata {
+			info, ok := column.(map[interface{}]interface{})
+			if !ok {
+				return nil, errNotSingleRow
+			}
+			vars[fmt.Sprint(info["name"])] = row[i]
+		}
+	} else {
+		for key, value := range result {
+			vars[fmt.Sprint(key)] = value
+		}
+	}
+
+	for name, value := range vars {
+		if !isScalar(value) {
+			return nil, fmt.Errorf("the value of the column %q is not a scalar", name)
+		}
+	}
+	return vars, nil
+}
@@ -0,0 +1,174 @@
+package connect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVarValue(t *testing.T) {
+	cases := []struct {
+		str      string
+		expected interface{}
+	}{
+		{"42", int64(42)},
+		{"-7", int64(-7)},
+		{"1.5", 1.5},
+		{"1e3", 1000.0},
+		{"true", true},
+		{"false", false},
+		{"True", "True"},
+		{"t", "t"},
+		{"inf", "inf"},
+		{"abc", "abc"},
+		{"'42'", "42"},
+		{`"true"`, "true"},
+		{"'it''s'", "it''s"},
+		{"'abc", "'abc"},
+		{"hello world", "hello world"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.str, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseVarValue(tc.str))
+		})
+	}
+}
+
+func TestBindVars(t *testing.T) {
+	vars := map[string]interface{}{
+		"id":   int64(1),
+		"name": "x'); drop table t; --",
+	}
+
+	cases := []struct {
+		name     string
+		stmt     string
+		lang     Language
+		expected string
+		binds    []interface{}
+	}{
+		{
+			name:     "sql",
+			stmt:     "select * from t where id = :id or name = :name or id = :id",
+			lang:     SQLLanguage,
+			expected: "select * from t where id = ? or name = ? or id = ?",
+			binds:    []interface{}{int64(1), vars["name"], int64(1)},
+		},
+		{
+			name: "sql literals and comments",
+			stmt: "select ':id', \"a:id\" /* :id */ -- :id\nfrom t where id=:id",
+			lang: SQLLanguage,
+			expected: "select ':id', \"a:id\" /* :id */ -- :id\n" +
+				"from t where id=?",
+			binds: []interface{}{int64(1)},
+		},
+		{
+			name: "lua",
+			stmt: "box.space.t:select({:id}, {limit = :id}):filter(:name)",
+			lang: LuaLanguage,
+			expected: "box.space.t:select({__tt_binds[1]}, {limit = __tt_binds[1]})" +
+				":filter(__tt_binds[2])",
+			binds: []interface{}{int64(1), vars["name"]},
+		},
+		{
+			name:     "lua default language",
+			stmt:     "if :id then return :id end",
+			lang:     DefaultLanguage,
+			expected: "if __tt_binds[1] then return __tt_binds[1] end",
+			binds:    []interface{}{int64(1)},
+		},
+		{
+			name: "lua literals and comments",
+			stmt: "return ':id', \"\\\":id\", [[:id]], [==[:id]==] --[[ :id ]] -- :id\n" +
+				"::label:: f() :id",
+			lang: LuaLanguage,
+			expected: "return ':id', \"\\\":id\", [[:id]], [==[:id]==] --[[ :id ]] -- :id\n" +
+				"::label:: f() :id",
+		},
+		{
+			name:     "no placeholders",
+			stmt:     "return 1",
+			lang:     LuaLanguage,
+			expected: "return 1",
+		},
+		{
+			name:     "not a name",
+			stmt:     "select ':1', : id from t",
+			lang:     SQLLanguage,
+			expected: "select ':1', : id from t",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, binds, err := bindVars(tc.stmt, tc.lang, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, stmt)
+			assert.Equal(t, tc.binds, binds)
+		})
+	}
+
+	_, _, err := bindVars("select :unknown", SQLLanguage, vars)
+	assert.EqualError(t, err, `variable "unknown" is not defined`)
+}
+
+func TestGetResultVars(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name: "sql row",
+			data: "---\n- metadata:\n  - name: ID\n    type: integer\n" +
+				"  - name: NAME\n    type: string\n  rows:\n  - [1, 'abc']\n...\n",
+			expected: map[string]interface{}{"ID": 1, "NAME": "abc"},
+		},
+		{
+			name:     "map",
+			data:     "---\n- {id: 1, ro: false}\n...\n",
+			expected: map[string]interface{}{"id": 1, "ro": false},
+		},
+		{
+			name: "sql rows",
+			data: "---\n- metadata:\n  - name: ID\n    type: integer\n" +
+				"  rows:\n  - [1]\n  - [2]\n...\n",
+			err: errNotSingleRow.Error(),
+		},
+		{
+			name: "scalar",
+			data: "---\n- 1\n...\n",
+			err:  errNotSingleRow.Error(),
+		},
+		{
+			name: "multiple results",
+			data: "---\n- {id: 1}\n- {id: 2}\n...\n",
+			err:  errNotSingleRow.Error(),
+		},
+		{
+			name: "not a scalar",
+			data: "---\n- {id: [1, 2]}\n...\n",
+			err:  `the value of the column "id" is not a scalar`,
+		},
+		{
+			name: "error",
+			data: "---\n- error: boom\n...\n",
+			err:  "the result is an error: boom",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars, err := getResultVars(tc.data)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vars)
+		})
+	}
+}
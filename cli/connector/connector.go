@@ -1,6 +1,7 @@
 package connector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -10,10 +11,12 @@ import (
 	"time"
 
 	"github.com/tarantool/go-tarantool"
+	"github.com/tarantool/tt/lib/dial"
 )
 
 const (
 	greetingOperationTimeout = 3 * time.Second
+	sshConnectTimeout        = 10 * time.Second
 	maxSocketPathLinux       = 108
 	maxSocketPathMac         = 106
 )
@@ -41,8 +44,51 @@ type Connector interface {
 	Close() error
 }
 
+// sshConnector is a connector through an SSH port forwarding.
+type sshConnector struct {
+	Connector
+	forward *dial.SshForward
+}
+
+// Close closes the connection and stops the SSH port forwarding.
+func (conn sshConnector) Close() error {
+	return errors.Join(conn.Connector.Close(), conn.forward.Close())
+}
+
+// connectSsh connects to the tarantool instance through an SSH server. The
+// connection goes to a local port forwarded to the instance address.
+func connectSsh(opts ConnectOpts) (Connector, error) {
+	if opts.Network != TCPNetwork {
+		return nil, fmt.Errorf("only TCP addresses are supported through an SSH server, got %q",
+			opts.Network)
+	}
+	if opts.Ssl != (SslOpts{}) {
+		return nil, errors.New("SSL connections through an SSH tunnel are not supported")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshConnectTimeout)
+	defer cancel()
+	forward, err := dial.NewSshForward(ctx, dial.SshOpts(opts.Ssh), opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Address = forward.Addr()
+	opts.Ssh = SshOpts{}
+	conn, err := Connect(opts)
+	if err != nil {
+		forward.Close()
+		return nil, err
+	}
+	return sshConnector{Connector: conn, forward: forward}, nil
+}
+
 // Connect connects to the tarantool instance according to options.
 func Connect(opts ConnectOpts) (Connector, error) {
+	if opts.Ssh.Host != "" {
+		return connectSsh(opts)
+	}
+
 	// It became common that address is longer than 108 symbols(sun_path limit).
 	// To reduce length of address we use relative path
 	// with chdir into a directory of socket.
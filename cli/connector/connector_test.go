@@ -0,0 +1,65 @@
+package connector_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+func TestConnect_sshErrors(t *testing.T) {
+	// A closed port to make sure that nothing listens on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	cases := []struct {
+		name string
+		opts connector.ConnectOpts
+		err  string
+	}{
+		{
+			name: "unix",
+			opts: connector.ConnectOpts{
+				Network: connector.UnixNetwork,
+				Address: "/tmp/tt.sock",
+				Ssh:     connector.SshOpts{Host: "bastion"},
+			},
+			err: `only TCP addresses are supported through an SSH server, got "unix"`,
+		},
+		{
+			name: "ssl",
+			opts: connector.ConnectOpts{
+				Network: connector.TCPNetwork,
+				Address: "localhost:3301",
+				Ssl:     connector.SslOpts{CaFile: "ca.crt"},
+				Ssh:     connector.SshOpts{Host: "bastion"},
+			},
+			err: "SSL connections through an SSH tunnel are not supported",
+		},
+		{
+			name: "unreachable",
+			opts: connector.ConnectOpts{
+				Network: connector.TCPNetwork,
+				Address: "localhost:3301",
+				Ssh: connector.SshOpts{
+					Host:           closedAddr,
+					User:           "tt",
+					KeyFile:        "testdata/localhost.key",
+					KnownHostsFile: "/dev/null",
+				},
+			},
+			err: "failed to dial SSH server",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := connector.Connect(tc.opts)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -18,6 +18,9 @@ type ConnectOpts struct {
 	Password string
 	// Ssl options for a connection.
 	Ssl SslOpts
+	// Ssh options of a server to connect through. The connection is
+	// established directly if Ssh.Host is empty.
+	Ssh SshOpts
 	// maximal count of commands to output in \history command.
 	MaxOutputHistoryLen int
 }
@@ -34,3 +37,17 @@ type SslOpts struct {
 	// connection can use.
 	Ciphers string
 }
+
+// SshOpts is a way to connect through an SSH server.
+type SshOpts struct {
+	// Host is an SSH server address: host[:port].
+	Host string
+	// User is an SSH user name. The current system user is used if empty.
+	User string
+	// KeyFile is a path to a private key file. Keys from the SSH agent are
+	// used if empty.
+	KeyFile string
+	// KnownHostsFile is a path to a known_hosts file. ~/.ssh/known_hosts is
+	// used if empty.
+	KnownHostsFile string
+}
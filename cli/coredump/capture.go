@@ -0,0 +1,264 @@
+package coredump
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// commLen is a maximum length of the process command name.
+	commLen = 15
+	// coredumpctlRetries is a number of attempts to get a core dump from
+	// systemd-coredump, because it is processed asynchronously.
+	coredumpctlRetries = 5
+	// coredumpctlRetryDelay is a delay between attempts to get a core dump
+	// from systemd-coredump.
+	coredumpctlRetryDelay = time.Second
+)
+
+var (
+	// corePatternFile is a file with the kernel core pattern.
+	corePatternFile = "/proc/sys/kernel/core_pattern"
+	// coreUsesPidFile is a file with the kernel flag to append the PID to
+	// the core file name.
+	coreUsesPidFile = "/proc/sys/kernel/core_uses_pid"
+	// coredumpctl saves the core dump of the process into the file.
+	coredumpctl = func(pid int, output string) error {
+		out, err := exec.Command("coredumpctl", "dump", strconv.Itoa(pid),
+			"--output", output).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("coredumpctl failed: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	// pack packs the core dump into an archive.
+	pack = Pack
+)
+
+// ErrNoCoreDump is returned if the crashed process has not dumped a core.
+var ErrNoCoreDump = errors.New("core dump is not produced, check the core file size limit")
+
+// CaptureOpts describes a crashed process to capture a core dump of.
+type CaptureOpts struct {
+	// PID is a PID of the crashed process.
+	PID int
+	// Signal is a signal that terminated the process.
+	Signal syscall.Signal
+	// Time is a moment of the crash.
+	Time time.Time
+	// Executable is a path to the tarantool executable of the process.
+	Executable string
+	// WorkDirs are working directories of the process to search core
+	// dumps with a relative core pattern in.
+	WorkDirs []string
+	// CoreDir is a directory to search core dumps in before the locations
+	// from the kernel core pattern.
+	CoreDir string
+	// Logs are log files to pack with the core dump.
+	Logs []string
+	// OutputDir is a directory to store the archive in.
+	OutputDir string
+}
+
+// expandCorePattern converts the kernel core pattern into a glob pattern for
+// the crashed process. See core(5) for the specifiers.
+func expandCorePattern(pattern string, opts CaptureOpts) string {
+	comm := filepath.Base(opts.Executable)
+	if len(comm) > commLen {
+		comm = comm[:commLen]
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i == len(pattern)-1 {
+			// Glob meta characters of the pattern are matched literally.
+			if strings.ContainsRune(`*?[\`, rune(pattern[i])) {
+				builder.WriteByte('\\')
+			}
+			builder.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case '%':
+			builder.WriteByte('%')
+		case 'p', 'P', 'i', 'I':
+			builder.WriteString(strconv.Itoa(opts.PID))
+		case 'e':
+			builder.WriteString(comm)
+		case 's':
+			builder.WriteString(strconv.Itoa(int(opts.Signal)))
+		case 'u':
+			builder.WriteString(strconv.Itoa(os.Getuid()))
+		case 'g':
+			builder.WriteString(strconv.Itoa(os.Getgid()))
+		default:
+			// Time, host name, executable path and other values may differ
+			// from the values known to tt, so any value is matched.
+			builder.WriteByte('*')
+		}
+	}
+	return builder.String()
+}
+
+// newestFile returns the newest regular file of the paths.
+func newestFile(paths []string) string {
+	newest := ""
+	var newestTime time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+	}
+	return newest
+}
+
+// findNewest returns the newest regular file matching the glob pattern.
+func findNewest(pattern string) string {
+	matches, _ := filepath.Glob(pattern)
+	return newestFile(matches)
+}
+
+// hasPidField checks whether the file name contains the PID as a separate
+// number, so `core.42` matches the PID 42 and `core.420` does not.
+func hasPidField(name string, pid int) bool {
+	fields := strings.FieldsFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+	return slices.Contains(fields, strconv.Itoa(pid))
+}
+
+// findNewestInDir returns the newest regular file of the directory with the
+// PID in the name.
+func findNewestInDir(dir string, pid int) string {
+	entries, _ := os.ReadDir(dir)
+	var paths []string
+	for _, entry := range entries {
+		if hasPidField(entry.Name(), pid) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return newestFile(paths)
+}
+
+// readProcValue reads the trimmed value of the proc file.
+func readProcValue(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// locateByPattern locates the core dump by the kernel core pattern.
+func locateByPattern(corePattern string, opts CaptureOpts) (string, error) {
+	if !strings.Contains(corePattern, "%p") && !strings.Contains(corePattern, "%P") {
+		if usesPid, _ := readProcValue(coreUsesPidFile); usesPid == "1" {
+			corePattern += ".%p"
+		}
+	}
+	pattern := expandCorePattern(corePattern, opts)
+	if filepath.IsAbs(pattern) {
+		if core := findNewest(pattern); core != "" {
+			return core, nil
+		}
+		return "", fmt.Errorf("core dump is not found by pattern %q", corePattern)
+	}
+	for _, dir := range opts.WorkDirs {
+		if core := findNewest(filepath.Join(dir, pattern)); core != "" {
+			return core, nil
+		}
+	}
+	return "", fmt.Errorf("core dump is not found by pattern %q in %s", corePattern,
+		strings.Join(opts.WorkDirs, ", "))
+}
+
+// locateBySystemd gets the core dump from systemd-coredump into the directory.
+func locateBySystemd(opts CaptureOpts, dir string) (string, error) {
+	core := filepath.Join(dir, fmt.Sprintf("core.%d", opts.PID))
+	var err error
+	for range coredumpctlRetries {
+		if err = coredumpctl(opts.PID, core); err == nil {
+			return core, nil
+		}
+		time.Sleep(coredumpctlRetryDelay)
+	}
+	return "", err
+}
+
+// Locate finds the core dump of the crashed process. The configured core
+// directory is searched first, then the kernel core pattern is used: a file
+// pattern or systemd-coredump. A core dump from systemd-coredump is saved
+// into the temporary directory.
+func Locate(opts CaptureOpts, tmpDir string) (string, error) {
+	if opts.CoreDir != "" {
+		if core := findNewestInDir(opts.CoreDir, opts.PID); core != "" {
+			return core, nil
+		}
+	}
+
+	corePattern, err := readProcValue(corePatternFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the kernel core pattern: %w", err)
+	}
+	if strings.HasPrefix(corePattern, "|") {
+		if strings.Contains(corePattern, "systemd-coredump") {
+			return locateBySystemd(opts, tmpDir)
+		}
+		return "", fmt.Errorf("core dump is passed to %q, set a core dump directory "+
+			"to search it in", strings.TrimPrefix(corePattern, "|"))
+	}
+	return locateByPattern(corePattern, opts)
+}
+
+// Capture locates the core dump of the crashed process and packs it with
+// the executable and the logs into an archive in the output directory. It
+// returns a path to the archive.
+func Capture(opts CaptureOpts) (string, error) {
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create a directory for core dumps: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(opts.OutputDir, ".capture-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	core, err := Locate(opts, tmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	var logs []string
+	for _, logFile := range opts.Logs {
+		if _, err := os.Stat(logFile); err == nil {
+			logs = append(logs, logFile)
+		}
+	}
+	err = pack(core, opts.Executable, tmpDir, 0, strconv.FormatInt(opts.Time.Unix(), 10),
+		logs...)
+	if err != nil {
+		return "", err
+	}
+
+	archives, _ := filepath.Glob(filepath.Join(tmpDir, "*.tar.gz"))
+	if len(archives) == 0 {
+		return "", fmt.Errorf("core dump archive is not created")
+	}
+	archive := filepath.Join(opts.OutputDir,
+		fmt.Sprintf("%s-%d.tar.gz", opts.Time.Format("20060102-150405"), opts.PID))
+	if err := os.Rename(archives[0], archive); err != nil {
+		return "", fmt.Errorf("failed to store the core dump archive: %w", err)
+	}
+	return archive, nil
+}
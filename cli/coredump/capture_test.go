@@ -0,0 +1,174 @@
+package coredump
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandCorePattern(t *testing.T) {
+	opts := CaptureOpts{
+		PID:        1234,
+		Signal:     syscall.SIGSEGV,
+		Executable: "/usr/bin/tarantool-enterprise",
+	}
+	uid := strconv.Itoa(os.Getuid())
+
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"core", "core"},
+		{"/var/crash/core.%p", "/var/crash/core.1234"},
+		{"/var/crash/%e-%s-%t", "/var/crash/tarantool-enter-11-*"},
+		{"core-%u-%h-%%", "core-" + uid + "-*-%"},
+		{"core[%P]*", `core\[1234]\*`},
+		{"core%", "core%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expandCorePattern(tt.pattern, opts))
+		})
+	}
+}
+
+// setCorePattern sets the kernel core pattern files for the test.
+func setCorePattern(t *testing.T, pattern, usesPid string) {
+	dir := t.TempDir()
+	origPattern, origUsesPid := corePatternFile, coreUsesPidFile
+	corePatternFile = filepath.Join(dir, "core_pattern")
+	coreUsesPidFile = filepath.Join(dir, "core_uses_pid")
+	t.Cleanup(func() { corePatternFile, coreUsesPidFile = origPattern, origUsesPid })
+
+	require.NoError(t, os.WriteFile(corePatternFile, []byte(pattern+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(coreUsesPidFile, []byte(usesPid+"\n"), 0o644))
+}
+
+func writeFile(t *testing.T, path string) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("core"), 0o644))
+	return path
+}
+
+func TestLocate(t *testing.T) {
+	workDir := t.TempDir()
+	crashDir := t.TempDir()
+	opts := CaptureOpts{PID: 42, Executable: "tarantool", WorkDirs: []string{workDir}}
+
+	t.Run("relative pattern", func(t *testing.T) {
+		setCorePattern(t, "core", "1")
+		core := writeFile(t, filepath.Join(workDir, "core.42"))
+		writeFile(t, filepath.Join(workDir, "core.43"))
+
+		located, err := Locate(opts, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, core, located)
+	})
+
+	t.Run("absolute pattern", func(t *testing.T) {
+		setCorePattern(t, filepath.Join(crashDir, "%e.%p.%t"), "0")
+		core := writeFile(t, filepath.Join(crashDir, "tarantool.42.1700000000"))
+
+		located, err := Locate(opts, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, core, located)
+	})
+
+	t.Run("core dir", func(t *testing.T) {
+		setCorePattern(t, "|/usr/lib/apport %p", "0")
+		_, err := Locate(opts, t.TempDir())
+		assert.ErrorContains(t, err, "set a core dump directory")
+
+		coreDir := t.TempDir()
+		core := writeFile(t, filepath.Join(coreDir, "tarantool-core.42.1700000000"))
+		// Other PIDs containing the PID digits are not matched.
+		for _, name := range []string{"core.420", "core.142", "tarantool-core.4242.1"} {
+			other := writeFile(t, filepath.Join(coreDir, name))
+			future := time.Now().Add(time.Hour)
+			require.NoError(t, os.Chtimes(other, future, future))
+		}
+		opts := opts
+		opts.CoreDir = coreDir
+		located, err := Locate(opts, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, core, located)
+	})
+
+	t.Run("systemd-coredump", func(t *testing.T) {
+		setCorePattern(t, "|/usr/lib/systemd/systemd-coredump %P %u %g %s %t %c %h", "0")
+		origCoredumpctl := coredumpctl
+		defer func() { coredumpctl = origCoredumpctl }()
+		coredumpctl = func(pid int, output string) error {
+			assert.Equal(t, 42, pid)
+			writeFile(t, output)
+			return nil
+		}
+
+		tmpDir := t.TempDir()
+		located, err := Locate(opts, tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(tmpDir, "core.42"), located)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		setCorePattern(t, "core.%p", "0")
+		_, err := Locate(CaptureOpts{PID: 7, WorkDirs: []string{workDir}}, t.TempDir())
+		assert.ErrorContains(t, err, `core dump is not found by pattern "core.%p"`)
+	})
+}
+
+func TestCapture(t *testing.T) {
+	workDir := t.TempDir()
+	setCorePattern(t, "core.%p", "0")
+	core := writeFile(t, filepath.Join(workDir, "core.42"))
+	logFile := writeFile(t, filepath.Join(workDir, "tt.log"))
+
+	origPack := pack
+	defer func() { pack = origPack }()
+	var packedLogs []string
+	pack = func(corePath, executable, outputDir string, pid uint, time string,
+		logs ...string,
+	) error {
+		assert.Equal(t, core, corePath)
+		assert.Equal(t, "tarantool", executable)
+		assert.Equal(t, "1700000000", time)
+		packedLogs = logs
+		writeFile(t, filepath.Join(outputDir, "tarantool-core-N-202311142213-host.tar.gz"))
+		return nil
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "coredumps")
+	opts := CaptureOpts{
+		PID:        42,
+		Time:       time.Unix(1700000000, 0),
+		Executable: "tarantool",
+		WorkDirs:   []string{workDir},
+		Logs:       []string{logFile, filepath.Join(workDir, "missing.log")},
+		OutputDir:  outputDir,
+	}
+	archive, err := Capture(opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir,
+		time.Unix(1700000000, 0).Format("20060102-150405")+"-42.tar.gz"), archive)
+	assert.FileExists(t, archive)
+	assert.Equal(t, []string{logFile}, packedLogs)
+
+	// Temporary files are removed.
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	pack = func(string, string, string, uint, string, ...string) error {
+		return errors.New("gdb is not installed")
+	}
+	_, err = Capture(opts)
+	assert.ErrorContains(t, err, "gdb is not installed")
+}
@@ -26,8 +26,9 @@ const (
 	inspectEmbedPath = "scripts/gdb.sh"
 )
 
-// Pack packs coredump into a tar.gz archive.
-func Pack(corePath, executable, outputDir string, pid uint, time string) error {
+// Pack packs coredump into a tar.gz archive. The log files are packed
+// into the archive too.
+func Pack(corePath, executable, outputDir string, pid uint, time string, logs ...string) error {
 	tmpDir, err := os.MkdirTemp(os.TempDir(), "tt-coredump-*")
 	if err != nil {
 		return fmt.Errorf("cannot create a temporary directory for archiving: %v", err)
@@ -44,6 +45,12 @@ func Pack(corePath, executable, outputDir string, pid uint, time string) error {
 	if pid != 0 {
 		scriptArgs = append(scriptArgs, "-p", strconv.FormatUint(uint64(pid), 10))
 	}
+	if time != "" {
+		scriptArgs = append(scriptArgs, "-t", time)
+	}
+	for _, logFile := range logs {
+		scriptArgs = append(scriptArgs, "-l", logFile)
+	}
 
 	// Prepare gdb wrapper for packing.
 	inspectPath := filepath.Join(tmpDir, filepath.Base(inspectEmbedPath))
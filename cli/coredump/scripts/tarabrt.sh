@@ -12,11 +12,12 @@ a single archive with unified format:
   - /tarantool      - the executable binary file produced the core dump
   - /coredump       - the core dump file produced by the executable
   - /etc/os-release - the plain text file with OS identification data
+  - /logs           - the log files of the crashed instance (if any)
   - all shared libraries loaded (even via dlopen(3)) at the crash moment
 
 SYNOPSIS
 
-  $0 [-h] [-c core] [-d dir] [-e executable] [-p procID] [-t datetime]
+  $0 [-h] [-c core] [-d dir] [-e executable] [-l log] [-p procID] [-t datetime]
 
 Supported options are:
   -c COREDUMP                   Use file COREDUMP as a core dump to examine.
@@ -34,6 +35,9 @@ Supported options are:
   -g GDBWRAPPER                 Include GDB-wrapper script GDBWRAPPER into the
                                 archive.
 
+  -l LOG                        Include log file LOG into the archive.
+                                This option may appear multiple times.
+
   -p PID                        PID of the dumped process, as seen in the PID
                                 namespace in which the given process resides
                                 (see %p in core(5) for more info). This flag
@@ -67,11 +71,12 @@ COREDIR=${PWD}
 COREFILE=
 EXTS=
 GDB=
+LOGS=
 PID=
 TIME=$(date +%s)
 
 # Parse CLI options.
-OPTIONS=$(getopt -o c:d:e:g:hp:t:x: -n "${TOOL}" -- "$@")
+OPTIONS=$(getopt -o c:d:e:g:hl:p:t:x: -n "${TOOL}" -- "$@")
 eval set -- "${OPTIONS}"
 while true; do
 	case "$1" in
@@ -80,6 +85,7 @@ while true; do
 		-d) COREDIR=$2;  shift 2;;
 		-e) BINARY=$2;   shift 2;;
 		-g) GDB=$2;      shift 2;;
+		-l) LOGS=$2:$LOGS; shift 2;;
 		-p) PID=$2;      shift 2;;
 		-t) TIME=$2;     shift 2;;
 		-x) EXTS=$2:$EXTS; shift 2;;
@@ -311,6 +317,16 @@ MISSINGEXT
 	EXTS=${EXTS#*:} # drop the first
 done
 
+# Include log files (if any)
+while [ -n "${LOGS}" ]; do
+	LOG=${LOGS%%:*} # pick the first
+	if [ -f "${LOG}" ]; then
+		echo "${LOG}" >>"${TARLIST}"
+		tar_opts=$tar_opts\ --transform="s|${LOG}|/logs/$(basename "${LOG}")|"
+	fi
+	LOGS=${LOGS#*:} # drop the first
+done
+
 # Pack everything listed in TARLIST file into a tarball. To unify
 # the archive format BINARY, COREFILE, VERSION and TARLIST are
 # renamed while packing.
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// auditEntry is an audit log entry of a daemon HTTP API request.
+type auditEntry struct {
+	// ClientIP is an IP address of the client.
+	ClientIP string `json:"client_ip"`
+	// Client is a name of the authenticated client.
+	Client string `json:"client,omitempty"`
+	// Method is an HTTP method of the request.
+	Method string `json:"method"`
+	// Path is a path of the request.
+	Path string `json:"path"`
+	// Command is a name of the requested command.
+	Command string `json:"command,omitempty"`
+	// Params are parameters of the requested command.
+	Params []string `json:"params,omitempty"`
+	// Status is an HTTP status of the response.
+	Status int `json:"status"`
+	// Error describes a failure of the request.
+	Error string `json:"error,omitempty"`
+}
+
+// audit writes the entry to the audit log as a JSON object.
+func (handler *DaemonHandler) audit(entry auditEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		handler.logger.Printf("Failed to encode an audit log entry: %s", err)
+		return
+	}
+	handler.auditLogger.Printf("%s", data)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+var (
+	// errUnauthorized is returned if a client is not authenticated.
+	errUnauthorized = errors.New("unauthorized")
+	// errForbidden is returned if a client is not allowed to call a command.
+	errForbidden = errors.New("forbidden")
+)
+
+// anonymousClient is a name of the client if authentication is disabled.
+const anonymousClient = "anonymous"
+
+// allowedFlags are flags that restricted clients may pass to commands. A flag
+// is mapped to true if it takes a value. Other flags, like --cfg or --local,
+// are rejected since they change the environment of the command.
+var allowedFlags = map[string]map[string]bool{
+	"start": {
+		"--wait":    false,
+		"--timeout": true,
+	},
+	"stop": {
+		"-y":    false,
+		"--yes": false,
+	},
+	"restart": {
+		"-y":            false,
+		"--yes":         false,
+		"--rolling":     false,
+		"--switch-back": false,
+		"--timeout":     true,
+	},
+	"status": {
+		"-f":        true,
+		"--format":  true,
+		"-d":        false,
+		"--details": false,
+		"-p":        false,
+		"--pretty":  false,
+	},
+}
+
+// Authenticator authenticates clients of the daemon HTTP API by a bearer token
+// or a verified client certificate and checks their permissions.
+type Authenticator struct {
+	clients []config.DaemonClientOpts
+}
+
+// NewAuthenticator creates Authenticator. Any client is allowed to call any
+// command if the clients list is empty.
+func NewAuthenticator(clients []config.DaemonClientOpts) *Authenticator {
+	return &Authenticator{clients: clients}
+}
+
+// clientName returns a name of the client for the audit log.
+func clientName(client *config.DaemonClientOpts, idx int) string {
+	if client.Name != "" {
+		return client.Name
+	}
+	if client.CommonName != "" {
+		return client.CommonName
+	}
+	return fmt.Sprintf("client #%d", idx+1)
+}
+
+// bearerToken returns the bearer token of the request.
+func bearerToken(req *http.Request) string {
+	header := req.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Authenticate finds the client of the request. It returns the client name and
+// options or nil options if authentication is disabled.
+func (auth *Authenticator) Authenticate(req *http.Request) (string,
+	*config.DaemonClientOpts, error,
+) {
+	if len(auth.clients) == 0 {
+		return anonymousClient, nil, nil
+	}
+
+	if token := bearerToken(req); token != "" {
+		for i := range auth.clients {
+			client := &auth.clients[i]
+			if client.Token != "" &&
+				subtle.ConstantTimeCompare([]byte(client.Token), []byte(token)) == 1 {
+				return clientName(client, i), client, nil
+			}
+		}
+		return "", nil, fmt.Errorf("%w: invalid bearer token", errUnauthorized)
+	}
+
+	// Peer certificates are verified by the TLS server, so the common name
+	// of the leaf certificate can be trusted.
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+		commonName := req.TLS.VerifiedChains[0][0].Subject.CommonName
+		for i := range auth.clients {
+			client := &auth.clients[i]
+			if client.CommonName != "" && client.CommonName == commonName {
+				return clientName(client, i), client, nil
+			}
+		}
+		return "", nil, fmt.Errorf("%w: unknown client certificate %q",
+			errUnauthorized, commonName)
+	}
+
+	return "", nil, fmt.Errorf("%w: a bearer token or a client certificate is required",
+		errUnauthorized)
+}
+
+// isInstanceAllowed checks whether the target in the `app` or `app:instance`
+// form is allowed by the patterns.
+func isInstanceAllowed(patterns []string, target string) bool {
+	app, _, _ := strings.Cut(target, string(running.InstanceDelimiter))
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == target {
+			return true
+		}
+		// A pattern without an instance name allows all application instances.
+		if !strings.ContainsRune(pattern, running.InstanceDelimiter) && pattern == app {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeCommand checks whether the client is allowed to call the command.
+func authorizeCommand(client *config.DaemonClientOpts, cmdName string) error {
+	if client != nil && len(client.Commands) > 0 && !slices.Contains(client.Commands, cmdName) {
+		return fmt.Errorf("%w: command %q is not allowed", errForbidden, cmdName)
+	}
+	return nil
+}
+
+// isInstanceVisible checks whether the client is allowed to see the instance.
+func isInstanceVisible(client *config.DaemonClientOpts, target string) bool {
+	return client == nil || len(client.Instances) == 0 ||
+		isInstanceAllowed(client.Instances, target)
+}
+
+// isRestricted checks whether the client has any restrictions.
+func isRestricted(client *config.DaemonClientOpts) bool {
+	return client != nil && (len(client.Commands) > 0 || len(client.Instances) > 0)
+}
+
+// splitParams splits the command parameters into applications or instances.
+// It returns an error if a flag is not allowed for the command.
+func splitParams(cmdName string, params []string) ([]string, error) {
+	flags := allowedFlags[cmdName]
+	var targets []string
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if !strings.HasPrefix(param, "-") {
+			targets = append(targets, param)
+			continue
+		}
+		name, _, hasValue := strings.Cut(param, "=")
+		takesValue, ok := flags[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: flag %q is not allowed for command %q",
+				errForbidden, name, cmdName)
+		}
+		// The value of the flag is the next parameter in the `--flag value`
+		// form.
+		if takesValue && !hasValue {
+			i++
+		}
+	}
+	return targets, nil
+}
+
+// authorize checks whether the client is allowed to call the command with
+// the parameters. Restricted clients may pass only allowed flags, other
+// parameters are applications or instances.
+func authorize(client *config.DaemonClientOpts, cmdName string, params []string) error {
+	if !isRestricted(client) {
+		return nil
+	}
+	if err := authorizeCommand(client, cmdName); err != nil {
+		return err
+	}
+	targets, err := splitParams(cmdName, params)
+	if err != nil {
+		return err
+	}
+	if len(client.Instances) == 0 {
+		return nil
+	}
+
+	for _, target := range targets {
+		if !isInstanceAllowed(client.Instances, target) {
+			return fmt.Errorf("%w: instance %q is not allowed", errForbidden, target)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: an application or an instance must be specified",
+			errForbidden)
+	}
+	return nil
+}
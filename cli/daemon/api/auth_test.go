@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+var testClients = []config.DaemonClientOpts{
+	{
+		Name:      "ci",
+		Token:     "secret",
+		Commands:  []string{"status", "restart"},
+		Instances: []string{"app1", "app2:inst1"},
+	},
+	{
+		Token: "admin",
+	},
+	{
+		CommonName: "ops.example.com",
+		Commands:   []string{"status"},
+	},
+}
+
+func newRequestWithCert(commonName string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tarantool", nil)
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	return req
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	withToken := func(header string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/tarantool", nil)
+		req.Header.Set("Authorization", header)
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		clients    []config.DaemonClientOpts
+		req        *http.Request
+		wantClient string
+		wantErr    string
+	}{
+		{
+			name:       "auth disabled",
+			req:        httptest.NewRequest(http.MethodPost, "/tarantool", nil),
+			wantClient: anonymousClient,
+		},
+		{
+			name:       "named token",
+			clients:    testClients,
+			req:        withToken("Bearer secret"),
+			wantClient: "ci",
+		},
+		{
+			name:       "unnamed token",
+			clients:    testClients,
+			req:        withToken("bearer admin"),
+			wantClient: "client #2",
+		},
+		{
+			name:    "invalid token",
+			clients: testClients,
+			req:     withToken("Bearer wrong"),
+			wantErr: "unauthorized: invalid bearer token",
+		},
+		{
+			name:    "basic auth",
+			clients: testClients,
+			req:     withToken("Basic secret"),
+			wantErr: "unauthorized: a bearer token or a client certificate is required",
+		},
+		{
+			name:       "client certificate",
+			clients:    testClients,
+			req:        newRequestWithCert("ops.example.com"),
+			wantClient: "ops.example.com",
+		},
+		{
+			name:    "unknown client certificate",
+			clients: testClients,
+			req:     newRequestWithCert("dev.example.com"),
+			wantErr: `unauthorized: unknown client certificate "dev.example.com"`,
+		},
+		{
+			name:    "no credentials",
+			clients: testClients,
+			req:     httptest.NewRequest(http.MethodPost, "/tarantool", nil),
+			wantErr: "unauthorized: a bearer token or a client certificate is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, _, err := NewAuthenticator(tt.clients).Authenticate(tt.req)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantClient, name)
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	client := &testClients[0]
+
+	tests := []struct {
+		name    string
+		client  *config.DaemonClientOpts
+		cmdName string
+		params  []string
+		wantErr string
+	}{
+		{"anonymous", nil, "stop", nil, ""},
+		{"unrestricted", &testClients[1], "stop", nil, ""},
+		{"allowed app", client, "restart", []string{"-y", "app1"}, ""},
+		{"allowed app instance", client, "restart", []string{"app1:inst3"}, ""},
+		{"allowed instance", client, "status", []string{"app2:inst1"}, ""},
+		{
+			"forbidden command", client, "stop", []string{"app1"},
+			`forbidden: command "stop" is not allowed`,
+		},
+		{
+			"forbidden instance", client, "status", []string{"app2:inst2"},
+			`forbidden: instance "app2:inst2" is not allowed`,
+		},
+		{
+			"forbidden app", client, "status", []string{"app2"},
+			`forbidden: instance "app2" is not allowed`,
+		},
+		{
+			"all instances", client, "status", []string{"--details"},
+			"forbidden: an application or an instance must be specified",
+		},
+		{"allowed flags", client, "status", []string{"-d", "--format=json", "app1"}, ""},
+		{"flag value", client, "status", []string{"--format", "json", "app1"}, ""},
+		{"restart flags", client, "restart", []string{"--rolling", "--timeout", "30", "app1"}, ""},
+		{
+			"local flag", client, "status", []string{"--local=/other/env", "app1"},
+			`forbidden: flag "--local" is not allowed for command "status"`,
+		},
+		{
+			"local flag value", client, "status", []string{"--local", "/other/env", "app1"},
+			`forbidden: flag "--local" is not allowed for command "status"`,
+		},
+		{
+			"cfg flag", client, "restart", []string{"-y", "--cfg=/tmp/tt.yaml", "app1"},
+			`forbidden: flag "--cfg" is not allowed for command "restart"`,
+		},
+		{
+			"short cfg flag", client, "restart", []string{"-c", "/tmp/tt.yaml", "app1"},
+			`forbidden: flag "-c" is not allowed for command "restart"`,
+		},
+		{
+			"flags separator", client, "status", []string{"--", "app1"},
+			`forbidden: flag "--" is not allowed for command "status"`,
+		},
+		{
+			"flag value target", client, "status", []string{"--format", "app1"},
+			"forbidden: an application or an instance must be specified",
+		},
+		{
+			"commands only", &testClients[2], "status", []string{"--cfg=/tmp/tt.yaml"},
+			`forbidden: flag "--cfg" is not allowed for command "status"`,
+		},
+		{"commands only all instances", &testClients[2], "status", []string{"-p"}, ""},
+		{"unrestricted flags", &testClients[1], "status", []string{"--cfg=/tmp/tt.yaml"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(tt.client, tt.cmdName, tt.params)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDaemonHandler_audit(t *testing.T) {
+	var auditLog bytes.Buffer
+	handler := NewDaemonHandler("echo").
+		Auth(NewAuthenticator(testClients)).
+		AuditLogger(ttlog.NewCustomLogger(&auditLog, "", 0))
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		wantStatus int
+		wantEntry  auditEntry
+	}{
+		{
+			name:       "unauthorized",
+			token:      "wrong",
+			body:       `{"command_name": "stop", "params": []}`,
+			wantStatus: http.StatusUnauthorized,
+			wantEntry: auditEntry{
+				Status: http.StatusUnauthorized,
+				Error:  "unauthorized: invalid bearer token",
+			},
+		},
+		{
+			name:       "forbidden",
+			token:      "secret",
+			body:       `{"command_name": "stop", "params": ["app1"]}`,
+			wantStatus: http.StatusForbidden,
+			wantEntry: auditEntry{
+				Client:  "ci",
+				Command: "stop",
+				Params:  []string{"app1"},
+				Status:  http.StatusForbidden,
+				Error:   `forbidden: command "stop" is not allowed`,
+			},
+		},
+		{
+			name:       "allowed",
+			token:      "secret",
+			body:       `{"command_name": "status", "params": ["app1"]}`,
+			wantStatus: http.StatusOK,
+			wantEntry: auditEntry{
+				Client:  "ci",
+				Command: "status",
+				Params:  []string{"app1"},
+				Status:  http.StatusOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog.Reset()
+			req := httptest.NewRequest(http.MethodPost, "/tarantool", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			var entry auditEntry
+			require.NoError(t, json.Unmarshal(auditLog.Bytes(), &entry))
+			tt.wantEntry.ClientIP = "192.0.2.1:1234"
+			tt.wantEntry.Method = http.MethodPost
+			tt.wantEntry.Path = "/tarantool"
+			assert.Equal(t, tt.wantEntry, entry)
+		})
+	}
+}
@@ -11,13 +11,17 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/ttlog"
 )
 
 // DaemonHandler is used to communicate with the daemon over HTTP.
 type DaemonHandler struct {
-	cmdPath string
-	logger  ttlog.Logger
+	cmdPath     string
+	logger      ttlog.Logger
+	auditLogger ttlog.Logger
+	auth        *Authenticator
+	instances   InstancesProvider
 }
 
 // resResult describes a failure during the command execution.
@@ -52,8 +56,10 @@ func (handler *DaemonHandler) callCommand(ttCmd *command) (string, error) {
 // NewDaemonHandler creates DaemonHandler.
 func NewDaemonHandler(cmdPath string) *DaemonHandler {
 	return &DaemonHandler{
-		cmdPath: cmdPath,
-		logger:  ttlog.NewCustomLogger(io.Discard, "", 0),
+		cmdPath:     cmdPath,
+		logger:      ttlog.NewCustomLogger(io.Discard, "", 0),
+		auditLogger: ttlog.NewCustomLogger(io.Discard, "", 0),
+		auth:        NewAuthenticator(nil),
 	}
 }
 
@@ -63,6 +69,18 @@ func (handler *DaemonHandler) Logger(logger ttlog.Logger) *DaemonHandler {
 	return handler
 }
 
+// AuditLogger sets audit logger for DaemonHandler.
+func (handler *DaemonHandler) AuditLogger(logger ttlog.Logger) *DaemonHandler {
+	handler.auditLogger = logger
+	return handler
+}
+
+// Auth sets authenticator of clients for DaemonHandler.
+func (handler *DaemonHandler) Auth(auth *Authenticator) *DaemonHandler {
+	handler.auth = auth
+	return handler
+}
+
 // getClientIP gets the IP address of the client for an incoming HTTP request.
 func (handler *DaemonHandler) getClientIP(req *http.Request) (string, error) {
 	// Get IP from the X-REAL-IP header.
@@ -111,33 +129,51 @@ func (handler *DaemonHandler) getClientIP(req *http.Request) (string, error) {
 	return "", fmt.Errorf("no valid IP found")
 }
 
+// handleCommand authorizes the client, parses and calls the command.
+func (handler *DaemonHandler) handleCommand(req *http.Request,
+	client *config.DaemonClientOpts, entry *auditEntry,
+) (int, any, string) {
+	var cmd command
+	rawBody, err := parseCommand(req.Body, &cmd)
+	if err != nil {
+		return http.StatusBadRequest, &errorResult{err.Error()}, rawBody
+	}
+	entry.Command = cmd.Name
+	entry.Params = cmd.Params
+
+	if err := authorize(client, cmd.Name, cmd.Params); err != nil {
+		return http.StatusForbidden, &errorResult{err.Error()}, rawBody
+	}
+
+	commandRes, err := handler.callCommand(&cmd)
+	if err != nil {
+		return http.StatusOK, &errorResult{err.Error()}, rawBody
+	}
+	return http.StatusOK, &resResult{commandRes}, rawBody
+}
+
 // ServeHTTP handles requests to the tt daemon.
 func (handler *DaemonHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
 	// Parse, check and call the command.
 	var res interface{}
 	var status int
-	var cmd command
+	var rawBody string
 
-	// Construct client IP msg.
-	var clientIpMsg string
-	if ip, err := handler.getClientIP(req); err != nil {
-		clientIpMsg = err.Error()
-	} else {
-		clientIpMsg = ip
-	}
+	clientIpMsg := handler.clientAddress(req)
 
-	rawBody, err := parseCommand(req.Body, &cmd)
+	entry := auditEntry{
+		ClientIP: clientIpMsg,
+		Method:   req.Method,
+		Path:     req.URL.Path,
+	}
+	clientName, client, err := handler.auth.Authenticate(req)
 	if err != nil {
-		status = http.StatusBadRequest
+		status = http.StatusUnauthorized
 		res = &errorResult{err.Error()}
+		wr.Header().Set("WWW-Authenticate", `Bearer realm="tt daemon"`)
 	} else {
-		status = http.StatusOK
-		commandRes, err := handler.callCommand(&cmd)
-		if err != nil {
-			res = &errorResult{err.Error()}
-		} else {
-			res = &resResult{commandRes}
-		}
+		entry.Client = clientName
+		status, res, rawBody = handler.handleCommand(req, client, &entry)
 	}
 
 	// Construct json response.
@@ -152,7 +188,27 @@ func (handler *DaemonHandler) ServeHTTP(wr http.ResponseWriter, req *http.Reques
 	handler.logger.Printf("Client IP: %s; Request body: %s; Response body: %s",
 		clientIpMsg, rawBody, jsonResMsg)
 
-	// Write the result.
+	entry.Status = status
+	if errRes, ok := res.(*errorResult); ok {
+		entry.Error = errRes.Err
+	}
+	handler.audit(entry)
+
+	handler.writeResponse(wr, status, res)
+}
+
+// clientAddress returns the client IP address or a description of the failure
+// to get it.
+func (handler *DaemonHandler) clientAddress(req *http.Request) string {
+	ip, err := handler.getClientIP(req)
+	if err != nil {
+		return err.Error()
+	}
+	return ip
+}
+
+// writeResponse writes the result as JSON.
+func (handler *DaemonHandler) writeResponse(wr http.ResponseWriter, status int, res any) {
 	wr.Header().Set("Content-Type", "application/json")
 	wr.WriteHeader(status)
 	if err := json.NewEncoder(wr).Encode(res); err != nil {
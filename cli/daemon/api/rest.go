@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/status"
+	"github.com/tarantool/tt/cli/tail"
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// defaultLogLines is a default number of log lines returned by the API.
+const defaultLogLines = 10
+
+// InstancesProvider returns instances of the application or the instance
+// specified in the `app` or `app:instance` form. All instances are returned
+// if the target is empty.
+type InstancesProvider func(target string) ([]running.InstanceCtx, error)
+
+// instanceResource describes an instance in the REST API.
+type instanceResource struct {
+	// Name is a full name of the instance in the `app:instance` form.
+	Name string `json:"name"`
+	// App is a name of the application.
+	App string `json:"app"`
+	// Instance is a name of the instance.
+	Instance string `json:"instance"`
+	// Status is a process status of the instance.
+	Status string `json:"status"`
+	// PID is a PID of the watchdog process if the instance is running.
+	PID *int `json:"pid,omitempty"`
+}
+
+// restartResult describes a result of the instance restart.
+type restartResult struct {
+	// Instance is a full name of the instance.
+	Instance string `json:"instance"`
+	// Output is an output of the restart command.
+	Output string `json:"output"`
+}
+
+// logsResult contains the last log lines of the instance.
+type logsResult struct {
+	// Instance is a full name of the instance.
+	Instance string `json:"instance"`
+	// Lines are the last log lines.
+	Lines []string `json:"lines"`
+}
+
+// httpError is an error with an HTTP status.
+type httpError struct {
+	status int
+	err    error
+}
+
+// Error implements the error interface.
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+// newHTTPError creates an error with the HTTP status.
+func newHTTPError(status int, format string, args ...any) error {
+	return &httpError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// resourceFunc handles an authorized request to a resource. It returns
+// the response object.
+type resourceFunc func(req *http.Request, client *config.DaemonClientOpts) (any, error)
+
+// Instances sets a provider of instances for the REST API.
+func (handler *DaemonHandler) Instances(provider InstancesProvider) *DaemonHandler {
+	handler.instances = provider
+	return handler
+}
+
+// RESTHandler returns a handler of the versioned REST API.
+func (handler *DaemonHandler) RESTHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/openapi.json", func(wr http.ResponseWriter, req *http.Request) {
+		wr.Header().Set("Content-Type", "application/json")
+		wr.Write(openAPIDocument)
+	})
+	mux.Handle("GET /v1/instances", handler.resource("status", handler.listInstances))
+	mux.Handle("GET /v1/instances/{name}/status",
+		handler.resource("status", handler.instanceStatus))
+	mux.Handle("POST /v1/instances/{name}/restart",
+		handler.resource("restart", handler.restartInstance))
+	mux.Handle("GET /v1/instances/{name}/logs", handler.resource("log", handler.instanceLogs))
+	return mux
+}
+
+// resource authenticates and authorizes the client for the command, calls
+// the resource function and writes an audit log entry.
+func (handler *DaemonHandler) resource(cmdName string, fn resourceFunc) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		entry := auditEntry{
+			ClientIP: handler.clientAddress(req),
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			Command:  cmdName,
+		}
+		name := req.PathValue("name")
+		if name != "" {
+			entry.Params = []string{name}
+		}
+
+		var res any
+		clientName, client, err := handler.auth.Authenticate(req)
+		if err != nil {
+			wr.Header().Set("WWW-Authenticate", `Bearer realm="tt daemon"`)
+			err = &httpError{status: http.StatusUnauthorized, err: err}
+		} else {
+			entry.Client = clientName
+			if name == "" {
+				err = authorizeCommand(client, cmdName)
+			} else {
+				err = authorize(client, cmdName, []string{name})
+			}
+			if err != nil {
+				err = &httpError{status: http.StatusForbidden, err: err}
+			} else {
+				res, err = fn(req, client)
+			}
+		}
+
+		entry.Status = http.StatusOK
+		if err != nil {
+			entry.Status = http.StatusInternalServerError
+			var httpErr *httpError
+			if errors.As(err, &httpErr) {
+				entry.Status = httpErr.status
+			}
+			entry.Error = err.Error()
+			res = &errorResult{err.Error()}
+		}
+		handler.audit(entry)
+		handler.writeResponse(wr, entry.Status, res)
+	})
+}
+
+// provideInstances returns instances of the target.
+func (handler *DaemonHandler) provideInstances(target string) ([]running.InstanceCtx, error) {
+	if handler.instances == nil {
+		return nil, newHTTPError(http.StatusServiceUnavailable, "instances are not available")
+	}
+	return handler.instances(target)
+}
+
+// newInstanceResource creates an instance resource from the instance context.
+func newInstanceResource(inst running.InstanceCtx) instanceResource {
+	procStatus := running.Status(&inst)
+	resource := instanceResource{
+		Name:     running.GetAppInstanceName(inst),
+		App:      inst.AppName,
+		Instance: inst.InstName,
+		Status:   procStatus.Status,
+	}
+	if procStatus.Code == process_utils.ProcessRunningCode {
+		resource.PID = &procStatus.PID
+	}
+	return resource
+}
+
+// findInstance returns the instance with the name in the `app:instance` form.
+func (handler *DaemonHandler) findInstance(name string) (running.InstanceCtx, error) {
+	app, instName, found := strings.Cut(name, string(running.InstanceDelimiter))
+	if !found || app == "" || instName == "" {
+		return running.InstanceCtx{}, newHTTPError(http.StatusBadRequest,
+			"invalid instance name %q: expected app:instance", name)
+	}
+	instances, err := handler.provideInstances(name)
+	if err != nil {
+		var httpErr *httpError
+		if errors.As(err, &httpErr) {
+			return running.InstanceCtx{}, err
+		}
+		return running.InstanceCtx{}, newHTTPError(http.StatusNotFound,
+			"instance %q is not found: %s", name, err)
+	}
+	for _, inst := range instances {
+		if inst.AppName == app && inst.InstName == instName {
+			return inst, nil
+		}
+	}
+	return running.InstanceCtx{}, newHTTPError(http.StatusNotFound,
+		"instance %q is not found", name)
+}
+
+// listInstances returns instances visible to the client.
+func (handler *DaemonHandler) listInstances(req *http.Request,
+	client *config.DaemonClientOpts,
+) (any, error) {
+	instances, err := handler.provideInstances("")
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]instanceResource, 0, len(instances))
+	for _, inst := range instances {
+		if isInstanceVisible(client, running.GetAppInstanceName(inst)) {
+			resources = append(resources, newInstanceResource(inst))
+		}
+	}
+	return resources, nil
+}
+
+// instanceStatus returns a status of the instance.
+func (handler *DaemonHandler) instanceStatus(req *http.Request,
+	client *config.DaemonClientOpts,
+) (any, error) {
+	inst, err := handler.findInstance(req.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	statuses := status.Collect(running.RunningCtx{Instances: []running.InstanceCtx{inst}})
+	return statuses[running.GetAppInstanceName(inst)], nil
+}
+
+// restartInstance restarts the instance.
+func (handler *DaemonHandler) restartInstance(req *http.Request,
+	client *config.DaemonClientOpts,
+) (any, error) {
+	inst, err := handler.findInstance(req.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	name := running.GetAppInstanceName(inst)
+	output, err := handler.callCommand(&command{Name: "restart", Params: []string{"-y", name}})
+	if err != nil {
+		return nil, err
+	}
+	return &restartResult{Instance: name, Output: output}, nil
+}
+
+// instanceLogs returns the last log lines of the instance.
+func (handler *DaemonHandler) instanceLogs(req *http.Request,
+	client *config.DaemonClientOpts,
+) (any, error) {
+	lines := defaultLogLines
+	if value := req.URL.Query().Get("lines"); value != "" {
+		var err error
+		if lines, err = strconv.Atoi(value); err != nil || lines < 0 {
+			return nil, newHTTPError(http.StatusBadRequest,
+				"invalid lines value %q: expected a non-negative number", value)
+		}
+	}
+	inst, err := handler.findInstance(req.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &logsResult{Instance: running.GetAppInstanceName(inst), Lines: []string{}}
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	logLines, err := tail.TailN(ctx, func(str string) string { return str }, inst.Log, lines)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return res, nil
+		}
+		return nil, err
+	}
+	for line := range logLines {
+		res.Lines = append(res.Lines, line)
+	}
+	return res, nil
+}
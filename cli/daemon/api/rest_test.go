@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func newTestRESTHandler(t *testing.T, clients []config.DaemonClientOpts) http.Handler {
+	dir := t.TempDir()
+	var instances []running.InstanceCtx
+	for _, name := range []string{"inst1", "inst2"} {
+		instances = append(instances, running.InstanceCtx{
+			AppName:       "app",
+			InstName:      name,
+			PIDFile:       filepath.Join(dir, name+".pid"),
+			ConsoleSocket: filepath.Join(dir, name+".control"),
+			Log:           filepath.Join(dir, name+".log"),
+		})
+	}
+	require.NoError(t, os.WriteFile(instances[0].Log, []byte("one\ntwo\nthree\n"), 0o644))
+
+	provider := func(target string) ([]running.InstanceCtx, error) {
+		if target != "" && target != "app" && target != "app:inst1" && target != "app:inst2" {
+			return nil, errors.New("can't find an application init file")
+		}
+		return instances, nil
+	}
+	return NewDaemonHandler("echo").Auth(NewAuthenticator(clients)).Instances(provider).
+		RESTHandler()
+}
+
+func doRequest(handler http.Handler, method, target, token string, res any) int {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if res != nil {
+		json.Unmarshal(rec.Body.Bytes(), res)
+	}
+	return rec.Code
+}
+
+func TestRESTHandler_instances(t *testing.T) {
+	handler := newTestRESTHandler(t, nil)
+
+	var instances []instanceResource
+	require.Equal(t, http.StatusOK, doRequest(handler, http.MethodGet, "/v1/instances", "",
+		&instances))
+	assert.Equal(t, []instanceResource{
+		{Name: "app:inst1", App: "app", Instance: "inst1", Status: "NOT RUNNING"},
+		{Name: "app:inst2", App: "app", Instance: "inst2", Status: "NOT RUNNING"},
+	}, instances)
+
+	var status map[string]any
+	require.Equal(t, http.StatusOK, doRequest(handler, http.MethodGet,
+		"/v1/instances/app:inst1/status", "", &status))
+	assert.Equal(t, "NOT RUNNING", status["status"])
+	assert.Nil(t, status["pid"])
+
+	var errRes errorResult
+	require.Equal(t, http.StatusNotFound, doRequest(handler, http.MethodGet,
+		"/v1/instances/app:inst3/status", "", &errRes))
+	assert.Equal(t, `instance "app:inst3" is not found: `+
+		"can't find an application init file", errRes.Err)
+
+	require.Equal(t, http.StatusBadRequest, doRequest(handler, http.MethodGet,
+		"/v1/instances/app/status", "", &errRes))
+	assert.Equal(t, `invalid instance name "app": expected app:instance`, errRes.Err)
+
+	require.Equal(t, http.StatusMethodNotAllowed, doRequest(handler, http.MethodGet,
+		"/v1/instances/app:inst1/restart", "", nil))
+}
+
+func TestRESTHandler_logs(t *testing.T) {
+	handler := newTestRESTHandler(t, nil)
+
+	tests := []struct {
+		target     string
+		wantStatus int
+		wantLines  []string
+	}{
+		{"/v1/instances/app:inst1/logs", http.StatusOK, []string{"one", "two", "three"}},
+		{"/v1/instances/app:inst1/logs?lines=2", http.StatusOK, []string{"two", "three"}},
+		{"/v1/instances/app:inst1/logs?lines=0", http.StatusOK, []string{}},
+		{"/v1/instances/app:inst2/logs", http.StatusOK, []string{}},
+		{"/v1/instances/app:inst1/logs?lines=-1", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var logs logsResult
+			require.Equal(t, tt.wantStatus, doRequest(handler, http.MethodGet, tt.target, "",
+				&logs))
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantLines, logs.Lines)
+			}
+		})
+	}
+}
+
+func TestRESTHandler_auth(t *testing.T) {
+	handler := newTestRESTHandler(t, []config.DaemonClientOpts{
+		{Token: "viewer", Commands: []string{"status"}, Instances: []string{"app:inst2"}},
+	})
+
+	require.Equal(t, http.StatusUnauthorized, doRequest(handler, http.MethodGet,
+		"/v1/instances", "", nil))
+
+	var instances []instanceResource
+	require.Equal(t, http.StatusOK, doRequest(handler, http.MethodGet, "/v1/instances",
+		"viewer", &instances))
+	require.Len(t, instances, 1)
+	assert.Equal(t, "app:inst2", instances[0].Name)
+
+	require.Equal(t, http.StatusForbidden, doRequest(handler, http.MethodGet,
+		"/v1/instances/app:inst1/status", "viewer", nil))
+	require.Equal(t, http.StatusForbidden, doRequest(handler, http.MethodPost,
+		"/v1/instances/app:inst2/restart", "viewer", nil))
+	require.Equal(t, http.StatusForbidden, doRequest(handler, http.MethodGet,
+		"/v1/instances/app:inst2/logs", "viewer", nil))
+
+	var document map[string]any
+	require.Equal(t, http.StatusOK, doRequest(handler, http.MethodGet, "/v1/openapi.json", "",
+		&document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+}
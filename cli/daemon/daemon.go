@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/daemon/api"
 	"github.com/tarantool/tt/cli/process_utils"
 	"github.com/tarantool/tt/cli/ttlog"
 )
@@ -21,15 +22,29 @@ type DaemonCtx struct {
 	// ListenInterface is a network interface the IP address
 	// should be found on to bind http server socket.
 	ListenInterface string
+	// AuditLogPath is a path to a file contains audit log of http API requests.
+	AuditLogPath string
+	// TLS contains TLS options of daemon http server. TLS is disabled if nil.
+	TLS *config.DaemonTLSOpts
+	// Auth is a list of clients allowed to use daemon http API.
+	Auth []config.DaemonClientOpts
+	// Instances is a provider of instances for the REST API.
+	Instances api.InstancesProvider
 }
 
 // NewDaemonCtx creates the DaemonCtx context.
 func NewDaemonCtx(opts *config.DaemonOpts) *DaemonCtx {
-	return &DaemonCtx{
+	daemonCtx := &DaemonCtx{
 		PIDFile: filepath.Join(opts.RunDir, opts.PIDFile),
 		Port:    opts.Port,
 		LogPath: filepath.Join(opts.LogDir, opts.LogFile),
+		TLS:     opts.TLS,
+		Auth:    opts.Auth,
 	}
+	if opts.AuditLog != "" {
+		daemonCtx.AuditLogPath = filepath.Join(opts.LogDir, opts.AuditLog)
+	}
+	return daemonCtx
 }
 
 // RunHTTPServerOnBackground starts http daemon process.
@@ -39,8 +54,11 @@ func RunHTTPServerOnBackground(daemonCtx *DaemonCtx) error {
 	}
 
 	args := []string{"daemon", "start"}
-	proc := NewProcess(NewHTTPServer(daemonCtx.ListenInterface, daemonCtx.Port),
-		daemonCtx.PIDFile, logOpts).CmdPath(os.Args[0]).CmdArgs(args)
+	httpServer := NewHTTPServer(daemonCtx.ListenInterface, daemonCtx.Port).
+		TLS(daemonCtx.TLS).Auth(daemonCtx.Auth).AuditLog(daemonCtx.AuditLogPath).
+		Instances(daemonCtx.Instances)
+	proc := NewProcess(httpServer, daemonCtx.PIDFile, logOpts).
+		CmdPath(os.Args[0]).CmdArgs(args)
 
 	if err := proc.Start(); err != nil {
 		return err
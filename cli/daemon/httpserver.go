@@ -2,12 +2,16 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
+	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/daemon/api"
 	"github.com/tarantool/tt/cli/ttlog"
 )
@@ -30,6 +34,16 @@ type HTTPServer struct {
 	timeout time.Duration
 	// logger is  a log file the HTTP server will write to.
 	logger ttlog.Logger
+	// tlsOpts are TLS options of the HTTP server. TLS is disabled if nil.
+	tlsOpts *config.DaemonTLSOpts
+	// clients are clients allowed to use the HTTP API.
+	clients []config.DaemonClientOpts
+	// auditLogPath is a path to the audit log file of the HTTP API requests.
+	auditLogPath string
+	// auditLogger is the audit log the HTTP server will write to.
+	auditLogger ttlog.Logger
+	// instances is a provider of instances for the REST API.
+	instances api.InstancesProvider
 }
 
 // listenIP discovers IP address on the specified interface.
@@ -97,6 +111,57 @@ func (httpServer *HTTPServer) Timeout(timeout time.Duration) *HTTPServer {
 	return httpServer
 }
 
+// TLS sets TLS options of the HTTP server.
+func (httpServer *HTTPServer) TLS(opts *config.DaemonTLSOpts) *HTTPServer {
+	httpServer.tlsOpts = opts
+	return httpServer
+}
+
+// Auth sets clients allowed to use the HTTP API.
+func (httpServer *HTTPServer) Auth(clients []config.DaemonClientOpts) *HTTPServer {
+	httpServer.clients = clients
+	return httpServer
+}
+
+// AuditLog sets a path to the audit log file of the HTTP API requests.
+func (httpServer *HTTPServer) AuditLog(path string) *HTTPServer {
+	httpServer.auditLogPath = path
+	return httpServer
+}
+
+// Instances sets a provider of instances for the REST API.
+func (httpServer *HTTPServer) Instances(provider api.InstancesProvider) *HTTPServer {
+	httpServer.instances = provider
+	return httpServer
+}
+
+// tlsConfig creates TLS configuration of the HTTP server. Client certificates
+// are required and verified if the CA file is set.
+func (httpServer *HTTPServer) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(httpServer.tlsOpts.CertFile, httpServer.tlsOpts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if httpServer.tlsOpts.CaFile != "" {
+		caCert, err := os.ReadFile(httpServer.tlsOpts.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in the CA file %q",
+				httpServer.tlsOpts.CaFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
 // SetLogger sets a log file the HTTP server will write to.
 func (httpServer *HTTPServer) SetLogger(logger ttlog.Logger) {
 	httpServer.logger = logger
@@ -114,9 +179,26 @@ func (httpServer *HTTPServer) Start(ttPath string) {
 		Addr: httpServerAddr,
 	}
 
+	if httpServer.tlsOpts != nil {
+		if httpServer.srv.TLSConfig, err = httpServer.tlsConfig(); err != nil {
+			httpServer.logger.Fatal(err)
+		}
+	}
+
 	// Prepare HTTP server.
-	daemonHandler := api.NewDaemonHandler(ttPath).Logger(httpServer.logger)
+	daemonHandler := api.NewDaemonHandler(ttPath).Logger(httpServer.logger).
+		Auth(api.NewAuthenticator(httpServer.clients)).Instances(httpServer.instances)
+	if httpServer.auditLogPath != "" {
+		httpServer.auditLogger, err = ttlog.NewFileLogger(ttlog.LoggerOpts{
+			Filename: httpServer.auditLogPath,
+		})
+		if err != nil {
+			httpServer.logger.Fatal(err)
+		}
+		daemonHandler.AuditLogger(httpServer.auditLogger)
+	}
 	http.Handle("/tarantool", daemonHandler)
+	http.Handle("/v1/", daemonHandler.RESTHandler())
 
 	// Start HTTP server.
 	socket, err := net.Listen("tcp4", httpServer.srv.Addr)
@@ -124,7 +206,12 @@ func (httpServer *HTTPServer) Start(ttPath string) {
 		httpServer.logger.Fatal(err)
 	}
 
-	if err := httpServer.srv.Serve(socket); err != http.ErrServerClosed {
+	if httpServer.srv.TLSConfig != nil {
+		err = httpServer.srv.ServeTLS(socket, "", "")
+	} else {
+		err = httpServer.srv.Serve(socket)
+	}
+	if err != http.ErrServerClosed {
 		httpServer.logger.Fatalf("Can't start HTTP server")
 	}
 }
@@ -143,5 +230,9 @@ func (httpServer *HTTPServer) Stop() error {
 	}
 	cancel()
 
+	if httpServer.auditLogger != nil {
+		httpServer.auditLogger.Close()
+	}
+
 	return err
 }
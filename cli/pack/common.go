@@ -576,6 +576,9 @@ func createNewOpts(opts *config.CliOpts, packCtx PackCtx) *config.CliOpts {
 		cliOptsNew.Env.InstancesEnabled = configure.InstancesEnabledDirName
 	}
 	cliOptsNew.Env.Restartable = opts.Env.Restartable
+	cliOptsNew.Env.RestartPolicy = opts.Env.RestartPolicy
+	cliOptsNew.Env.ResourceLimits = opts.Env.ResourceLimits
+	cliOptsNew.Env.Coredump = opts.Env.Coredump
 	cliOptsNew.Env.TarantoolctlLayout = opts.Env.TarantoolctlLayout
 
 	// In case the user separates one of the directories for storing memtx, vinyl or wal artifacts
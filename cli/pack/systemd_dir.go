@@ -1,7 +1,6 @@
 package pack
 
 import (
-	_ "embed"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,36 +8,10 @@ import (
 	"github.com/apex/log"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/systemd"
 	"github.com/tarantool/tt/cli/util"
-	"gopkg.in/yaml.v2"
 )
 
-const (
-	defaultInstanceFdLimit = 65535
-	unitParamsFileName     = "systemd-unit-params.yml"
-)
-
-//go:embed templates/app-inst-unit-template.txt
-var appInstUnitContentTemplate string
-
-// systemdUnitFileName generates systemd unit file name for application.
-func systemdUnitFileName(inst running.InstanceCtx) string {
-	if inst.SingleApp {
-		return fmt.Sprintf("%s.service", inst.AppName)
-	} else {
-		return fmt.Sprintf("%s@.service", inst.AppName)
-	}
-}
-
-// systemdDescriptionAppName generates an app name to use in description line in systemd unit.
-func systemdDescriptionAppName(inst running.InstanceCtx) string {
-	if inst.SingleApp {
-		return inst.AppName
-	} else {
-		return fmt.Sprintf("%s@%%i", inst.AppName)
-	}
-}
-
 // initSystemdDir generates systemd unit files for every application in the current bundle.
 // pathToEnv is a path to environment in the target system.
 // baseDirPath is a root of the directory which will get packed.
@@ -57,7 +30,7 @@ func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv string) error {
 		}
 		inst := instances[0]
 		// Create service systemd.unit for each application.
-		appInstUnitPath := systemdUnitFileName(inst)
+		appInstUnitPath := systemd.UnitFileName(inst)
 		appInstUnitPath = filepath.Join(systemdBaseDir, appInstUnitPath)
 
 		log.Debugf("Generating systemd unit for %q application.", appName)
@@ -66,41 +39,20 @@ func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv string) error {
 			return err
 		}
 
-		if err = util.InstantiateFileFromTemplate(appInstUnitPath, appInstUnitContentTemplate,
-			unitParams); err != nil {
-			return fmt.Errorf("failed to create systemd unit file: %s", err)
+		if err = systemd.InstantiateUnit(appInstUnitPath, unitParams); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// systemdExecArgsForApp generates CLI arguments for start/stop commands in unit file.
-func systemdExecArgsForApp(inst running.InstanceCtx) string {
-	if inst.SingleApp {
-		// There are no instances for single instance application. So only app name is returned.
-		return inst.AppName
-	} else {
-		// Return <app><delimiter>%i format ars. %i will be replaced with instance name.
-		return fmt.Sprintf("%s%c%%i", inst.AppName, running.InstanceDelimiter)
-	}
-}
-
-type systemdUnitParams struct {
-	AppName     string            `yaml:"AppName"`
-	ExecArgs    string            `yaml:"ExecArgs"`
-	TT          string            `yaml:"TT"`
-	ConfigPath  string            `yaml:"ConfigPath"`
-	FdLimit     uint64            `yaml:"FdLimit"`
-	InstanceEnv map[string]string `yaml:"instance-env"`
-}
-
-func loadUserUnitParams(unitParams *systemdUnitParams, packCtx *PackCtx,
+func loadUserUnitParams(unitParams *systemd.UnitParams, packCtx *PackCtx,
 	inst running.InstanceCtx,
 ) error {
 	// First check systemd params file in application directory and if it does not exist, check
 	// params file path in the pack context.
-	unitParamsFile := util.JoinPaths(inst.AppDir, unitParamsFileName)
+	unitParamsFile := util.JoinPaths(inst.AppDir, systemd.UnitParamsFileName)
 	if !util.IsRegularFile(unitParamsFile) {
 		unitParamsFile = packCtx.RpmDeb.SystemdUnitParamsFile
 		if len(unitParamsFile) == 0 {
@@ -109,29 +61,20 @@ func loadUserUnitParams(unitParams *systemdUnitParams, packCtx *PackCtx,
 	}
 	log.Debugf("Using systemd unit params file %q for %s application",
 		unitParamsFile, inst.AppName)
-	unitTemplFile, err := os.Open(unitParamsFile)
-	if err != nil {
-		return fmt.Errorf("cannot open systemd unit parameters file %q: %s",
-			unitParamsFile, err)
-	}
-
-	if err = yaml.NewDecoder(unitTemplFile).Decode(unitParams); err != nil {
-		return fmt.Errorf("failed to decode systemd unit params: %s", err)
-	}
-	return nil
+	return systemd.LoadUnitParams(unitParamsFile, unitParams)
 }
 
 // getUnitParams checks if there is a passed unit params file in context and
 // returns its content. Otherwise, it returns the default params.
 func getUnitParams(packCtx *PackCtx, pathToEnv string,
 	inst running.InstanceCtx,
-) (systemdUnitParams, error) {
+) (systemd.UnitParams, error) {
 	ttBinary := getTTBinary(packCtx, pathToEnv)
 
-	unitParams := systemdUnitParams{
+	unitParams := systemd.UnitParams{
 		TT:         ttBinary,
 		ConfigPath: pathToEnv,
-		FdLimit:    defaultInstanceFdLimit,
+		FdLimit:    systemd.DefaultFdLimit,
 	}
 
 	if err := loadUserUnitParams(&unitParams, packCtx, inst); err != nil {
@@ -140,8 +83,8 @@ func getUnitParams(packCtx *PackCtx, pathToEnv string,
 
 	// Application name is specific for each generated per-application systemd unit, so it
 	// should not be set by unit params file, because it will become the same for all units.
-	unitParams.AppName = systemdDescriptionAppName(inst)
-	unitParams.ExecArgs = systemdExecArgsForApp(inst)
+	unitParams.AppName = systemd.DescriptionAppName(inst)
+	unitParams.ExecArgs = systemd.ExecArgsForApp(inst)
 	return unitParams, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/systemd"
 )
 
 func compareFiles(t *testing.T, resultFile, expectedFile string) {
@@ -136,13 +137,14 @@ func Test_initSystemdDir(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 			check: func(baseTestDir string) error {
-				unitTemplate, err := template.ParseFiles("templates/app-inst-unit-template.txt")
+				unitTemplate, err := template.ParseFiles(
+					filepath.Join("..", "systemd", "templates", "app-inst-unit-template.txt"))
 				require.NoError(t, err)
 				// app1 systemd unit check.
 				appInstData := map[string]any{
 					"TT":         filepath.Join(fakeCfgPath, configure.BinPath, "tt"),
 					"ConfigPath": fakeCfgPath,
-					"FdLimit":    defaultInstanceFdLimit,
+					"FdLimit":    systemd.DefaultFdLimit,
 					"AppName":    "app1@%i",
 					"ExecArgs":   "app1:%i",
 				}
@@ -223,7 +225,7 @@ func Test_getUnitParams(t *testing.T) {
 		name    string
 		args    args
 		prepare func() error
-		want    systemdUnitParams
+		want    systemd.UnitParams
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
@@ -239,10 +241,10 @@ func Test_getUnitParams(t *testing.T) {
 					AppsInfo: appsInfo,
 				},
 			},
-			want: systemdUnitParams{
+			want: systemd.UnitParams{
 				TT:          "tt",
 				ConfigPath:  "/path/to/env",
-				FdLimit:     defaultInstanceFdLimit,
+				FdLimit:     systemd.DefaultFdLimit,
 				AppName:     "envName",
 				ExecArgs:    "envName:%i",
 				InstanceEnv: map[string]string{},
@@ -267,7 +269,7 @@ func Test_getUnitParams(t *testing.T) {
 					AppsInfo: appsInfo,
 				},
 			},
-			want: systemdUnitParams{
+			want: systemd.UnitParams{
 				TT:         "tt",
 				ConfigPath: "/path/to/env",
 				FdLimit:    1024,
@@ -303,7 +305,7 @@ func Test_getUnitParams(t *testing.T) {
 					},
 				},
 			},
-			want: systemdUnitParams{
+			want: systemd.UnitParams{
 				TT:         "/usr/bin/tt",
 				ConfigPath: "/test/path",
 				FdLimit:    1024,
@@ -335,7 +337,7 @@ func Test_getUnitParams(t *testing.T) {
 					AppsInfo: appsInfo,
 				},
 			},
-			want: systemdUnitParams{
+			want: systemd.UnitParams{
 				TT:          "tt",
 				ConfigPath:  "/path/to/env",
 				FdLimit:     1024,
@@ -375,7 +377,7 @@ instance-env:
 					},
 				},
 			},
-			want: systemdUnitParams{
+			want: systemd.UnitParams{
 				TT:         "tt",
 				ConfigPath: "/path/to/env",
 				FdLimit:    128,
@@ -0,0 +1,283 @@
+package replicaset
+
+import (
+	_ "embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	defaultCartridgeFailoverFilename = "failover.yml"
+	// defaultCartridgeCookie is a cluster cookie used by Cartridge if it is
+	// not configured.
+	defaultCartridgeCookie = "secret-cluster-cookie"
+)
+
+//go:embed lua/cartridge/get_clusterwide_config_body.lua
+var cartridgeGetClusterwideConfigBody string
+
+// CartridgeConfig is a clusterwide configuration of a Cartridge application.
+type CartridgeConfig struct {
+	// Config is a clusterwide configuration by sections.
+	Config map[string]any
+	// Aliases are aliases of the instances by UUIDs.
+	Aliases map[string]string
+	// Cookie is a cluster cookie.
+	Cookie string
+	// FromAppFiles is true if the configuration is made from replicasets.yml
+	// and failover.yml files of the application because the clusterwide
+	// configuration is not found.
+	FromAppFiles bool
+}
+
+// normalizeCartridgeValue converts maps with string keys into
+// map[string]any deeply.
+func normalizeCartridgeValue(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = normalizeCartridgeValue(elem)
+		}
+		return m
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = normalizeCartridgeValue(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = normalizeCartridgeValue(elem)
+		}
+	}
+	return value
+}
+
+// GetCartridgeConfig returns a clusterwide configuration received from an
+// instance with the Cartridge orchestrator.
+func GetCartridgeConfig(evaler connector.Evaler) (CartridgeConfig, error) {
+	var config CartridgeConfig
+	data, err := evaler.Eval(cartridgeGetClusterwideConfigBody, []any{},
+		connector.RequestOpts{})
+	if err != nil {
+		return config, err
+	}
+	if len(data) != 1 {
+		return config, fmt.Errorf("unexpected response: %v", data)
+	}
+
+	if err := mapstructure.Decode(normalizeCartridgeValue(data[0]), &config); err != nil {
+		return config, fmt.Errorf("failed to parse a response: %w", err)
+	}
+	return config, nil
+}
+
+// loadCartridgeConfigDir loads a clusterwide configuration from a directory.
+// Each file is a section, YAML files are parsed.
+func loadCartridgeConfigDir(dir string) (map[string]any, error) {
+	config := map[string]any{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		section, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		section = filepath.ToSlash(section)
+		ext := filepath.Ext(section)
+		if ext != ".yml" && ext != ".yaml" {
+			config[section] = string(data)
+			return nil
+		}
+
+		var value any
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("failed to parse %q: %w", path, err)
+		}
+		config[strings.TrimSuffix(section, ext)] = normalizeCartridgeValue(value)
+		return nil
+	})
+	return config, err
+}
+
+// loadCartridgeConfigWorkDir loads a clusterwide configuration from a work
+// directory of an instance. It returns nil if the configuration is not found.
+func loadCartridgeConfigWorkDir(workDir string) (map[string]any, error) {
+	if dir := filepath.Join(workDir, "config"); util.IsDir(dir) {
+		return loadCartridgeConfigDir(dir)
+	}
+
+	file := filepath.Join(workDir, "config.yml")
+	if !util.IsRegularFile(file) {
+		return nil, nil
+	}
+	config, err := parseYaml[map[string]any](file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", file, err)
+	}
+	return normalizeCartridgeValue(config).(map[string]any), nil
+}
+
+// makeCartridgeConfig makes a clusterwide configuration from replicasets.yml
+// and failover.yml files of an application.
+func makeCartridgeConfig(appDir string,
+	instances map[string]cartridgeInstanceConfig,
+) (map[string]any, error) {
+	replicasetsCfg, _, err := getCartridgeReplicasetsConfig(appDir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a replicasets configuration: %w", err)
+	}
+
+	servers := map[string]any{}
+	replicasets := map[string]any{}
+	for name, replicasetCfg := range replicasetsCfg {
+		alias := name
+		if replicasetCfg.Alias != "" {
+			alias = replicasetCfg.Alias
+		}
+
+		roles := map[string]any{}
+		for _, role := range replicasetCfg.Roles {
+			roles[role] = true
+		}
+		master := []any{}
+		for _, instance := range replicasetCfg.Instances {
+			master = append(master, instance)
+			servers[instance] = map[string]any{
+				"uri":             instances[instance].URI,
+				"replicaset_uuid": alias,
+			}
+		}
+
+		replicaset := map[string]any{
+			"alias":  alias,
+			"roles":  roles,
+			"master": master,
+		}
+		if replicasetCfg.Weight != nil {
+			replicaset["weight"] = *replicasetCfg.Weight
+		}
+		if replicasetCfg.AllRW != nil {
+			replicaset["all_rw"] = *replicasetCfg.AllRW
+		}
+		if replicasetCfg.VShardGroup != nil {
+			replicaset["vshard_group"] = *replicasetCfg.VShardGroup
+		}
+		replicasets[alias] = replicaset
+	}
+
+	topology := map[string]any{
+		"servers":     servers,
+		"replicasets": replicasets,
+	}
+	failoverFile, err := util.GetYamlFileName(
+		filepath.Join(appDir, defaultCartridgeFailoverFilename), false)
+	if err != nil {
+		return nil, err
+	}
+	if failoverFile != "" {
+		failover, err := parseYaml[map[string]any](failoverFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", failoverFile, err)
+		}
+		topology["failover"] = normalizeCartridgeValue(failover)
+	}
+
+	return map[string]any{"topology": topology}, nil
+}
+
+// getCartridgeCookie returns a cluster cookie from instances.yml file of an
+// application.
+func getCartridgeCookie(appName, appDir string) (string, error) {
+	filename, err := util.GetYamlFileName(
+		filepath.Join(appDir, defaultCartridgeInstancesFilename), true)
+	if err != nil {
+		return "", err
+	}
+	sections, err := parseYaml[map[string]map[string]any](filename)
+	if err != nil {
+		return "", err
+	}
+
+	cookie := defaultCartridgeCookie
+	if value, ok := sections[appName]["cluster_cookie"]; ok {
+		cookie = fmt.Sprint(value)
+	}
+	return cookie, nil
+}
+
+// LoadCartridgeConfig loads a clusterwide configuration of a Cartridge
+// application from work directories of its instances. If the configuration
+// is not found, it is made from replicasets.yml and failover.yml files of
+// the application.
+func LoadCartridgeConfig(runningCtx running.RunningCtx) (CartridgeConfig, error) {
+	var config CartridgeConfig
+	if len(runningCtx.Instances) == 0 {
+		return config, fmt.Errorf("there are no instances")
+	}
+	appName := runningCtx.Instances[0].AppName
+	appDir := runningCtx.Instances[0].AppDir
+
+	instances, err := getCartridgeInstancesConfig(appName, appDir)
+	if err != nil {
+		return config, fmt.Errorf("failed to get an instances configuration: %w", err)
+	}
+	if config.Cookie, err = getCartridgeCookie(appName, appDir); err != nil {
+		return config, fmt.Errorf("failed to get a cluster cookie: %w", err)
+	}
+
+	for _, inst := range runningCtx.Instances {
+		if inst.WalDir == "" {
+			continue
+		}
+		if config.Config, err = loadCartridgeConfigWorkDir(inst.WalDir); err != nil {
+			return config, fmt.Errorf("failed to load a clusterwide configuration: %w", err)
+		}
+		if config.Config != nil {
+			break
+		}
+	}
+
+	config.Aliases = map[string]string{}
+	if config.Config == nil {
+		config.FromAppFiles = true
+		if config.Config, err = makeCartridgeConfig(appDir, instances); err != nil {
+			return config, err
+		}
+		for alias := range instances {
+			config.Aliases[alias] = alias
+		}
+		return config, nil
+	}
+
+	// Instances UUIDs are unknown, so match them by advertise URIs.
+	aliasesByURI := map[string]string{}
+	for alias, instance := range instances {
+		aliasesByURI[instance.URI] = alias
+	}
+	topology, _ := config.Config["topology"].(map[string]any)
+	servers, _ := topology["servers"].(map[string]any)
+	for uuid, server := range servers {
+		server, _ := server.(map[string]any)
+		uri, _ := server["uri"].(string)
+		if alias, ok := aliasesByURI[uri]; ok {
+			config.Aliases[uuid] = alias
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,226 @@
+package replicaset_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/replicaset"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestGetCartridgeConfig(t *testing.T) {
+	evaler := &instanceMockEvaler{
+		Ret: [][]any{{map[any]any{
+			"config": map[any]any{
+				"topology": map[any]any{
+					"servers": map[any]any{
+						"uuid-a": map[any]any{"uri": "localhost:3301"},
+					},
+				},
+			},
+			"aliases": map[any]any{"uuid-a": "a"},
+			"cookie":  "cookie",
+		}}},
+	}
+	config, err := replicaset.GetCartridgeConfig(evaler)
+	require.NoError(t, err)
+	assert.Equal(t, replicaset.CartridgeConfig{
+		Config: map[string]any{
+			"topology": map[string]any{
+				"servers": map[string]any{
+					"uuid-a": map[string]any{"uri": "localhost:3301"},
+				},
+			},
+		},
+		Aliases: map[string]string{"uuid-a": "a"},
+		Cookie:  "cookie",
+	}, config)
+}
+
+func TestGetCartridgeConfig_errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		evaler *instanceMockEvaler
+		err    string
+	}{
+		{
+			name:   "eval error",
+			evaler: &instanceMockEvaler{Error: []error{errors.New("foo")}},
+			err:    "foo",
+		},
+		{
+			name:   "empty response",
+			evaler: &instanceMockEvaler{Ret: [][]any{{}}},
+			err:    "unexpected response: []",
+		},
+		{
+			name:   "invalid response",
+			evaler: &instanceMockEvaler{Ret: [][]any{{"foo"}}},
+			err:    "failed to parse a response",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := replicaset.GetCartridgeConfig(tc.evaler)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+// writeCartridgeApp writes files of a Cartridge application and returns
+// a running context of the application.
+func writeCartridgeApp(t *testing.T, files map[string]string) running.RunningCtx {
+	t.Helper()
+
+	appDir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(appDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+	return running.RunningCtx{
+		Instances: []running.InstanceCtx{
+			{
+				AppName: "app",
+				AppDir:  appDir,
+				WalDir:  filepath.Join(appDir, "tmp", "app.router"),
+			},
+			{
+				AppName: "app",
+				AppDir:  appDir,
+				WalDir:  filepath.Join(appDir, "tmp", "app.storage"),
+			},
+		},
+	}
+}
+
+const cartridgeConfigInstances = `app:
+  cluster_cookie: cookie
+app.router:
+  advertise_uri: localhost:3301
+app.storage:
+  advertise_uri: localhost:3302
+`
+
+func TestLoadCartridgeConfig_workDir(t *testing.T) {
+	runningCtx := writeCartridgeApp(t, map[string]string{
+		"instances.yml": cartridgeConfigInstances,
+		"tmp/app.storage/config/topology.yml": `servers:
+  uuid-router:
+    uri: localhost:3301
+  uuid-storage:
+    uri: localhost:3302
+  uuid-unknown:
+    uri: localhost:3303
+`,
+		"tmp/app.storage/config/custom.txt": "text",
+	})
+
+	config, err := replicaset.LoadCartridgeConfig(runningCtx)
+	require.NoError(t, err)
+	assert.False(t, config.FromAppFiles)
+	assert.Equal(t, "cookie", config.Cookie)
+	assert.Equal(t, map[string]string{
+		"uuid-router":  "router",
+		"uuid-storage": "storage",
+	}, config.Aliases)
+	assert.Equal(t, "text", config.Config["custom.txt"])
+	assert.Contains(t, config.Config["topology"], "servers")
+}
+
+func TestLoadCartridgeConfig_configFile(t *testing.T) {
+	runningCtx := writeCartridgeApp(t, map[string]string{
+		"instances.yml": "app.router:\n  advertise_uri: localhost:3301\n",
+		"tmp/app.router/config.yml": `topology:
+  servers:
+    uuid-router:
+      uri: localhost:3301
+vshard:
+  bucket_count: 100
+`,
+	})
+
+	config, err := replicaset.LoadCartridgeConfig(runningCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-cluster-cookie", config.Cookie)
+	assert.Equal(t, map[string]string{"uuid-router": "router"}, config.Aliases)
+	assert.Equal(t, map[string]any{"bucket_count": 100}, config.Config["vshard"])
+}
+
+func TestLoadCartridgeConfig_appFiles(t *testing.T) {
+	runningCtx := writeCartridgeApp(t, map[string]string{
+		"instances.yml": cartridgeConfigInstances,
+		"replicasets.yml": `router:
+  instances:
+  - router
+  roles:
+  - vshard-router
+s-1:
+  instances:
+  - storage
+  roles:
+  - vshard-storage
+  weight: 2
+  all_rw: true
+`,
+		"failover.yml": "mode: stateful\nstate_provider: stateboard\n",
+	})
+
+	config, err := replicaset.LoadCartridgeConfig(runningCtx)
+	require.NoError(t, err)
+	assert.True(t, config.FromAppFiles)
+	assert.Equal(t, "cookie", config.Cookie)
+	assert.Equal(t, map[string]string{"router": "router", "storage": "storage"},
+		config.Aliases)
+	assert.Equal(t, map[string]any{
+		"topology": map[string]any{
+			"servers": map[string]any{
+				"router": map[string]any{
+					"uri":             "localhost:3301",
+					"replicaset_uuid": "router",
+				},
+				"storage": map[string]any{
+					"uri":             "localhost:3302",
+					"replicaset_uuid": "s-1",
+				},
+			},
+			"replicasets": map[string]any{
+				"router": map[string]any{
+					"alias":  "router",
+					"roles":  map[string]any{"vshard-router": true},
+					"master": []any{"router"},
+				},
+				"s-1": map[string]any{
+					"alias":  "s-1",
+					"roles":  map[string]any{"vshard-storage": true},
+					"master": []any{"storage"},
+					"weight": 2.0,
+					"all_rw": true,
+				},
+			},
+			"failover": map[string]any{
+				"mode":           "stateful",
+				"state_provider": "stateboard",
+			},
+		},
+	}, config.Config)
+}
+
+func TestLoadCartridgeConfig_errors(t *testing.T) {
+	_, err := replicaset.LoadCartridgeConfig(running.RunningCtx{})
+	assert.EqualError(t, err, "there are no instances")
+
+	runningCtx := writeCartridgeApp(t, map[string]string{
+		"instances.yml": cartridgeConfigInstances,
+	})
+	_, err = replicaset.LoadCartridgeConfig(runningCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get a replicasets configuration")
+}
@@ -0,0 +1,295 @@
+package replicasetcmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/mitchellh/mapstructure"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/replicaset"
+	"github.com/tarantool/tt/cli/running"
+	libcluster "github.com/tarantool/tt/lib/cluster"
+)
+
+const (
+	// RollingRestartDefaultTimeout is a default timeout of a rolling restart
+	// step in seconds.
+	RollingRestartDefaultTimeout = 60
+	// rollingRestartCheckPeriod is a period of instance state checks.
+	rollingRestartCheckPeriod = 500 * time.Millisecond
+)
+
+// RollingRestartCtx describes the context to restart an application
+// replicaset by replicaset without losing the write master.
+type RollingRestartCtx struct {
+	// RunningCtx is an application running context.
+	RunningCtx running.RunningCtx
+	// Publishers is data publisher factory.
+	Publishers libcluster.DataPublisherFactory
+	// Collectors is data collector factory.
+	Collectors libcluster.DataCollectorFactory
+	// Orchestrator is a forced orchestrator choice.
+	Orchestrator replicaset.Orchestrator
+	// Restart restarts an instance.
+	Restart func(instance running.InstanceCtx) error
+	// SwitchBack is true if the original master should be promoted back
+	// after its restart.
+	SwitchBack bool
+	// Timeout describes a timeout of each step in seconds.
+	// We keep int as it can be passed to the target instance.
+	Timeout int
+}
+
+// rollingOrchestrator is an orchestrator used by the rolling restart.
+type rollingOrchestrator interface {
+	replicaset.Discoverer
+	replicaset.Promoter
+}
+
+// rollingRestarter restarts instances of replicasets one by one.
+type rollingRestarter struct {
+	// orchestrator is used to promote instances.
+	orchestrator rollingOrchestrator
+	// restart restarts an instance.
+	restart func(instance running.InstanceCtx) error
+	// waitReady waits until the instance is running and has caught up with
+	// the master. The master could be nil.
+	waitReady func(instance running.InstanceCtx, master *running.InstanceCtx,
+		timeout int) error
+	// waitRW waits until the instance becomes writable.
+	waitRW func(instance running.InstanceCtx, timeout int) error
+	// switchBack is true if the original master should be promoted back.
+	switchBack bool
+	// timeout is a timeout of each step in seconds.
+	timeout int
+}
+
+// RollingRestart restarts an application replicaset by replicaset. Replicas
+// are restarted one by one, then a restarted replica is promoted and the old
+// master is restarted.
+func RollingRestart(ctx RollingRestartCtx) error {
+	orchestratorType, err := getApplicationOrchestrator(ctx.Orchestrator, ctx.RunningCtx)
+	if err != nil {
+		return err
+	}
+
+	orchestrator, err := makeApplicationOrchestrator(orchestratorType,
+		ctx.RunningCtx, ctx.Collectors, ctx.Publishers)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Discovery application...")
+	fmt.Println()
+
+	// Get and print status.
+	replicasets, err := orchestrator.Discovery(replicaset.SkipCache)
+	if err != nil {
+		return err
+	}
+	statusReplicasets(replicasets)
+	fmt.Println()
+
+	restarter := rollingRestarter{
+		orchestrator: orchestrator,
+		restart:      ctx.Restart,
+		waitReady:    waitInstanceReady,
+		waitRW:       waitInstanceRW,
+		switchBack:   ctx.SwitchBack,
+		timeout:      ctx.Timeout,
+	}
+	for _, rs := range fillAliases(replicasets).Replicasets {
+		log.Infof("Restart replicaset: %s", rs.Alias)
+		if err := restarter.restartReplicaset(rs); err != nil {
+			return fmt.Errorf("replicaset %s: %w", rs.Alias, err)
+		}
+	}
+	log.Info("Done.")
+	return nil
+}
+
+// restartReplicaset restarts instances of the replicaset. The master is
+// restarted last after a switchover to a restarted replica.
+func (r *rollingRestarter) restartReplicaset(rs replicaset.Replicaset) error {
+	var (
+		master   *replicaset.Instance
+		replicas []replicaset.Instance
+	)
+	for i, instance := range rs.Instances {
+		if !instance.InstanceCtxFound {
+			return fmt.Errorf("instance %q should be online", instance.Alias)
+		}
+		switch instance.Mode {
+		case replicaset.ModeRW:
+			if master != nil {
+				return fmt.Errorf("%q and %q are both masters, "+
+					"rolling restart is not supported", master.Alias, instance.Alias)
+			}
+			master = &rs.Instances[i]
+		case replicaset.ModeRead:
+			replicas = append(replicas, instance)
+		default:
+			return fmt.Errorf("unable to determine a mode of the instance %q",
+				instance.Alias)
+		}
+	}
+
+	var masterCtx *running.InstanceCtx
+	if master != nil {
+		masterCtx = &master.InstanceCtx
+	}
+	for _, replica := range replicas {
+		if err := r.restartInstance(replica, masterCtx); err != nil {
+			return err
+		}
+	}
+
+	if master == nil {
+		return nil
+	}
+	if len(replicas) == 0 {
+		log.Warnf("There are no replicas, the master %q is restarted without a switchover",
+			master.Alias)
+		return r.restartInstance(*master, nil)
+	}
+
+	candidate := replicas[0]
+	if err := r.promote(candidate); err != nil {
+		return err
+	}
+	if err := r.restartInstance(*master, &candidate.InstanceCtx); err != nil {
+		return err
+	}
+	if r.switchBack {
+		return r.promote(*master)
+	}
+	return nil
+}
+
+// restartInstance restarts the instance and waits until it is ready.
+func (r *rollingRestarter) restartInstance(instance replicaset.Instance,
+	master *running.InstanceCtx,
+) error {
+	log.Infof("Restart instance: %s", instance.Alias)
+	if err := r.restart(instance.InstanceCtx); err != nil {
+		return fmt.Errorf("failed to restart instance %q: %w", instance.Alias, err)
+	}
+	return r.waitReady(instance.InstanceCtx, master, r.timeout)
+}
+
+// promote promotes the instance and waits until it becomes writable.
+func (r *rollingRestarter) promote(instance replicaset.Instance) error {
+	log.Infof("Promote instance: %s", instance.Alias)
+	// Instances modes are changed by restarts, so the cache is outdated.
+	if _, err := r.orchestrator.Discovery(replicaset.SkipCache); err != nil {
+		return err
+	}
+	err := r.orchestrator.Promote(replicaset.PromoteCtx{
+		InstName: instance.Alias,
+		Timeout:  r.timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to promote instance %q: %w", instance.Alias, err)
+	}
+	return r.waitRW(instance.InstanceCtx, r.timeout)
+}
+
+// waitInstance calls the check function until it succeeds or the timeout
+// expires.
+func waitInstance(instance running.InstanceCtx, timeout int, state string,
+	check func() error,
+) error {
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("instance %q is not %s after %d seconds: %w",
+				running.GetAppInstanceName(instance), state, timeout, err)
+		}
+		time.Sleep(rollingRestartCheckPeriod)
+	}
+}
+
+// evalInstanceValue evaluates the expression on the instance and decodes
+// the first returned value.
+func evalInstanceValue(instance running.InstanceCtx, expr string, value any) error {
+	evaler := replicaset.MakeInstanceEvalFunc(instance)
+	res, err := evaler.Eval(expr, []any{}, connector.RequestOpts{})
+	if err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		return errors.New("empty result")
+	}
+	return mapstructure.Decode(res[0], value)
+}
+
+// waitInstanceReady waits until the instance is running and has caught up
+// with the master LSN at the moment of the instance start.
+func waitInstanceReady(instance running.InstanceCtx, master *running.InstanceCtx,
+	timeout int,
+) error {
+	var (
+		masterID  uint32
+		masterLSN uint64
+		synced    bool
+	)
+	return waitInstance(instance, timeout, "ready", func() error {
+		var status string
+		err := evalInstanceValue(instance,
+			"return type(box.cfg) ~= 'function' and box.info.status or 'unconfigured'",
+			&status)
+		if err != nil {
+			return err
+		}
+		if status != "running" {
+			return fmt.Errorf("status is %q", status)
+		}
+		if master == nil {
+			return nil
+		}
+
+		if !synced {
+			var info struct {
+				ID  uint32 `mapstructure:"id"`
+				LSN uint64 `mapstructure:"lsn"`
+			}
+			err := evalInstanceValue(*master,
+				"return {id = box.info.id, lsn = box.info.lsn}", &info)
+			if err != nil {
+				return fmt.Errorf("failed to get the master LSN: %w", err)
+			}
+			masterID, masterLSN, synced = info.ID, info.LSN, true
+		}
+		var lsn uint64
+		err = evalInstanceValue(instance,
+			fmt.Sprintf("return box.info.vclock[%d] or 0", masterID), &lsn)
+		if err != nil {
+			return err
+		}
+		if lsn < masterLSN {
+			return fmt.Errorf("current LSN %d is behind required master LSN %d",
+				lsn, masterLSN)
+		}
+		return nil
+	})
+}
+
+// waitInstanceRW waits until the instance becomes writable.
+func waitInstanceRW(instance running.InstanceCtx, timeout int) error {
+	return waitInstance(instance, timeout, "writable", func() error {
+		var ro bool
+		if err := evalInstanceValue(instance, "return box.info.ro", &ro); err != nil {
+			return err
+		}
+		if ro {
+			return errors.New("the instance is read-only")
+		}
+		return nil
+	})
+}
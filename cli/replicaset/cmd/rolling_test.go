@@ -0,0 +1,195 @@
+package replicasetcmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/replicaset"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// rollingSteps records steps of a rolling restart.
+type rollingSteps struct {
+	steps []string
+	// failStep is a step to fail.
+	failStep string
+}
+
+func (r *rollingSteps) add(step string) error {
+	r.steps = append(r.steps, step)
+	if step == r.failStep {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func (r *rollingSteps) Discovery(replicaset.CacheBehavior) (replicaset.Replicasets, error) {
+	return replicaset.Replicasets{}, nil
+}
+
+func (r *rollingSteps) Promote(ctx replicaset.PromoteCtx) error {
+	return r.add("promote " + ctx.InstName)
+}
+
+func newTestRollingRestarter(steps *rollingSteps, switchBack bool) rollingRestarter {
+	return rollingRestarter{
+		orchestrator: steps,
+		restart: func(instance running.InstanceCtx) error {
+			return steps.add("restart " + instance.InstName)
+		},
+		waitReady: func(instance running.InstanceCtx, master *running.InstanceCtx,
+			timeout int,
+		) error {
+			masterName := "-"
+			if master != nil {
+				masterName = master.InstName
+			}
+			return steps.add(fmt.Sprintf("ready %s %s %d",
+				instance.InstName, masterName, timeout))
+		},
+		waitRW: func(instance running.InstanceCtx, timeout int) error {
+			return steps.add(fmt.Sprintf("rw %s %d", instance.InstName, timeout))
+		},
+		switchBack: switchBack,
+		timeout:    10,
+	}
+}
+
+func newTestInstance(name string, mode replicaset.Mode) replicaset.Instance {
+	return replicaset.Instance{
+		Alias:            name,
+		Mode:             mode,
+		InstanceCtx:      running.InstanceCtx{InstName: name},
+		InstanceCtxFound: true,
+	}
+}
+
+func TestRollingRestarter_restartReplicaset(t *testing.T) {
+	cases := []struct {
+		name       string
+		instances  []replicaset.Instance
+		switchBack bool
+		failStep   string
+		steps      []string
+		err        string
+	}{
+		{
+			name: "master and replicas",
+			instances: []replicaset.Instance{
+				newTestInstance("r1", replicaset.ModeRead),
+				newTestInstance("m", replicaset.ModeRW),
+				newTestInstance("r2", replicaset.ModeRead),
+			},
+			steps: []string{
+				"restart r1", "ready r1 m 10",
+				"restart r2", "ready r2 m 10",
+				"promote r1", "rw r1 10",
+				"restart m", "ready m r1 10",
+			},
+		},
+		{
+			name: "switch back",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+				newTestInstance("r", replicaset.ModeRead),
+			},
+			switchBack: true,
+			steps: []string{
+				"restart r", "ready r m 10",
+				"promote r", "rw r 10",
+				"restart m", "ready m r 10",
+				"promote m", "rw m 10",
+			},
+		},
+		{
+			name: "single master",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+			},
+			switchBack: true,
+			steps:      []string{"restart m", "ready m - 10"},
+		},
+		{
+			name: "no master",
+			instances: []replicaset.Instance{
+				newTestInstance("r1", replicaset.ModeRead),
+				newTestInstance("r2", replicaset.ModeRead),
+			},
+			steps: []string{"restart r1", "ready r1 - 10", "restart r2", "ready r2 - 10"},
+		},
+		{
+			name: "replica is not ready",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+				newTestInstance("r1", replicaset.ModeRead),
+				newTestInstance("r2", replicaset.ModeRead),
+			},
+			failStep: "ready r1 m 10",
+			steps:    []string{"restart r1", "ready r1 m 10"},
+			err:      "failed",
+		},
+		{
+			name: "promote failed",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+				newTestInstance("r", replicaset.ModeRead),
+			},
+			failStep: "promote r",
+			steps:    []string{"restart r", "ready r m 10", "promote r"},
+			err:      `failed to promote instance "r": failed`,
+		},
+		{
+			name: "restart failed",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+			},
+			failStep: "restart m",
+			steps:    []string{"restart m"},
+			err:      `failed to restart instance "m": failed`,
+		},
+		{
+			name: "multiple masters",
+			instances: []replicaset.Instance{
+				newTestInstance("m1", replicaset.ModeRW),
+				newTestInstance("m2", replicaset.ModeRW),
+			},
+			err: `"m1" and "m2" are both masters, rolling restart is not supported`,
+		},
+		{
+			name: "unknown mode",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+				newTestInstance("r", replicaset.ModeUnknown),
+			},
+			err: `unable to determine a mode of the instance "r"`,
+		},
+		{
+			name: "offline instance",
+			instances: []replicaset.Instance{
+				newTestInstance("m", replicaset.ModeRW),
+				{Alias: "r", Mode: replicaset.ModeRead},
+			},
+			err: `instance "r" should be online`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			steps := &rollingSteps{failStep: tc.failStep}
+			restarter := newTestRollingRestarter(steps, tc.switchBack)
+			err := restarter.restartReplicaset(replicaset.Replicaset{
+				Alias:     "rs",
+				Instances: tc.instances,
+			})
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.steps, steps.steps)
+		})
+	}
+}
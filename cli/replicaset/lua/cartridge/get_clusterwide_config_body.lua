@@ -0,0 +1,27 @@
+local cartridge = require('cartridge')
+
+local servers, err = cartridge.admin_get_servers()
+
+if err ~= nil then
+    err = err.err
+end
+
+assert(err == nil, tostring(err))
+
+local aliases = setmetatable({}, {__serialize = 'map'})
+for _, server in pairs(servers) do
+    if server.uuid ~= nil and server.uuid ~= '' and server.alias ~= nil then
+        aliases[server.uuid] = server.alias
+    end
+end
+
+local config = cartridge.config_get_readonly()
+if config == nil then
+    config = setmetatable({}, {__serialize = 'map'})
+end
+
+return {
+    config = config,
+    aliases = aliases,
+    cookie = require('cartridge.cluster-cookie').cookie(),
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cgroups"
 	"github.com/tarantool/tt/cli/ttlog"
 	"github.com/tarantool/tt/lib/integrity"
 )
@@ -44,6 +46,14 @@ type baseInstance struct {
 	binaryPort string
 	// logDir is log files location.
 	logDir string
+	// cgroupRoot is a parent cgroup of instances cgroups. The default root
+	// is used if it is empty.
+	cgroupRoot string
+	// cgroup is a cgroup to place the process into. The process is not
+	// moved if it is empty.
+	cgroup string
+	// resourceLimits are resource limits of the cgroup.
+	resourceLimits cgroups.Limits
 	// IntegrityCtx contains information necessary to perform integrity checks.
 	integrityCtx integrity.IntegrityCtx
 	// integrityChecks tells whether integrity checks are turned on.
@@ -58,19 +68,22 @@ func newBaseInstance(tarantoolPath string, instanceCtx InstanceCtx,
 	opts ...InstanceOption,
 ) baseInstance {
 	baseInst := baseInstance{
-		tarantoolPath: tarantoolPath,
-		appPath:       instanceCtx.InstanceScript,
-		appName:       instanceCtx.AppName,
-		appDir:        instanceCtx.AppDir,
-		instName:      instanceCtx.InstName,
-		consoleSocket: instanceCtx.ConsoleSocket,
-		walDir:        instanceCtx.WalDir,
-		vinylDir:      instanceCtx.VinylDir,
-		memtxDir:      instanceCtx.MemtxDir,
-		logDir:        instanceCtx.LogDir,
-		binaryPort:    instanceCtx.BinaryPort,
-		stdOut:        os.Stdout,
-		stdErr:        os.Stderr,
+		tarantoolPath:  tarantoolPath,
+		appPath:        instanceCtx.InstanceScript,
+		appName:        instanceCtx.AppName,
+		appDir:         instanceCtx.AppDir,
+		instName:       instanceCtx.InstName,
+		consoleSocket:  instanceCtx.ConsoleSocket,
+		walDir:         instanceCtx.WalDir,
+		vinylDir:       instanceCtx.VinylDir,
+		memtxDir:       instanceCtx.MemtxDir,
+		logDir:         instanceCtx.LogDir,
+		binaryPort:     instanceCtx.BinaryPort,
+		cgroupRoot:     instanceCtx.CgroupRoot,
+		cgroup:         instanceCtx.Cgroup,
+		resourceLimits: instanceCtx.ResourceLimits,
+		stdOut:         os.Stdout,
+		stdErr:         os.Stderr,
 	}
 	for _, opt := range opts {
 		opt(&baseInst)
@@ -116,12 +129,55 @@ func StdLoggerOpt(logger ttlog.Logger) InstanceOption {
 	}
 }
 
+// startProcess starts the instance process inside the cgroup with resource
+// limits if it is set. If the kernel can not start a process inside a cgroup,
+// the process is moved into the cgroup right after the start.
+func (inst *baseInstance) startProcess(cmd *exec.Cmd) error {
+	release, inCgroup := func() {}, false
+	if inst.cgroup != "" {
+		root := inst.cgroupRoot
+		if root == "" {
+			root = cgroups.DefaultRoot(os.Getpid())
+		}
+		if err := cgroups.Create(root, inst.cgroup, inst.resourceLimits); err != nil {
+			return fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+		var err error
+		if release, inCgroup, err = cgroups.PrepareStart(cmd, inst.cgroup); err != nil {
+			return fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+	}
+
+	var err error
+	inst.processController, err = newProcessController(cmd)
+	release()
+	if err != nil {
+		return err
+	}
+
+	if inst.cgroup != "" && !inCgroup {
+		if err := cgroups.AddProcess(inst.cgroup, inst.processController.Process.Pid); err != nil {
+			inst.processController.Process.Kill()
+			inst.processController.Wait()
+			return fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+	}
+	return nil
+}
+
 // Wait waits for the child process to complete.
 func (inst *baseInstance) Wait() error {
 	if inst.processController == nil {
 		return fmt.Errorf("instance is not started")
 	}
-	return inst.processController.Wait()
+	err := inst.processController.Wait()
+	if inst.cgroup != "" {
+		// The cgroup is re-created on the next start.
+		if err := cgroups.Remove(inst.cgroup); err != nil {
+			log.Debugf("Failed to remove cgroup %q: %s", inst.cgroup, err)
+		}
+	}
+	return err
 }
 
 // SendSignal sends a signal to tarantool instance.
@@ -8,8 +8,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cluster"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/util"
+	libcluster "github.com/tarantool/tt/lib/cluster"
 	"github.com/tarantool/tt/lib/integrity"
 )
 
@@ -18,9 +21,19 @@ type clusterInstance struct {
 	baseInstance
 	// clusterConfigPath is a path of the cluster config.
 	clusterConfigPath string
-	runDir            string
+	// clusterRender defines how to render the cluster config or nil.
+	clusterRender *cluster.RenderOpts
+	runDir        string
+	// renderedConfigPath is a path of the rendered cluster config of
+	// the running process. It could contain values of secrets, so it is
+	// removed after the process exits.
+	renderedConfigPath string
 }
 
+// renderedClusterConfigFileName is a name of a rendered cluster config file
+// in the run directory of the instance.
+const renderedClusterConfigFileName = "config.yaml"
+
 // newClusterInstance creates a clusterInstance.
 func newClusterInstance(tarantoolCli cmdcontext.TarantoolCli, instanceCtx InstanceCtx,
 	opts ...InstanceOption,
@@ -42,6 +55,7 @@ func newClusterInstance(tarantoolCli cmdcontext.TarantoolCli, instanceCtx Instan
 		baseInstance:      newBaseInstance(tarantoolCli.Executable, instanceCtx, opts...),
 		runDir:            instanceCtx.RunDir,
 		clusterConfigPath: instanceCtx.ClusterConfigPath,
+		clusterRender:     instanceCtx.ClusterRender,
 	}, nil
 }
 
@@ -53,9 +67,73 @@ func appendEnvIfNotEmpty(env []string, envVarName, value string) []string {
 	return env
 }
 
+// needRenderClusterConfig returns true if the cluster config must be rendered
+// before start: render options are set or the config has secret references.
+func (inst *clusterInstance) needRenderClusterConfig() bool {
+	if inst.clusterRender != nil {
+		return true
+	}
+	data, err := os.ReadFile(inst.clusterConfigPath)
+	return err == nil && libcluster.HasSecrets(data)
+}
+
+// renderClusterConfig renders the cluster config into the run directory and
+// returns a path to the rendered config. Secret references are resolved.
+func (inst *clusterInstance) renderClusterConfig() (string, error) {
+	if inst.integrityChecks {
+		return "", fmt.Errorf("rendering of a cluster config is not supported " +
+			"with integrity checks")
+	}
+	if inst.runDir == "" {
+		return "", fmt.Errorf("unable to render a cluster config: run directory is not set")
+	}
+
+	var data []byte
+	if inst.clusterRender != nil {
+		opts := *inst.clusterRender
+		opts.Secrets = cluster.NewSecretResolvers()
+		var err error
+		data, _, err = cluster.RenderConfig(libcluster.NewDataCollectorFactory(),
+			inst.clusterConfigPath, opts)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		// Variables are substituted only with render options, so only secret
+		// references are resolved and "${...}" values are kept as is.
+		src, err := os.ReadFile(inst.clusterConfigPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read a cluster config: %w", err)
+		}
+		config, err := libcluster.NewYamlCollector(src).Collect()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse a cluster config: %w", err)
+		}
+		config, err = libcluster.ResolveSecrets(config, cluster.NewSecretResolvers())
+		if err != nil {
+			return "", fmt.Errorf("failed to render a configuration: %w", err)
+		}
+		data = []byte(config.String())
+	}
+	path := filepath.Join(inst.runDir, renderedClusterConfigFileName)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write a rendered cluster config: %w", err)
+	}
+	return path, nil
+}
+
 // Start starts tarantool instance with cluster config.
 func (inst *clusterInstance) Start(ctx context.Context) error {
-	cmdArgs := []string{"-n", inst.instName, "-c", inst.clusterConfigPath}
+	configPath := inst.clusterConfigPath
+	if inst.needRenderClusterConfig() {
+		var err error
+		if configPath, err = inst.renderClusterConfig(); err != nil {
+			return err
+		}
+		inst.renderedConfigPath = configPath
+	}
+
+	cmdArgs := []string{"-n", inst.instName, "-c", configPath}
 	if inst.integrityChecks {
 		cmdArgs = append(cmdArgs, "--integrity-check",
 			filepath.Join(inst.appDir, integrity.HashesFileName))
@@ -91,10 +169,29 @@ func (inst *clusterInstance) Start(ctx context.Context) error {
 		return fmt.Errorf("application %q is not a directory", inst.appDir)
 	}
 
-	var err error
-	if inst.processController, err = newProcessController(cmd); err != nil {
+	if err := inst.startProcess(cmd); err != nil {
+		inst.removeRenderedConfig()
 		return err
 	}
-
 	return nil
 }
+
+// Wait waits for the child process to complete and removes the rendered
+// cluster config.
+func (inst *clusterInstance) Wait() error {
+	err := inst.baseInstance.Wait()
+	inst.removeRenderedConfig()
+	return err
+}
+
+// removeRenderedConfig removes the rendered cluster config if it exists.
+func (inst *clusterInstance) removeRenderedConfig() {
+	if inst.renderedConfigPath == "" {
+		return
+	}
+	if err := os.Remove(inst.renderedConfigPath); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove a rendered cluster config %q: %s",
+			inst.renderedConfigPath, err)
+	}
+	inst.renderedConfigPath = ""
+}
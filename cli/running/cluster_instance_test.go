@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cluster"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/ttlog"
 	"github.com/tarantool/tt/cli/util"
@@ -228,3 +230,89 @@ func TestClusterInstance_StopByContext(t *testing.T) {
 	assert.Error(t, clusterInstance.Wait(), context.Canceled)
 	assert.True(t, clusterInstance.ProcessState().Success())
 }
+
+func TestClusterInstance_renderClusterConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	overlayPath := filepath.Join(tmpDir, "prod.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("log:\n  level: 5\n"), 0o644))
+	require.NoError(t, os.WriteFile(overlayPath, []byte("log:\n  level: ${LEVEL}\n"), 0o644))
+	t.Setenv("LEVEL", "6")
+
+	runDir := filepath.Join(tmpDir, "run")
+	require.NoError(t, os.Mkdir(runDir, 0o755))
+	inst := clusterInstance{
+		clusterConfigPath: configPath,
+		clusterRender:     &cluster.RenderOpts{Overlays: []string{overlayPath}},
+		runDir:            runDir,
+	}
+	path, err := inst.renderClusterConfig()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(runDir, renderedClusterConfigFileName), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "log:\n  level: \"6\"\n", string(data))
+
+	inst.integrityChecks = true
+	_, err = inst.renderClusterConfig()
+	assert.EqualError(t, err,
+		"rendering of a cluster config is not supported with integrity checks")
+
+	inst.integrityChecks = false
+	inst.runDir = ""
+	_, err = inst.renderClusterConfig()
+	assert.EqualError(t, err, "unable to render a cluster config: run directory is not set")
+}
+
+func TestClusterInstance_renderClusterConfig_secrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath,
+		[]byte("credentials:\n  users:\n    admin:\n"+
+			"      password: '{{ secret \"env:TT_TEST_ADMIN_PASSWORD\" }}'\n"), 0o644))
+	t.Setenv("TT_TEST_ADMIN_PASSWORD", "secret")
+
+	inst := clusterInstance{
+		clusterConfigPath: configPath,
+		runDir:            tmpDir,
+	}
+	require.True(t, inst.needRenderClusterConfig())
+	path, err := inst.renderClusterConfig()
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "credentials:\n  users:\n    admin:\n      password: secret\n",
+		string(data))
+
+	// Variables are not substituted without render options.
+	require.NoError(t, os.WriteFile(configPath,
+		[]byte("credentials:\n  users:\n    admin:\n"+
+			"      password: '{{ secret \"env:TT_TEST_ADMIN_PASSWORD\" }}'\n"+
+			"app:\n  cfg:\n    template: ${literal}\n"), 0o644))
+	path, err = inst.renderClusterConfig()
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "template: ${literal}")
+	assert.Contains(t, string(data), "password: secret")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("log:\n  level: 5\n"), 0o644))
+	assert.False(t, inst.needRenderClusterConfig())
+}
+
+func TestClusterInstance_Wait_removesRenderedConfig(t *testing.T) {
+	runDir := t.TempDir()
+	path := filepath.Join(runDir, renderedClusterConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte("password: secret\n"), 0o600))
+
+	controller, err := newProcessController(exec.Command("true"))
+	require.NoError(t, err)
+	inst := clusterInstance{
+		baseInstance:       baseInstance{processController: controller},
+		runDir:             runDir,
+		renderedConfigPath: path,
+	}
+	require.NoError(t, inst.Wait())
+	assert.NoFileExists(t, path)
+	assert.Empty(t, inst.renderedConfigPath)
+}
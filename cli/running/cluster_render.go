@@ -0,0 +1,130 @@
+package running
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tarantool/tt/cli/cluster"
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// clusterRenderFileName is a name of a file in the application run directory
+// with the options to render the cluster configuration of the application.
+const clusterRenderFileName = "cluster_render.yaml"
+
+// clusterRenderFile is a content of the file with the options to render
+// a cluster configuration.
+type clusterRenderFile struct {
+	// Overlays are absolute paths to configuration overlays.
+	Overlays []string `yaml:"overlays,omitempty"`
+	// VarsFile is an absolute path to a file with variables.
+	VarsFile string `yaml:"vars_file,omitempty"`
+}
+
+// clusterRenderCtx describes where to find the options to render cluster
+// configurations of applications.
+type clusterRenderCtx struct {
+	// runDir is a run directory from the tt configuration. The stored options
+	// are not used if it is empty.
+	runDir string
+	// opts are used instead of the stored options if set.
+	opts *cluster.RenderOpts
+}
+
+// getAppRunDir returns a run directory of a multi-instance application, it
+// contains run directories of the application instances.
+func getAppRunDir(appDir, runDir string) string {
+	if filepath.IsAbs(runDir) {
+		return filepath.Join(runDir, filepath.Base(appDir))
+	}
+	return filepath.Join(appDir, runDir)
+}
+
+// getClusterRenderOpts returns the options to render the cluster
+// configuration of the application or nil if the configuration should not be
+// rendered.
+func getClusterRenderOpts(appDir string, renderCtx clusterRenderCtx) (*cluster.RenderOpts,
+	error,
+) {
+	if renderCtx.opts != nil {
+		return renderCtx.opts, nil
+	}
+	if renderCtx.runDir == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(getAppRunDir(appDir, renderCtx.runDir), clusterRenderFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cluster config render options: %w", err)
+	}
+
+	var file clusterRenderFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster config render options from %q: %w",
+			path, err)
+	}
+	opts := cluster.RenderOpts{Overlays: file.Overlays, VarsFile: file.VarsFile}
+	if !opts.IsSet() {
+		return nil, nil
+	}
+	return &opts, nil
+}
+
+// SetClusterRenderOpts stores the options to render the cluster configuration
+// into the run directory of the instances application, so all commands work
+// with the rendered configuration of the application. The options can not be
+// changed while the instances are running.
+func SetClusterRenderOpts(instances []InstanceCtx, opts cluster.RenderOpts) error {
+	file := clusterRenderFile{Overlays: make([]string, 0, len(opts.Overlays))}
+	for _, overlay := range opts.Overlays {
+		path, err := filepath.Abs(overlay)
+		if err != nil {
+			return err
+		}
+		file.Overlays = append(file.Overlays, path)
+	}
+	if opts.VarsFile != "" {
+		var err error
+		if file.VarsFile, err = filepath.Abs(opts.VarsFile); err != nil {
+			return err
+		}
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster config render options: %w", err)
+	}
+
+	appRunDirs := map[string]bool{}
+	for _, inst := range instances {
+		if inst.ClusterConfigPath == "" {
+			continue
+		}
+		procStatus := process_utils.ProcessStatus(inst.PIDFile)
+		if procStatus.Code == process_utils.ProcStateRunning.Code {
+			return fmt.Errorf("unable to change cluster config render options: "+
+				"the instance %s is running", GetAppInstanceName(inst))
+		}
+		// The instance run directory is inside the application run directory.
+		appRunDirs[filepath.Dir(inst.RunDir)] = true
+	}
+
+	for dir := range appRunDirs {
+		if err := util.CreateDirectory(dir, defaultDirPerms); err != nil {
+			return err
+		}
+		err := os.WriteFile(filepath.Join(dir, clusterRenderFileName), data, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write cluster config render options: %w", err)
+		}
+	}
+	return nil
+}
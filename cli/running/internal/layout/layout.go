@@ -12,4 +12,6 @@ type Layout interface {
 	DataDir(dir string) string
 	// BinaryPort returns binary port file path.
 	BinaryPort(dir string) string
+	// WatchdogStateFile returns watchdog state file path.
+	WatchdogStateFile(dir string) string
 }
@@ -64,6 +64,11 @@ func (layout MultiInstLayout) BinaryPort(dir string) string {
 	return layout.genFilePath(dir, "tarantool.sock")
 }
 
+// WatchdogStateFile returns watchdog state file path.
+func (layout MultiInstLayout) WatchdogStateFile(dir string) string {
+	return layout.genFilePath(dir, "tt.state")
+}
+
 // DataDir returns data directory path.
 func (layout MultiInstLayout) DataDir(dir string) string {
 	return filepath.Dir(layout.genFilePath(dir, "0"))
@@ -27,6 +27,7 @@ func TestMultiInstLayout(t *testing.T) {
 	assert.Equal(t, "/var/log/app1/master/tt.log", miLayout.LogFile("/var/log"))
 	assert.Equal(t, "/var/lib/app1/master", miLayout.DataDir("/var/lib"))
 	assert.Equal(t, "/var/run/app1/master/tarantool.sock", miLayout.BinaryPort("/var/run"))
+	assert.Equal(t, "/var/run/app1/master/tt.state", miLayout.WatchdogStateFile("/var/run"))
 }
 
 func TestMultiIntLayoutNewErr(t *testing.T) {
@@ -52,6 +52,11 @@ func (layout TntCtlLayout) BinaryPort(dir string) string {
 	return layout.genRuntimeFilePath(dir, layout.appName+".sock")
 }
 
+// WatchdogStateFile returns watchdog state file path.
+func (layout TntCtlLayout) WatchdogStateFile(dir string) string {
+	return layout.genRuntimeFilePath(dir, layout.appName+".state")
+}
+
 // DataDir returns data directory path.
 func (layout TntCtlLayout) DataDir(dir string) string {
 	return util.JoinPaths(layout.baseDir, dir, layout.appName)
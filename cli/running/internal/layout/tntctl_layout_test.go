@@ -22,6 +22,8 @@ func TestTntCtlLayout(t *testing.T) {
 		tntCtlLayout.DataDir("./lib/tarantool"))
 	assert.Equal(t, "/home/user/run/tarantool/app1.sock",
 		tntCtlLayout.BinaryPort("./run/tarantool"))
+	assert.Equal(t, "/home/user/run/tarantool/app1.state",
+		tntCtlLayout.WatchdogStateFile("./run/tarantool"))
 }
 
 func TestTntCtlLayoutNewErr(t *testing.T) {
@@ -15,10 +15,12 @@ import (
 	"time"
 
 	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cgroups"
 	"github.com/tarantool/tt/cli/cluster"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/coredump"
 	"github.com/tarantool/tt/cli/process_utils"
 	"github.com/tarantool/tt/cli/running/internal/layout"
 	"github.com/tarantool/tt/cli/ttlog"
@@ -26,11 +28,19 @@ import (
 	"github.com/tarantool/tt/cli/util/regexputil"
 	libcluster "github.com/tarantool/tt/lib/cluster"
 	"github.com/tarantool/tt/lib/integrity"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
 	"golang.org/x/sys/unix"
 )
 
 const defaultDirPerms = 0o770
 
+// defaultRestartDelay is a default delay before a restart of a failed instance.
+const defaultRestartDelay = 5 * time.Second
+
+// coredumpsDirName is a name of the instance run directory subdirectory with
+// captured core dumps.
+const coredumpsDirName = "coredumps"
+
 const (
 	// stateBoardInstName is cartridge stateboard instance name.
 	stateBoardInstName = "stateboard"
@@ -49,6 +59,10 @@ var (
 type RunningCtx struct {
 	// Instances contains information about application instances.
 	Instances []InstanceCtx
+	// ClusterRender defines overlays and variables to render the cluster
+	// configuration of the named application instead of the stored ones or
+	// nil.
+	ClusterRender *cluster.RenderOpts
 }
 
 // InstanceCtx contains information about application instance.
@@ -93,6 +107,23 @@ type InstanceCtx struct {
 	// If the instance is started under the watchdog it should
 	// restart on if it crashes.
 	Restartable bool
+	// RestartPolicy describes restarts of the instance if it crashes.
+	RestartPolicy libwatchdog.RestartPolicy
+	// WatchdogStateFile is a file with the state of the instance restarts.
+	WatchdogStateFile string
+	// CaptureCoredump is true to pack a core dump of the instance crashed by
+	// a signal into the run directory.
+	CaptureCoredump bool
+	// CoredumpDir is a directory to search core dumps in.
+	CoredumpDir string
+	// ResourceLimits are resource limits of the instance cgroup.
+	ResourceLimits cgroups.Limits
+	// CgroupRoot is a parent cgroup of instances cgroups. It is empty if
+	// the default root is used, it depends on the cgroup of the tt process.
+	CgroupRoot string
+	// Cgroup is a cgroup v2 of the instance. It is empty if there are no
+	// resource limits.
+	Cgroup string
 	// Control UNIX socket for started instance.
 	ConsoleSocket string
 	// Unix socket used as "binary port".
@@ -105,6 +136,10 @@ type InstanceCtx struct {
 	ClusterConfigPath string
 	// Configuration is instance configuration loaded from cluster config.
 	Configuration libcluster.InstanceConfig
+	// ClusterRender defines overlays and variables to render the cluster
+	// configuration or nil. The rendered configuration is written into the
+	// run directory on the instance start.
+	ClusterRender *cluster.RenderOpts
 }
 
 // RunOpts contains flags and args for tt run.
@@ -246,6 +281,40 @@ func (provider *providerImpl) IsRestartable() (bool, error) {
 	return provider.instanceCtx.Restartable, nil
 }
 
+// CaptureCoredump packs a core dump of the instance process crashed by
+// a signal. It returns an empty path if capturing is disabled.
+func (provider *providerImpl) CaptureCoredump(state *os.ProcessState) (string, error) {
+	inst := provider.instanceCtx
+	if !inst.CaptureCoredump {
+		return "", nil
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return "", nil
+	}
+	if !status.CoreDump() {
+		return "", coredump.ErrNoCoreDump
+	}
+
+	executable, err := exec.LookPath(provider.cmdCtx.Cli.TarantoolCli.Executable)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the tarantool executable: %w", err)
+	}
+	opts := coredump.CaptureOpts{
+		PID:        state.Pid(),
+		Signal:     status.Signal(),
+		Time:       time.Now(),
+		Executable: executable,
+		WorkDirs:   []string{inst.AppDir, inst.WalDir},
+		CoreDir:    inst.CoredumpDir,
+		OutputDir:  filepath.Join(inst.RunDir, coredumpsDirName),
+	}
+	if inst.Log != "" {
+		opts.Logs = append(opts.Logs, inst.Log)
+	}
+	return coredump.Capture(opts)
+}
+
 // searchApplicationScript searches for application script in a directory.
 func searchApplicationScript(applicationsDir, appName string) (InstanceCtx, error) {
 	instCtx := InstanceCtx{
@@ -336,7 +405,7 @@ func findInstanceScriptInAppDir(appDir, instName, clusterCfgPath, defaultScript
 }
 
 // loadInstanceConfig load instance configuration from cluster config.
-func loadInstanceConfig(configPath, instName string,
+func loadInstanceConfig(configPath, instName string, render *cluster.RenderOpts,
 	integrityCtx integrity.IntegrityCtx,
 ) (libcluster.InstanceConfig, error) {
 	var instCfg libcluster.InstanceConfig
@@ -357,9 +426,13 @@ func loadInstanceConfig(configPath, instName string,
 				return integrityCtx.Repository.Read(path)
 			})
 	}
-	collectors := libcluster.NewCollectorFactory(dataCollectors)
-
-	clusterCfg, err := cluster.GetClusterConfig(collectors, configPath)
+	var clusterCfg libcluster.ClusterConfig
+	if render != nil {
+		clusterCfg, err = cluster.GetRenderedClusterConfig(dataCollectors, configPath, *render)
+	} else {
+		collectors := libcluster.NewCollectorFactory(dataCollectors)
+		clusterCfg, err = cluster.GetClusterConfig(collectors, configPath)
+	}
 	if err != nil {
 		return instCfg, err
 	}
@@ -370,7 +443,7 @@ func loadInstanceConfig(configPath, instName string,
 }
 
 // collectInstancesFromAppDir collects instances information from application directory.
-func collectInstancesFromAppDir(appDir, selectedInstName string,
+func collectInstancesFromAppDir(appDir, selectedInstName string, renderCtx clusterRenderCtx,
 	integrityCtx integrity.IntegrityCtx, loadConfig ConfigLoad) (
 	[]InstanceCtx,
 	error,
@@ -418,10 +491,21 @@ func collectInstancesFromAppDir(appDir, selectedInstName string,
 	if err != nil {
 		return nil, err
 	}
+	var render *cluster.RenderOpts
+	if appDirFiles.clusterCfgPath != "" {
+		if render, err = getClusterRenderOpts(appDir, renderCtx); err != nil {
+			return nil, err
+		}
+	}
+
 	log.Debug("Processing application instances file")
 	instances := []InstanceCtx{}
 	for inst := range instParams {
-		instance := InstanceCtx{AppDir: appDir, ClusterConfigPath: appDirFiles.clusterCfgPath}
+		instance := InstanceCtx{
+			AppDir:            appDir,
+			ClusterConfigPath: appDirFiles.clusterCfgPath,
+			ClusterRender:     render,
+		}
 		instance.InstName = getInstanceName(inst, instance.ClusterConfigPath != "")
 		instance.AppName = filepath.Base(appDir)
 		if selectedInstName != "" && instance.InstName != selectedInstName {
@@ -434,7 +518,7 @@ func collectInstancesFromAppDir(appDir, selectedInstName string,
 		log.Debugf("Instance %q", instance.InstName)
 
 		instance.Configuration, err = loadInstanceConfig(instance.ClusterConfigPath,
-			instance.InstName, integrityCtx)
+			instance.InstName, instance.ClusterRender, integrityCtx)
 		if err != nil && (loadConfig == ConfigLoadAll || loadConfig == ConfigLoadCluster) {
 			return instances, fmt.Errorf("error loading instance %q configuration from "+
 				"config %q: %w", instance.InstName, instance.ClusterConfigPath, err)
@@ -462,6 +546,16 @@ func collectInstancesFromAppDir(appDir, selectedInstName string,
 func CollectInstances(appName, applicationsDir string,
 	integrityCtx integrity.IntegrityCtx, loadConfig ConfigLoad) (
 	[]InstanceCtx, error,
+) {
+	return collectInstances(appName, applicationsDir, clusterRenderCtx{}, integrityCtx,
+		loadConfig)
+}
+
+// collectInstances searches all instances available in application. Cluster
+// configurations are rendered with the options from the render context.
+func collectInstances(appName, applicationsDir string, renderCtx clusterRenderCtx,
+	integrityCtx integrity.IntegrityCtx, loadConfig ConfigLoad) (
+	[]InstanceCtx, error,
 ) {
 	// The user can select a specific instance from the application.
 	// Example: `tt status application:server`.
@@ -491,7 +585,8 @@ func CollectInstances(appName, applicationsDir string,
 		appDir = applicationsDir
 	}
 
-	return collectInstancesFromAppDir(appDir, selectedInstName, integrityCtx, loadConfig)
+	return collectInstancesFromAppDir(appDir, selectedInstName, renderCtx, integrityCtx,
+		loadConfig)
 }
 
 // cleanup removes runtime artifacts.
@@ -557,16 +652,107 @@ func mapValuesFromConfig[T any](cfg *libcluster.Config, mapFunc func(val T) (T,
 	return nil
 }
 
+// getRestartPolicy returns the restart policy from tt config options. A
+// constant default delay is used if the options are not set.
+func getRestartPolicy(opts *config.RestartPolicyOpts) libwatchdog.RestartPolicy {
+	if opts == nil {
+		return libwatchdog.NewConstantRestartPolicy(defaultRestartDelay)
+	}
+	policy := libwatchdog.RestartPolicy{
+		Delay:       time.Duration(opts.Delay) * time.Second,
+		MaxDelay:    time.Duration(opts.MaxDelay) * time.Second,
+		MaxRestarts: opts.MaxRestarts,
+		Window:      time.Duration(opts.Window) * time.Second,
+	}
+	if policy.Delay <= 0 {
+		policy.Delay = defaultRestartDelay
+	}
+	return policy
+}
+
+// cgroupsDisabledEnv is an environment variable that disables placing
+// instances into cgroups.
+const cgroupsDisabledEnv = "TT_CLI_CGROUPS_DISABLED"
+
+// getResourceLimits returns resource limits and the cgroup root from tt config
+// options.
+func getResourceLimits(opts *config.LimitsOpts) (cgroups.Limits, string, error) {
+	var limits cgroups.Limits
+	if opts == nil {
+		return limits, "", nil
+	}
+	if opts.MemoryMax != "" {
+		var err error
+		if limits.MemoryMax, err = cgroups.ParseMemorySize(opts.MemoryMax); err != nil {
+			return limits, "", fmt.Errorf("invalid memory_max: %w", err)
+		}
+	}
+	limits.CPUQuota = opts.CPUQuota
+	limits.IOWeight = opts.IOWeight
+	if err := limits.Validate(); err != nil {
+		return limits, "", fmt.Errorf("invalid resource limits: %w", err)
+	}
+
+	root := opts.CgroupRoot
+	if root == "" {
+		return limits, "", nil
+	}
+	if !filepath.IsAbs(root) {
+		return limits, "", fmt.Errorf("cgroup_root must be an absolute path: %q", root)
+	}
+	return limits, filepath.Clean(root), nil
+}
+
+// getInstanceCgroup returns a cgroup of the instance started by the tt
+// process with the pid.
+func getInstanceCgroup(inst *InstanceCtx, pid int) string {
+	root := inst.CgroupRoot
+	if root == "" {
+		root = cgroups.DefaultRoot(pid)
+	}
+	return filepath.Join(root, inst.AppName, inst.InstName)
+}
+
+// GetInstanceCgroup returns a cgroup of the instance with resource limits
+// started by the watchdog with the pid. The default cgroup root depends on
+// the cgroup of the watchdog.
+func GetInstanceCgroup(inst InstanceCtx, watchdogPid int) string {
+	return getInstanceCgroup(&inst, watchdogPid)
+}
+
 // setInstCtxFromTtConfig sets instance context members from tt config.
 func setInstCtxFromTtConfig(inst *InstanceCtx, cliOpts *config.CliOpts, ttConfigDir string) error {
 	tarantoolCtlLayout := false
+	var restartPolicyOpts *config.RestartPolicyOpts
+	var resourceLimitsOpts *config.LimitsOpts
 	if cliOpts.Env != nil {
 		inst.Restartable = cliOpts.Env.Restartable
+		restartPolicyOpts = cliOpts.Env.RestartPolicy
+		if cliOpts.Env.Coredump != nil {
+			inst.CaptureCoredump = cliOpts.Env.Coredump.Capture
+			inst.CoredumpDir = cliOpts.Env.Coredump.Dir
+			if inst.CoredumpDir != "" && !filepath.IsAbs(inst.CoredumpDir) {
+				inst.CoredumpDir = filepath.Join(ttConfigDir, inst.CoredumpDir)
+			}
+		}
+		resourceLimitsOpts = cliOpts.Env.ResourceLimits
 		tarantoolCtlLayout = cliOpts.Env.TarantoolctlLayout
 	}
+	inst.RestartPolicy = getRestartPolicy(restartPolicyOpts)
+
+	var err error
+	inst.ResourceLimits, inst.CgroupRoot, err = getResourceLimits(resourceLimitsOpts)
+	if err != nil {
+		return err
+	}
+	// The limits are applied by the service manager if tt is started by
+	// a generated systemd unit.
+	if inst.ResourceLimits.IsSet() && os.Getenv(cgroupsDisabledEnv) == "" {
+		inst.Cgroup = getInstanceCgroup(inst, os.Getpid())
+	}
+
 	if cliOpts.App != nil {
 		var envLayout layout.Layout = nil
-		var err error
 		if tarantoolCtlLayout && inst.SingleApp {
 			// Tarantoolctl layout is still relative to the configuration file location.
 			envLayout, err = layout.NewTntCtlLayout(ttConfigDir, inst.AppName)
@@ -580,6 +766,7 @@ func setInstCtxFromTtConfig(inst *InstanceCtx, cliOpts *config.CliOpts, ttConfig
 		inst.ConsoleSocket = envLayout.ConsoleSocket(cliOpts.App.RunDir)
 		inst.BinaryPort = envLayout.BinaryPort(cliOpts.App.RunDir)
 		inst.PIDFile = envLayout.PidFile(cliOpts.App.RunDir)
+		inst.WatchdogStateFile = envLayout.WatchdogStateFile(cliOpts.App.RunDir)
 		inst.RunDir = filepath.Dir(inst.ConsoleSocket)
 
 		inst.Log = envLayout.LogFile(cliOpts.App.LogDir)
@@ -656,16 +843,32 @@ func GetClusterConfigPath(cliOpts *config.CliOpts,
 func CollectInstancesForApps(appList []string, cliOpts *config.CliOpts,
 	ttConfigDir string, integrityCtx integrity.IntegrityCtx, loadConfig ConfigLoad) (
 	map[string][]InstanceCtx, error,
+) {
+	return collectInstancesForApps(appList, cliOpts, ttConfigDir, nil, integrityCtx,
+		loadConfig)
+}
+
+// collectInstancesForApps collects instances information per application.
+// Cluster configurations are rendered with the render options if set or with
+// the options stored in the applications run directories.
+func collectInstancesForApps(appList []string, cliOpts *config.CliOpts,
+	ttConfigDir string, render *cluster.RenderOpts, integrityCtx integrity.IntegrityCtx,
+	loadConfig ConfigLoad) (
+	map[string][]InstanceCtx, error,
 ) {
 	instEnabledPath := cliOpts.Env.InstancesEnabled
 	if cliOpts.Env.InstancesEnabled == "." {
 		instEnabledPath = ttConfigDir
 	}
+	renderCtx := clusterRenderCtx{opts: render}
+	if cliOpts.App != nil {
+		renderCtx.runDir = cliOpts.App.RunDir
+	}
 	apps := make(map[string][]InstanceCtx)
 	for _, appName := range appList {
 		appName = strings.TrimSuffix(appName, ".lua")
-		collectedInstances, err := CollectInstances(appName, instEnabledPath, integrityCtx,
-			loadConfig)
+		collectedInstances, err := collectInstances(appName, instEnabledPath, renderCtx,
+			integrityCtx, loadConfig)
 		if err != nil {
 			return apps, fmt.Errorf("can't collect instance information for %s: %w",
 				appName, err)
@@ -724,6 +927,11 @@ func FillCtx(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx,
 		return fmt.Errorf(`%s not found`, configure.ConfigName)
 	}
 
+	// The render options are scoped to the named application.
+	if runningCtx.ClusterRender != nil && len(args) == 0 {
+		return fmt.Errorf("cluster config render options require an application name")
+	}
+
 	var appList []string
 	if len(args) == 0 {
 		appList, err = util.CollectAppList(cmdCtx.Cli.ConfigDir, cliOpts.Env.InstancesEnabled,
@@ -736,8 +944,8 @@ func FillCtx(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx,
 		appList = append(appList, args[0])
 	}
 
-	instances, err := CollectInstancesForApps(appList, cliOpts, cmdCtx.Cli.ConfigDir,
-		cmdCtx.Integrity, loadConfig)
+	instances, err := collectInstancesForApps(appList, cliOpts, cmdCtx.Cli.ConfigDir,
+		runningCtx.ClusterRender, cmdCtx.Integrity, loadConfig)
 	if err != nil {
 		return err
 	}
@@ -810,7 +1018,7 @@ func Start(cmdCtx *cmdcontext.CmdCtx, inst *InstanceCtx) error {
 		}
 		return nil
 	}
-	wd := NewWatchdog(inst.Restartable, 5*time.Second, logger,
+	wd := NewWatchdog(inst.Restartable, inst.RestartPolicy, inst.WatchdogStateFile, logger,
 		&provider, preStartAction, cmdCtx.Integrity,
 		time.Duration(cmdCtx.Cli.IntegrityCheckPeriod*int(time.Second)))
 
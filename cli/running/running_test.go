@@ -5,14 +5,19 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cgroups"
+	"github.com/tarantool/tt/cli/cluster"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/lib/integrity"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
 	"golang.org/x/exp/slices"
 )
 
@@ -349,6 +354,89 @@ func Test_collectInstancesForApps(t *testing.T) {
 	require.Equal(t, 3, comparisonsCount)
 }
 
+func Test_collectInstancesForApps_render(t *testing.T) {
+	tmpDir := t.TempDir()
+	overlay := filepath.Join(tmpDir, "prod.yaml")
+	require.NoError(t, os.WriteFile(overlay, []byte(`groups:
+  group-001:
+    replicasets:
+      replicaset-001:
+        instances:
+          instance-001:
+            wal:
+              dir: ${WAL_DIR}
+`), 0o644))
+	varsFile := filepath.Join(tmpDir, "vars.yaml")
+	require.NoError(t, os.WriteFile(varsFile, []byte("WAL_DIR: ./prod_wal_dir\n"), 0o644))
+	renderOpts := &cluster.RenderOpts{
+		Overlays: []string{overlay},
+		VarsFile: varsFile,
+	}
+
+	instancesEnabled, err := filepath.Abs("./testdata/instances_enabled")
+	require.NoError(t, err)
+	appLocation := filepath.Join(instancesEnabled, "cluster_app")
+	appName := "cluster_app:instance-001"
+	cliOpts := configure.GetDefaultCliOpts()
+	cliOpts.Env.InstancesEnabled = instancesEnabled
+	cliOpts.App.RunDir = filepath.Join(tmpDir, "run")
+	collect := func(render *cluster.RenderOpts) InstanceCtx {
+		t.Helper()
+		instances, err := collectInstancesForApps([]string{appName}, cliOpts,
+			"/etc/tarantool/", render, integrity.IntegrityCtx{
+				Repository: &mockRepository{},
+			}, ConfigLoadAll)
+		require.NoError(t, err)
+		require.Len(t, instances[appName], 1)
+		return instances[appName][0]
+	}
+
+	// The options are used instead of the stored ones.
+	inst := collect(renderOpts)
+	assert.Equal(t, filepath.Join(appLocation, "prod_wal_dir"), inst.WalDir)
+	assert.Equal(t, filepath.Join(appLocation, "config.yml"), inst.ClusterConfigPath)
+	assert.Equal(t, renderOpts, inst.ClusterRender)
+
+	// Nothing is stored yet.
+	assert.Nil(t, collect(nil).ClusterRender)
+
+	// The stored options are used by other commands.
+	require.NoError(t, SetClusterRenderOpts([]InstanceCtx{inst}, *renderOpts))
+	assert.Equal(t, filepath.Join(tmpDir, "run", "cluster_app"), filepath.Dir(inst.RunDir))
+	assert.FileExists(t, filepath.Join(tmpDir, "run", "cluster_app", clusterRenderFileName))
+	inst = collect(nil)
+	assert.Equal(t, filepath.Join(appLocation, "prod_wal_dir"), inst.WalDir)
+	assert.Equal(t, renderOpts, inst.ClusterRender)
+
+	require.NoError(t, SetClusterRenderOpts([]InstanceCtx{inst}, cluster.RenderOpts{}))
+	assert.Nil(t, collect(nil).ClusterRender)
+}
+
+func TestFillCtx_renderRequiresApp(t *testing.T) {
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigPath = "tt.yaml"
+	runningCtx := RunningCtx{ClusterRender: &cluster.RenderOpts{VarsFile: "vars.yaml"}}
+	err := FillCtx(configure.GetDefaultCliOpts(), &cmdCtx, &runningCtx, nil, ConfigLoadAll)
+	assert.EqualError(t, err, "cluster config render options require an application name")
+}
+
+func TestSetClusterRenderOpts_running(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidFile := filepath.Join(tmpDir, "tt.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0o644))
+
+	err := SetClusterRenderOpts([]InstanceCtx{{
+		AppName:           "app",
+		InstName:          "inst",
+		ClusterConfigPath: "config.yaml",
+		PIDFile:           pidFile,
+		RunDir:            filepath.Join(tmpDir, "app", "inst"),
+	}}, cluster.RenderOpts{VarsFile: "vars.yaml"})
+	assert.EqualError(t, err, "unable to change cluster config render options: "+
+		"the instance app:inst is running")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "app", clusterRenderFileName))
+}
+
 func TestIsAbleToStartInstances(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -487,6 +575,68 @@ func Test_getInstanceName(t *testing.T) {
 	}
 }
 
+func Test_getRestartPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		opts     *config.RestartPolicyOpts
+		expected libwatchdog.RestartPolicy
+	}{
+		{nil, libwatchdog.NewConstantRestartPolicy(defaultRestartDelay)},
+		{
+			&config.RestartPolicyOpts{MaxRestarts: 3},
+			libwatchdog.RestartPolicy{Delay: defaultRestartDelay, MaxRestarts: 3},
+		},
+		{
+			&config.RestartPolicyOpts{Delay: 1, MaxDelay: 60, MaxRestarts: 5, Window: 600},
+			libwatchdog.RestartPolicy{
+				Delay:       time.Second,
+				MaxDelay:    time.Minute,
+				MaxRestarts: 5,
+				Window:      10 * time.Minute,
+			},
+		},
+	} {
+		assert.Equal(t, tc.expected, getRestartPolicy(tc.opts))
+	}
+}
+
+func Test_getResourceLimits(t *testing.T) {
+	limits, root, err := getResourceLimits(nil)
+	require.NoError(t, err)
+	assert.False(t, limits.IsSet())
+	assert.Empty(t, root)
+
+	limits, root, err = getResourceLimits(&config.LimitsOpts{
+		MemoryMax: "512M",
+		CPUQuota:  150,
+		IOWeight:  200,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cgroups.Limits{MemoryMax: 512 << 20, CPUQuota: 150, IOWeight: 200}, limits)
+	// The default root depends on the cgroup of the process.
+	assert.Empty(t, root)
+	inst := InstanceCtx{AppName: "app", InstName: "master"}
+	assert.Equal(t, filepath.Join(cgroups.DefaultRoot(os.Getpid()), "app", "master"),
+		GetInstanceCgroup(inst, os.Getpid()))
+	inst.CgroupRoot = "/sys/fs/cgroup/tarantool"
+	assert.Equal(t, "/sys/fs/cgroup/tarantool/app/master", GetInstanceCgroup(inst, os.Getpid()))
+
+	_, root, err = getResourceLimits(&config.LimitsOpts{
+		CPUQuota:   50,
+		CgroupRoot: "/sys/fs/cgroup/tarantool/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/sys/fs/cgroup/tarantool", root)
+
+	for _, opts := range []config.LimitsOpts{
+		{MemoryMax: "lots"},
+		{IOWeight: 20000},
+		{CPUQuota: 100, CgroupRoot: "tt"},
+	} {
+		_, _, err = getResourceLimits(&opts)
+		assert.Error(t, err)
+	}
+}
+
 func TestGetAppPath(t *testing.T) {
 	assert.Equal(t, "/path/to/app/init.lua", GetAppPath(InstanceCtx{
 		InstanceScript: "/path/to/app/init.lua",
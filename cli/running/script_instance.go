@@ -170,7 +170,7 @@ func (inst *scriptInstance) Start(ctx context.Context) error {
 	inst.setTarantoolLog(cmd)
 
 	// Start an Instance.
-	if inst.processController, err = newProcessController(cmd); err != nil {
+	if err = inst.startProcess(cmd); err != nil {
 		return err
 	}
 	StdinPipe.Write([]byte(instanceLauncher))
@@ -2,6 +2,7 @@ package running
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/tarantool/tt/cli/ttlog"
 	"github.com/tarantool/tt/lib/integrity"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
 )
 
 // Provider interface provides Watchdog methods to get objects whose creation
@@ -23,6 +25,10 @@ type Provider interface {
 	UpdateLogger(logger ttlog.Logger) (ttlog.Logger, error)
 	// IsRestartable checks
 	IsRestartable() (bool, error)
+	// CaptureCoredump packs a core dump of the completed instance process if
+	// it has crashed by a signal. It returns a path to the archive or an empty
+	// path if there is nothing to capture.
+	CaptureCoredump(state *os.ProcessState) (string, error)
 }
 
 // Watchdog is a process that controls an Instance process.
@@ -34,9 +40,18 @@ type Watchdog struct {
 	// doneBarrier used to indicate the completion of the
 	// signal handling goroutine.
 	doneBarrier sync.WaitGroup
-	// restartTimeout describes the timeout between
-	// restarting the Instance.
-	restartTimeout time.Duration
+	// backoff describes timeouts between restarts of the Instance
+	// according to the restart policy.
+	backoff *libwatchdog.Backoff
+	// stateFile is a file to persist the restarts state in. The state is not
+	// persisted if the path is empty.
+	stateFile string
+	// state is the current restarts state.
+	state libwatchdog.State
+	// stateMutex protects the state updated by background core dump captures.
+	stateMutex sync.Mutex
+	// captures is used to wait for background core dump captures.
+	captures sync.WaitGroup
 	// provider provides Watchdog methods to get objects whose creation
 	// and updating may depend on changing external parameters
 	// (such as configuration file).
@@ -54,14 +69,15 @@ type Watchdog struct {
 }
 
 // NewWatchdog creates a new instance of Watchdog.
-func NewWatchdog(restartable bool, restartTimeout time.Duration, logger ttlog.Logger,
-	provider Provider, preStartAction func() error,
+func NewWatchdog(restartable bool, restartPolicy libwatchdog.RestartPolicy, stateFile string,
+	logger ttlog.Logger, provider Provider, preStartAction func() error,
 	integrityCtx integrity.IntegrityCtx, integrityCheckPeriod time.Duration,
 ) *Watchdog {
 	wd := Watchdog{
 		instance:             nil,
 		logger:               logger,
-		restartTimeout:       restartTimeout,
+		backoff:              libwatchdog.NewBackoff(restartPolicy),
+		stateFile:            stateFile,
 		provider:             provider,
 		preStartAction:       preStartAction,
 		integrityCtx:         integrityCtx,
@@ -73,6 +89,9 @@ func NewWatchdog(restartable bool, restartTimeout time.Duration, logger ttlog.Lo
 
 // Start starts the Instance and signal handling.
 func (wd *Watchdog) Start() error {
+	// Core dumps are captured in background and must be packed before exit.
+	defer wd.captures.Wait()
+
 	var err error
 	// Create Instance.
 	if wd.instance, err = wd.provider.CreateInstance(wd.logger); err != nil {
@@ -101,6 +120,8 @@ func (wd *Watchdog) Start() error {
 		wd.doneBarrier.Wait()
 		return err
 	}
+	// The restarts are counted since the watchdog start.
+	wd.writeState(wd.logger)
 
 	// The Instance must be restarted on completion if the "restartable"
 	// parameter is set to "true".
@@ -117,9 +138,14 @@ func (wd *Watchdog) Start() error {
 		if err := wd.instance.Start(context.Background()); err != nil {
 			wd.logger.Printf(`(ERROR):  instance start failed: %v.`, err)
 			wd.stopMutex.Unlock()
+			wd.updateState(wd.logger, func(state *libwatchdog.State) {
+				state.LastExitReason = fmt.Sprintf("start failed: %v", err)
+				state.LastExitTime = time.Now()
+			})
 			break
 		}
 		wd.stopMutex.Unlock()
+		startTime := time.Now()
 
 		// Wait while the Instance will be terminated.
 		if err := wd.instance.Wait(); err != nil {
@@ -131,6 +157,17 @@ func (wd *Watchdog) Start() error {
 		// Wait for the signal processing goroutine to complete.
 		wd.doneBarrier.Wait()
 
+		exitTime := time.Now()
+		if !wd.shouldStop {
+			wd.captureCoredump()
+			// The exit is recorded even if the Instance is not restarted,
+			// so waiting for the readiness could stop.
+			wd.updateState(wd.logger, func(state *libwatchdog.State) {
+				state.LastExitReason = libwatchdog.ExitReason(wd.instance.ProcessState())
+				state.LastExitTime = exitTime
+			})
+		}
+
 		// Stop the process if the Instance is not restartable.
 		restartable, err := wd.provider.IsRestartable()
 		if err != nil {
@@ -148,8 +185,22 @@ func (wd *Watchdog) Start() error {
 		} else {
 			wd.logger = logger
 		}
-		wd.logger.Printf(`(INFO): waiting for restart timeout %s.`, wd.restartTimeout)
-		time.Sleep(wd.restartTimeout)
+
+		restartTimeout, err := wd.backoff.Next(exitTime, time.Since(startTime))
+		if err != nil {
+			wd.updateState(wd.logger, func(state *libwatchdog.State) {
+				wd.logger.Printf(`(ERROR): the Instance is not restarted after %d restarts: %v.`,
+					state.Restarts, err)
+				state.Failed = true
+			})
+			break
+		}
+		wd.updateState(wd.logger, func(state *libwatchdog.State) {
+			state.Restarts++
+		})
+
+		wd.logger.Printf(`(INFO): waiting for restart timeout %s.`, restartTimeout)
+		time.Sleep(restartTimeout)
 
 		wd.shouldStop = false
 
@@ -170,6 +221,57 @@ func (wd *Watchdog) Start() error {
 	return nil
 }
 
+// captureCoredump packs a core dump of the crashed Instance in background,
+// so a slow capture does not delay the restart. The result is recorded in the
+// state.
+func (wd *Watchdog) captureCoredump() {
+	state := wd.instance.ProcessState()
+	if state == nil {
+		return
+	}
+	logger := wd.logger
+	wd.captures.Add(1)
+	go func() {
+		defer wd.captures.Done()
+		archive, err := wd.provider.CaptureCoredump(state)
+		if err != nil {
+			logger.Printf(`(WARN): failed to capture the core dump: %v.`, err)
+			wd.updateState(logger, func(state *libwatchdog.State) {
+				state.LastCoredump = ""
+				state.LastCoredumpError = err.Error()
+			})
+			return
+		}
+		if archive == "" {
+			return
+		}
+		logger.Printf(`(INFO): the core dump is packed into %s.`, archive)
+		wd.updateState(logger, func(state *libwatchdog.State) {
+			state.LastCoredump = archive
+			state.LastCoredumpError = ""
+		})
+	}()
+}
+
+// updateState updates and persists the restarts state. The logger is passed
+// explicitly since the state is updated by background captures too.
+func (wd *Watchdog) updateState(logger ttlog.Logger, update func(state *libwatchdog.State)) {
+	wd.stateMutex.Lock()
+	defer wd.stateMutex.Unlock()
+	update(&wd.state)
+	wd.writeState(logger)
+}
+
+// writeState persists the restarts state if the state file is set.
+func (wd *Watchdog) writeState(logger ttlog.Logger) {
+	if wd.stateFile == "" {
+		return
+	}
+	if err := libwatchdog.WriteState(wd.stateFile, wd.state); err != nil {
+		logger.Printf(`(WARN): can't write the watchdog state: %v.`, err)
+	}
+}
+
 // startIntegrityChecks launches goroutine that performs periodic integrity checks.
 func (wd *Watchdog) startIntegrityChecks(ctx context.Context) {
 	ticker := time.NewTicker(wd.integrityCheckPeriod)
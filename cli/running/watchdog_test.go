@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tarantool/tt/cli/ttlog"
 	"github.com/tarantool/tt/lib/integrity"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
 )
 
 const (
@@ -56,6 +57,11 @@ func (provider *providerTestImpl) IsRestartable() (bool, error) {
 	return provider.restartable, nil
 }
 
+// CaptureCoredump does not capture core dumps in tests.
+func (provider *providerTestImpl) CaptureCoredump(state *os.ProcessState) (string, error) {
+	return "", nil
+}
+
 // createTestWatchdog creates an instance and a watchdog for the test.
 func createTestWatchdog(t *testing.T, restartable bool) *Watchdog {
 	assert := assert.New(t)
@@ -76,7 +82,8 @@ func createTestWatchdog(t *testing.T, restartable bool) *Watchdog {
 		dataDir: dataDir, restartable: restartable, t: t,
 	}
 	testPreAction := func() error { return nil }
-	wd := NewWatchdog(restartable, wdTestRestartTimeout, logger, &provider, testPreAction,
+	wd := NewWatchdog(restartable, libwatchdog.NewConstantRestartPolicy(wdTestRestartTimeout), "",
+		logger, &provider, testPreAction,
 		integrity.IntegrityCtx{
 			Repository: &mockRepository{},
 		}, 0)
@@ -14,7 +14,7 @@ func NewJSONPrinter() *JSONPrinter {
 }
 
 // Print outputs the instance status map in JSON format.
-func (j JSONPrinter) Print(instances map[string]*instanceStatus) error {
+func (j JSONPrinter) Print(instances map[string]*InstanceStatus) error {
 	jsonData, err := json.MarshalIndent(instances, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal instances to JSON: %w", err)
@@ -2,18 +2,23 @@ package status
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"strings"
 
+	"github.com/apex/log"
 	"github.com/mitchellh/mapstructure"
+	"github.com/tarantool/tt/cli/cgroups"
 	"github.com/tarantool/tt/cli/connector"
 	"github.com/tarantool/tt/cli/process_utils"
 	"github.com/tarantool/tt/cli/running"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
 )
 
 // InstanceStatusPrinter interface defines methods to output instance status information.
 type InstanceStatusPrinter interface {
-	Print(instances map[string]*instanceStatus) error
+	Print(instances map[string]*InstanceStatus) error
 }
 
 //go:embed lua/instance_state.lua
@@ -62,48 +67,60 @@ type rawInstanceState struct {
 	UUID            string               `mapstructure:"uuid"`
 }
 
-type severity string
+// Severity is a severity of an instance alert.
+type Severity string
 
 const (
-	severityError   severity = "error"
-	severityWarning severity = "warning"
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
 )
 
-type instanceAlert struct {
+// InstanceAlert is an alert about an instance problem.
+type InstanceAlert struct {
 	Message  string   `json:"message"`
-	Severity severity `json:"severity"`
+	Severity Severity `json:"severity"`
 }
 
-type instanceStatus struct {
+// WatchdogStatus is a state of the instance watchdog.
+type WatchdogStatus struct {
+	Restarts       int    `json:"restarts"`
+	LastExitReason string `json:"last_exit_reason,omitempty" yaml:"last_exit_reason,omitempty"`
+	LastCoredump   string `json:"last_coredump,omitempty" yaml:"last_coredump,omitempty"`
+}
+
+// InstanceStatus is a collected status of an instance.
+type InstanceStatus struct {
 	Status             string                     `json:"status"`
 	PID                *int                       `json:"pid"`
 	Mode               string                     `json:"mode"`
 	Config             string                     `json:"config"`
 	Box                string                     `json:"box"`
 	Upstream           string                     `json:"upstream"`
-	Alerts             []instanceAlert            `json:"alerts"`
+	Alerts             []InstanceAlert            `json:"alerts"`
+	Watchdog           *WatchdogStatus            `json:"watchdog,omitempty" yaml:",omitempty"`
+	Resources          *cgroups.Usage             `json:"resources,omitempty" yaml:",omitempty"`
 	rawReplicationInfo []rawReplicationInfo       `json:"-" yaml:"-"`
 	procStatus         process_utils.ProcessState `json:"-" yaml:"-"`
 }
 
-func (is *instanceStatus) addAlert(message string, severity severity) {
-	is.Alerts = append(is.Alerts, instanceAlert{
+func (is *InstanceStatus) addAlert(message string, severity Severity) {
+	is.Alerts = append(is.Alerts, InstanceAlert{
 		Message:  message,
 		Severity: severity,
 	})
 }
 
-func newInstanceStatus() instanceStatus {
-	return instanceStatus{
+func newInstanceStatus() InstanceStatus {
+	return InstanceStatus{
 		Config:   defaultModuleStatus,
 		Box:      defaultModuleStatus,
 		Upstream: defaultModuleStatus,
 	}
 }
 
-type instanceStatusMap = map[string]*instanceStatus
+type instanceStatusMap = map[string]*InstanceStatus
 
-func processReplicationInfo(instStatus *instanceStatus, uuid2name map[string]string) {
+func processReplicationInfo(instStatus *InstanceStatus, uuid2name map[string]string) {
 	for _, repl := range instStatus.rawReplicationInfo {
 		fullInstanceUpstreamName, ok := uuid2name[repl.UUID]
 		// Use repl.Name if available, otherwise fallback to repl.UUID
@@ -130,26 +147,50 @@ func processReplicationInfo(instStatus *instanceStatus, uuid2name map[string]str
 		instStatus.addAlert(fmt.Sprintf(
 			"[upstream][warning]: replication from %s is in %q status: %q",
 			upstreamInstanceDesc, repl.Upstream.Status,
-			repl.Upstream.Message), severityWarning)
+			repl.Upstream.Message), SeverityWarning)
 	}
 }
 
-func processConfigInfo(instStatus *instanceStatus, instanceState rawInstanceState) {
+func processConfigInfo(instStatus *InstanceStatus, instanceState rawInstanceState) {
 	if len(instanceState.ConfigInfo.Alerts) == 0 {
 		return
 	}
 	for _, alert := range instanceState.ConfigInfo.Alerts {
-		severity := severityWarning
+		severity := SeverityWarning
 		if alert.Type == "error" {
-			severity = severityError
+			severity = SeverityError
 		}
 		instStatus.addAlert(fmt.Sprintf("[config][%s]: %s", alert.Type, alert.Message), severity)
 	}
 }
 
+// processWatchdogState adds restarts information from the watchdog state.
+func processWatchdogState(instStatus *InstanceStatus, state libwatchdog.State) {
+	instStatus.Watchdog = &WatchdogStatus{
+		Restarts:       state.Restarts,
+		LastExitReason: state.LastExitReason,
+		LastCoredump:   state.LastCoredump,
+	}
+	if state.LastCoredump != "" {
+		instStatus.addAlert(fmt.Sprintf(
+			"[watchdog][warning]: the core dump of the last crash is packed into %s",
+			state.LastCoredump), SeverityWarning)
+	}
+	if state.LastCoredumpError != "" {
+		instStatus.addAlert(fmt.Sprintf(
+			"[watchdog][warning]: the core dump of the last crash is not captured: %s",
+			state.LastCoredumpError), SeverityWarning)
+	}
+	if state.Failed && instStatus.procStatus.Code != process_utils.ProcessRunningCode {
+		instStatus.addAlert(fmt.Sprintf(
+			"[watchdog][error]: the instance has failed after %d restarts, last exit: %s",
+			state.Restarts, state.LastExitReason), SeverityError)
+	}
+}
+
 // collectInstanceState connects to an instance and collects its state.
 func collectInstanceState(run running.InstanceCtx, fullInstanceName string,
-	instStatus *instanceStatus,
+	instStatus *InstanceStatus,
 ) (rawInstanceState, error) {
 	var instanceState rawInstanceState
 
@@ -161,7 +202,7 @@ func collectInstanceState(run running.InstanceCtx, fullInstanceName string,
 		if instStatus.procStatus.Code == process_utils.ProcessRunningCode {
 			instStatus.addAlert(fmt.Sprintf(
 				"Error while connecting to instance %s via socket %s: %v",
-				fullInstanceName, run.ConsoleSocket, err), severityError)
+				fullInstanceName, run.ConsoleSocket, err), SeverityError)
 		}
 		return instanceState, fmt.Errorf("failed to connect to instance %s: %w",
 			fullInstanceName, err)
@@ -172,7 +213,7 @@ func collectInstanceState(run running.InstanceCtx, fullInstanceName string,
 	if err != nil {
 		instStatus.addAlert(fmt.Sprintf(
 			"Error while executing Lua script on instance %s: %v",
-			fullInstanceName, err), severityError)
+			fullInstanceName, err), SeverityError)
 		return instanceState, fmt.Errorf("failed to execute Lua script on instance %s: %w",
 			fullInstanceName, err)
 	}
@@ -180,14 +221,14 @@ func collectInstanceState(run running.InstanceCtx, fullInstanceName string,
 	if len(res) == 0 {
 		instStatus.addAlert(fmt.Sprintf(
 			"No data returned from Lua script on instance %s",
-			fullInstanceName), severityError)
+			fullInstanceName), SeverityError)
 		return instanceState, fmt.Errorf("no data returned from Lua script")
 	}
 
 	err = mapstructure.Decode(res[0], &instanceState)
 	if err != nil {
 		instStatus.addAlert(fmt.Sprintf("Error while decoding data from "+
-			"instance %s: %v", fullInstanceName, err), severityError)
+			"instance %s: %v", fullInstanceName, err), SeverityError)
 		return instanceState, fmt.Errorf("failed to decode data from instance %s: %w",
 			fullInstanceName, err)
 	}
@@ -197,6 +238,11 @@ func collectInstanceState(run running.InstanceCtx, fullInstanceName string,
 
 // Status writes the status as a table.
 func Status(runningCtx running.RunningCtx, printer InstanceStatusPrinter) error {
+	return printer.Print(Collect(runningCtx))
+}
+
+// Collect collects statuses of the instances.
+func Collect(runningCtx running.RunningCtx) map[string]*InstanceStatus {
 	instances := make(instanceStatusMap)
 	uuid2name := map[string]string{}
 	for _, run := range runningCtx.Instances {
@@ -210,6 +256,26 @@ func Status(runningCtx running.RunningCtx, printer InstanceStatusPrinter) error
 			instStatus.PID = &instStatus.procStatus.PID
 		}
 
+		if run.WatchdogStateFile != "" {
+			if state, err := libwatchdog.ReadState(run.WatchdogStateFile); err != nil {
+				instStatus.addAlert(err.Error(), SeverityWarning)
+			} else {
+				processWatchdogState(&instStatus, state)
+			}
+		}
+
+		if run.Cgroup != "" && instStatus.procStatus.Code == process_utils.ProcessRunningCode {
+			cgroup := running.GetInstanceCgroup(run, instStatus.procStatus.PID)
+			// There is no cgroup if the instance is started by a systemd unit.
+			if usage, err := cgroups.ReadUsage(cgroup); errors.Is(err, fs.ErrNotExist) {
+				log.Debugf("No cgroup of %s: %s", fullInstanceName, err)
+			} else if err != nil {
+				instStatus.addAlert(fmt.Sprintf("[cgroup][warning]: %s", err), SeverityWarning)
+			} else {
+				instStatus.Resources = &usage
+			}
+		}
+
 		instanceState, err := collectInstanceState(run, fullInstanceName, &instStatus)
 		if err != nil {
 			continue
@@ -229,5 +295,5 @@ func Status(runningCtx running.RunningCtx, printer InstanceStatusPrinter) error
 		processReplicationInfo(instStatus, uuid2name)
 	}
 
-	return printer.Print(instances)
+	return instances
 }
@@ -51,19 +51,39 @@ func NewTablePrinter(opts ...TablePrinterOption) *TablePrinter {
 }
 
 // formatAlert formats an alert message based on its severity.
-func formatAlert(alert instanceAlert) string {
+func formatAlert(alert InstanceAlert) string {
 	switch alert.Severity {
-	case severityError:
+	case SeverityError:
 		return printRed(alert.Message)
-	case severityWarning:
+	case SeverityWarning:
 		return printYellow(alert.Message)
 	default:
 		return alert.Message
 	}
 }
 
+// formatRestarts formats the restarts counter with the last exit reason.
+func formatRestarts(instStatus *InstanceStatus) string {
+	if instStatus.Watchdog == nil {
+		return defaultModuleStatus
+	}
+	if instStatus.Watchdog.LastExitReason == "" {
+		return fmt.Sprint(instStatus.Watchdog.Restarts)
+	}
+	return fmt.Sprintf("%d (%s)", instStatus.Watchdog.Restarts,
+		instStatus.Watchdog.LastExitReason)
+}
+
+// printInstanceResources prints a resource usage of a specific instance.
+func (t TablePrinter) printInstanceResources(instanceName string, instStatus *InstanceStatus) {
+	if instStatus.Resources == nil {
+		return
+	}
+	fmt.Printf("Resources of %s: %s\n\n", instanceName, instStatus.Resources)
+}
+
 // printInstanceAlerts prints alerts for a specific instance.
-func (t TablePrinter) printInstanceAlerts(instanceName string, instStatus *instanceStatus) {
+func (t TablePrinter) printInstanceAlerts(instanceName string, instStatus *InstanceStatus) {
 	if len(instStatus.Alerts) == 0 {
 		return
 	}
@@ -75,7 +95,7 @@ func (t TablePrinter) printInstanceAlerts(instanceName string, instStatus *insta
 }
 
 // hasAlerts checks if any instance has alerts.
-func hasAlerts(instances map[string]*instanceStatus) bool {
+func hasAlerts(instances map[string]*InstanceStatus) bool {
 	for _, instStatus := range instances {
 		if len(instStatus.Alerts) > 0 {
 			return true
@@ -85,17 +105,20 @@ func hasAlerts(instances map[string]*instanceStatus) bool {
 }
 
 // Print outputs the instance status map in table format.
-func (t TablePrinter) Print(instances map[string]*instanceStatus) error {
+func (t TablePrinter) Print(instances map[string]*InstanceStatus) error {
 	ts := table.NewWriter()
 	ts.SetOutputMirror(os.Stdout)
 	ts.AppendHeader(
-		table.Row{"INSTANCE", "STATUS", "PID", "MODE", "CONFIG", "BOX", "UPSTREAM"})
+		table.Row{"INSTANCE", "STATUS", "PID", "MODE", "CONFIG", "BOX", "UPSTREAM", "RESTARTS"})
 
 	for instName, instData := range instances {
 		row := []any{}
 		row = append(row, instName)
 		row = append(row, instData.procStatus.FormattedStatus())
 		if instData.PID == nil {
+			if instData.Watchdog != nil && instData.Watchdog.LastExitReason != "" {
+				row = append(row, "", "", "", "", "", formatRestarts(instData))
+			}
 			ts.AppendRow(row)
 			continue
 		}
@@ -104,12 +127,14 @@ func (t TablePrinter) Print(instances map[string]*instanceStatus) error {
 		row = append(row, instData.Config)
 		row = append(row, instData.Box)
 		row = append(row, instData.Upstream)
+		row = append(row, formatRestarts(instData))
 		ts.AppendRow(row)
 	}
 	ts.SortBy([]table.SortBy{{Name: "INSTANCE", Mode: table.Asc}})
 
 	if t.details {
 		for instanceName, instStatus := range instances {
+			t.printInstanceResources(instanceName, instStatus)
 			t.printInstanceAlerts(instanceName, instStatus)
 		}
 	}
@@ -0,0 +1,160 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/tail"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
+)
+
+// waitCheckPeriod is a period of instance readiness checks.
+var waitCheckPeriod = 500 * time.Millisecond
+
+// readyConfigStatuses are statuses of Tarantool 3 configuration of a ready
+// instance. The status is checked only for instances started with a cluster
+// configuration.
+var readyConfigStatuses = map[string]bool{
+	"ready":          true,
+	"check_warnings": true,
+}
+
+// checkInstanceReady collects the instance state and returns an error
+// if the instance is not ready.
+func checkInstanceReady(run running.InstanceCtx, fullInstanceName string,
+	instStatus *InstanceStatus,
+) error {
+	instanceState, err := collectInstanceState(run, fullInstanceName, instStatus)
+	if err != nil {
+		return err
+	}
+	processConfigInfo(instStatus, instanceState)
+	return checkInstanceState(run, instanceState)
+}
+
+// checkInstanceState returns an error if the collected state is not a state
+// of a ready instance.
+func checkInstanceState(run running.InstanceCtx, instanceState rawInstanceState) error {
+	if instanceState.BoxStatus != "running" {
+		return fmt.Errorf("box status is %q", instanceState.BoxStatus)
+	}
+	if run.ClusterConfigPath != "" && !readyConfigStatuses[instanceState.ConfigInfo.Status] {
+		return fmt.Errorf("config status is %q", instanceState.ConfigInfo.Status)
+	}
+	return nil
+}
+
+// formatNotReadyError creates an error for the instance that is not ready.
+// The error contains collected alerts.
+func formatNotReadyError(fullInstanceName string, err error,
+	instStatus *InstanceStatus,
+) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "instance %s is not ready: %s", fullInstanceName, err)
+	for _, alert := range instStatus.Alerts {
+		fmt.Fprintf(&builder, "\n  • %s", alert.Message)
+	}
+	return errors.New(builder.String())
+}
+
+// waitLogLines is a number of the instance log lines reported if the instance
+// has exited.
+const waitLogLines = 10
+
+// checkInstanceExited returns an error if the instance process has exited
+// since the moment and it will not be restarted: the watchdog recorded the
+// exit and it is not running anymore.
+func checkInstanceExited(run running.InstanceCtx, instStatus *InstanceStatus,
+	since time.Time,
+) error {
+	if instStatus.procStatus.Code == process_utils.ProcessRunningCode ||
+		run.WatchdogStateFile == "" {
+		return nil
+	}
+	state, err := libwatchdog.ReadState(run.WatchdogStateFile)
+	if err != nil || state.LastExitTime.Before(since) {
+		// The watchdog could be not started yet.
+		return nil
+	}
+	processWatchdogState(instStatus, state)
+	return fmt.Errorf("the instance has exited: %s", state.LastExitReason)
+}
+
+// readLogTail returns the last lines of the instance log.
+func readLogTail(run running.InstanceCtx, lines int) []string {
+	if run.Log == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	logLines, err := tail.TailN(ctx, func(str string) string { return str }, run.Log, lines)
+	if err != nil {
+		return nil
+	}
+	var result []string
+	for line := range logLines {
+		result = append(result, line)
+	}
+	return result
+}
+
+// formatExitedError creates an error for the exited instance. The error
+// contains collected alerts and the last lines of the instance log.
+func formatExitedError(fullInstanceName string, err error, instStatus *InstanceStatus,
+	logLines []string,
+) error {
+	notReadyErr := formatNotReadyError(fullInstanceName, err, instStatus)
+	if len(logLines) == 0 {
+		return notReadyErr
+	}
+	var builder strings.Builder
+	builder.WriteString(notReadyErr.Error())
+	builder.WriteString("\n  last log lines:")
+	for _, line := range logLines {
+		fmt.Fprintf(&builder, "\n    %s", line)
+	}
+	return errors.New(builder.String())
+}
+
+// WaitReady waits until the instances are running and their configuration is
+// applied. The timeout is shared by all instances. It returns an error with
+// collected alerts for each instance that is not ready in time. An instance
+// that has exited and will not be restarted is reported immediately with
+// the last lines of its log.
+func WaitReady(instances []running.InstanceCtx, timeout time.Duration) error {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	var errs []error
+	for _, run := range instances {
+		fullInstanceName := running.GetAppInstanceName(run)
+		for {
+			instStatus := newInstanceStatus()
+			instStatus.procStatus = running.Status(&run)
+			err := checkInstanceReady(run, fullInstanceName, &instStatus)
+			if err == nil {
+				for _, alert := range instStatus.Alerts {
+					log.Warnf("%s: %s", fullInstanceName, alert.Message)
+				}
+				log.Infof("The instance %s is ready.", fullInstanceName)
+				break
+			}
+			if exitErr := checkInstanceExited(run, &instStatus, start); exitErr != nil {
+				errs = append(errs, formatExitedError(fullInstanceName, exitErr, &instStatus,
+					readLogTail(run, waitLogLines)))
+				break
+			}
+			if time.Now().After(deadline) {
+				errs = append(errs, formatNotReadyError(fullInstanceName, err, &instStatus))
+				break
+			}
+			time.Sleep(waitCheckPeriod)
+		}
+	}
+	return errors.Join(errs...)
+}
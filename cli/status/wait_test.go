@@ -0,0 +1,147 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
+)
+
+func TestFormatNotReadyError(t *testing.T) {
+	instStatus := newInstanceStatus()
+	instStatus.addAlert("[config][error]: boom", SeverityError)
+	instStatus.addAlert("[config][warning]: hmm", SeverityWarning)
+
+	err := formatNotReadyError("app:inst", errors.New(`config status is "check_errors"`),
+		&instStatus)
+	assert.EqualError(t, err, "instance app:inst is not ready: "+
+		"config status is \"check_errors\"\n"+
+		"  • [config][error]: boom\n"+
+		"  • [config][warning]: hmm")
+}
+
+func TestCheckInstanceState(t *testing.T) {
+	clusterInstance := running.InstanceCtx{ClusterConfigPath: "config.yaml"}
+	cases := []struct {
+		name     string
+		run      running.InstanceCtx
+		box      string
+		config   string
+		expected string
+	}{
+		{"ready", clusterInstance, "running", "ready", ""},
+		{"check_warnings", clusterInstance, "running", "check_warnings", ""},
+		{"uninitialized", clusterInstance, "running", "uninitialized",
+			`config status is "uninitialized"`},
+		{"check_errors", clusterInstance, "running", "check_errors",
+			`config status is "check_errors"`},
+		{"loading", clusterInstance, "loading", "ready", `box status is "loading"`},
+		{"no_cluster_config", running.InstanceCtx{}, "running", "uninitialized", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkInstanceState(tc.run, rawInstanceState{
+				BoxStatus:  tc.box,
+				ConfigInfo: configInfo{Status: tc.config},
+			})
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWaitReady_timeout(t *testing.T) {
+	waitCheckPeriod = 10 * time.Millisecond
+	t.Cleanup(func() { waitCheckPeriod = 500 * time.Millisecond })
+
+	dir := t.TempDir()
+	instances := []running.InstanceCtx{
+		{
+			AppName:       "app",
+			InstName:      "inst1",
+			PIDFile:       filepath.Join(dir, "inst1.pid"),
+			ConsoleSocket: filepath.Join(dir, "inst1.control"),
+		},
+		{
+			AppName:       "app",
+			InstName:      "inst2",
+			PIDFile:       filepath.Join(dir, "inst2.pid"),
+			ConsoleSocket: filepath.Join(dir, "inst2.control"),
+		},
+	}
+
+	err := WaitReady(instances, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "instance app:inst1 is not ready: "+
+		"failed to connect to instance app:inst1")
+	assert.Contains(t, err.Error(), "instance app:inst2 is not ready: "+
+		"failed to connect to instance app:inst2")
+}
+
+func TestWaitReady_exited(t *testing.T) {
+	waitCheckPeriod = 10 * time.Millisecond
+	t.Cleanup(func() { waitCheckPeriod = 500 * time.Millisecond })
+
+	dir := t.TempDir()
+	run := running.InstanceCtx{
+		AppName:           "app",
+		InstName:          "inst1",
+		PIDFile:           filepath.Join(dir, "inst1.pid"),
+		ConsoleSocket:     filepath.Join(dir, "inst1.control"),
+		WatchdogStateFile: filepath.Join(dir, "inst1.state"),
+		Log:               filepath.Join(dir, "inst1.log"),
+	}
+	require.NoError(t, os.WriteFile(run.Log,
+		[]byte("starting\nfailed to load the config: boom\n"), 0o644))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = libwatchdog.WriteState(run.WatchdogStateFile, libwatchdog.State{
+			LastExitReason: "exit status 1",
+			LastExitTime:   time.Now(),
+		})
+	}()
+
+	start := time.Now()
+	err := WaitReady([]running.InstanceCtx{run}, time.Minute)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, "instance app:inst1 is not ready: the instance has exited: exit status 1\n"+
+		"  last log lines:\n"+
+		"    starting\n"+
+		"    failed to load the config: boom", err.Error())
+}
+
+func TestWaitReady_exitedBeforeWait(t *testing.T) {
+	waitCheckPeriod = 10 * time.Millisecond
+	t.Cleanup(func() { waitCheckPeriod = 500 * time.Millisecond })
+
+	// The exit of a previous run must not be reported.
+	dir := t.TempDir()
+	run := running.InstanceCtx{
+		AppName:           "app",
+		InstName:          "inst1",
+		PIDFile:           filepath.Join(dir, "inst1.pid"),
+		ConsoleSocket:     filepath.Join(dir, "inst1.control"),
+		WatchdogStateFile: filepath.Join(dir, "inst1.state"),
+	}
+	require.NoError(t, libwatchdog.WriteState(run.WatchdogStateFile, libwatchdog.State{
+		LastExitReason: "exit status 1",
+		LastExitTime:   time.Now().Add(-time.Hour),
+	}))
+
+	err := WaitReady([]running.InstanceCtx{run}, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "instance app:inst1 is not ready: "+
+		"failed to connect to instance app:inst1")
+}
@@ -15,7 +15,7 @@ func NewYAMLPrinter() *YAMLPrinter {
 }
 
 // Print outputs the instance status map in YAML format.
-func (y YAMLPrinter) Print(instances map[string]*instanceStatus) error {
+func (y YAMLPrinter) Print(instances map[string]*InstanceStatus) error {
 	yamlData, err := yaml.Marshal(instances)
 	if err != nil {
 		return fmt.Errorf("failed to marshal instances to YAML: %w", err)
@@ -0,0 +1,285 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// systemUnitDir is a directory of units of the system service manager.
+	systemUnitDir = "/etc/systemd/system"
+	// userManagerTarget is a target of units of the user service manager.
+	userManagerTarget = "default.target"
+)
+
+// SystemdCtx contains information for generating systemd units of
+// tt-managed instances.
+type SystemdCtx struct {
+	// Instances are instances to generate units for.
+	Instances []running.InstanceCtx
+	// TT is a path to the tt executable.
+	TT string
+	// ConfigPath is a path to the tt environment.
+	ConfigPath string
+	// UserManager is true to generate units for the user service manager.
+	UserManager bool
+	// User is a user to run instances by the system service manager.
+	User string
+	// Group is a group to run instances by the system service manager.
+	Group string
+	// UnitDir is a directory to install units to. A default directory of
+	// the service manager is used if it is empty.
+	UnitDir string
+}
+
+// systemctl runs systemctl with the arguments.
+var systemctl = func(userManager bool, args ...string) error {
+	if userManager {
+		args = append([]string{"--user"}, args...)
+	}
+	out, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s failed: %w: %s", strings.Join(args, " "), err,
+			strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// unit describes a generated unit of an application.
+type unit struct {
+	// fileName is a name of the unit file.
+	fileName string
+	// content is the unit content.
+	content string
+	// instances are names of instance units of the unit.
+	instances []string
+}
+
+// instancePIDFile returns a path to the PID file of the template unit instance.
+func instancePIDFile(inst running.InstanceCtx) string {
+	if inst.SingleApp {
+		return inst.PIDFile
+	}
+	// The PID file is in the instance directory of the run directory.
+	instDir := filepath.Dir(inst.PIDFile)
+	if filepath.Base(instDir) != inst.InstName {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(instDir), "%i", filepath.Base(inst.PIDFile))
+}
+
+// seconds returns a number of seconds rounded up.
+func seconds(duration float64) int {
+	return int(math.Ceil(duration))
+}
+
+// getUnitParams returns unit parameters of the instance application.
+func getUnitParams(systemdCtx *SystemdCtx, inst running.InstanceCtx) (UnitParams, error) {
+	unitParams := UnitParams{
+		TT:          systemdCtx.TT,
+		ConfigPath:  systemdCtx.ConfigPath,
+		FdLimit:     DefaultFdLimit,
+		PIDFile:     instancePIDFile(inst),
+		User:        systemdCtx.User,
+		Group:       systemdCtx.Group,
+		UserManager: systemdCtx.UserManager,
+	}
+	if systemdCtx.UserManager {
+		unitParams.WantedBy = userManagerTarget
+	}
+
+	unitParams.MemoryMax = inst.ResourceLimits.MemoryMax
+	unitParams.CPUQuota = inst.ResourceLimits.CPUQuota
+	unitParams.IOWeight = inst.ResourceLimits.IOWeight
+
+	// Only one layer owns restarts of a crashed instance. If the watchdog
+	// restarts instances (restart_on_failure), systemd does not restart the
+	// unit: the watchdog gives up on purpose after the restart policy limits.
+	// Otherwise systemd restarts the unit according to the restart policy.
+	policy := inst.RestartPolicy
+	if inst.Restartable {
+		unitParams.Restart = "no"
+	} else {
+		if policy.Delay > 0 {
+			unitParams.RestartSec = seconds(policy.Delay.Seconds())
+		}
+		if policy.MaxRestarts > 0 {
+			unitParams.StartLimitBurst = policy.MaxRestarts
+			unitParams.StartLimitInterval = "infinity"
+			if policy.Window > 0 {
+				unitParams.StartLimitInterval = fmt.Sprintf("%ds",
+					seconds(policy.Window.Seconds()))
+			}
+		}
+	}
+
+	unitParamsFile := util.JoinPaths(inst.AppDir, UnitParamsFileName)
+	if util.IsRegularFile(unitParamsFile) {
+		log.Debugf("Using systemd unit params file %q for %s application",
+			unitParamsFile, inst.AppName)
+		if err := LoadUnitParams(unitParamsFile, &unitParams); err != nil {
+			return unitParams, fmt.Errorf("cannot load custom systemd unit parameters: %s", err)
+		}
+	}
+
+	unitParams.AppName = DescriptionAppName(inst)
+	unitParams.ExecArgs = ExecArgsForApp(inst)
+	return unitParams, nil
+}
+
+// generateUnits generates a unit for each application of the instances.
+func generateUnits(systemdCtx *SystemdCtx) ([]unit, error) {
+	var units []unit
+	for _, inst := range systemdCtx.Instances {
+		fileName := UnitFileName(inst)
+		idx := slices.IndexFunc(units, func(u unit) bool { return u.fileName == fileName })
+		if idx != -1 {
+			units[idx].instances = append(units[idx].instances, UnitName(inst))
+			continue
+		}
+
+		unitParams, err := getUnitParams(systemdCtx, inst)
+		if err != nil {
+			return nil, err
+		}
+		content, err := RenderUnit(unitParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate systemd unit for %q application: %s",
+				inst.AppName, err)
+		}
+		units = append(units, unit{
+			fileName:  fileName,
+			content:   content,
+			instances: []string{UnitName(inst)},
+		})
+	}
+	if len(units) == 0 {
+		return nil, fmt.Errorf("there are no instances to generate systemd units for")
+	}
+	return units, nil
+}
+
+// unitDir returns a directory to install units to.
+func unitDir(systemdCtx *SystemdCtx) (string, error) {
+	if systemdCtx.UnitDir != "" {
+		return systemdCtx.UnitDir, nil
+	}
+	if !systemdCtx.UserManager {
+		return systemUnitDir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the user units directory: %w", err)
+	}
+	return filepath.Join(configDir, "systemd", "user"), nil
+}
+
+// Generate writes units of the instances applications. Units are written
+// into the output directory or to the writer if the directory is empty.
+func Generate(systemdCtx *SystemdCtx, outputDir string, w io.Writer) error {
+	units, err := generateUnits(systemdCtx)
+	if err != nil {
+		return err
+	}
+	for i, unit := range units {
+		if outputDir == "" {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "# %s\n%s", unit.fileName, unit.content)
+			continue
+		}
+		if err := writeUnit(filepath.Join(outputDir, unit.fileName), unit.content); err != nil {
+			return err
+		}
+		log.Infof("Generated %s", filepath.Join(outputDir, unit.fileName))
+	}
+	return nil
+}
+
+// writeUnit writes the unit file.
+func writeUnit(path string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write systemd unit file: %s", err)
+	}
+	return nil
+}
+
+// Install writes units of the instances applications into the service manager
+// units directory and enables units of the instances.
+func Install(systemdCtx *SystemdCtx) error {
+	units, err := generateUnits(systemdCtx)
+	if err != nil {
+		return err
+	}
+	dir, err := unitDir(systemdCtx)
+	if err != nil {
+		return err
+	}
+
+	var instances []string
+	for _, unit := range units {
+		if err := writeUnit(filepath.Join(dir, unit.fileName), unit.content); err != nil {
+			return err
+		}
+		log.Infof("Installed %s", filepath.Join(dir, unit.fileName))
+		instances = append(instances, unit.instances...)
+	}
+
+	if err := systemctl(systemdCtx.UserManager, "daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctl(systemdCtx.UserManager,
+		append([]string{"enable"}, instances...)...); err != nil {
+		return err
+	}
+	log.Infof("Enabled %s", strings.Join(instances, ", "))
+	return nil
+}
+
+// Uninstall stops and disables units of the instances and removes units of
+// the instances applications from the service manager units directory.
+func Uninstall(systemdCtx *SystemdCtx) error {
+	units, err := generateUnits(systemdCtx)
+	if err != nil {
+		return err
+	}
+	dir, err := unitDir(systemdCtx)
+	if err != nil {
+		return err
+	}
+
+	var instances []string
+	for _, unit := range units {
+		instances = append(instances, unit.instances...)
+	}
+	if err := systemctl(systemdCtx.UserManager,
+		append([]string{"disable", "--now"}, instances...)...); err != nil {
+		return err
+	}
+	log.Infof("Disabled %s", strings.Join(instances, ", "))
+
+	for _, unit := range units {
+		path := filepath.Join(dir, unit.fileName)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove systemd unit file: %s", err)
+		}
+		log.Infof("Removed %s", path)
+	}
+	return systemctl(systemdCtx.UserManager, "daemon-reload")
+}
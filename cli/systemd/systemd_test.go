@@ -0,0 +1,285 @@
+package systemd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cgroups"
+	"github.com/tarantool/tt/cli/running"
+	libwatchdog "github.com/tarantool/tt/lib/watchdog"
+)
+
+func TestGetUnitParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		systemdCtx SystemdCtx
+		inst       running.InstanceCtx
+		expected   UnitParams
+	}{
+		{
+			name:       "defaults",
+			systemdCtx: SystemdCtx{TT: "/usr/bin/tt", ConfigPath: "/opt/env"},
+			inst: running.InstanceCtx{
+				AppName:  "app",
+				InstName: "master",
+				PIDFile:  "/opt/env/var/run/app/master/tt.pid",
+			},
+			expected: UnitParams{
+				AppName:    "app@%i",
+				ExecArgs:   "app:%i",
+				TT:         "/usr/bin/tt",
+				ConfigPath: "/opt/env",
+				FdLimit:    DefaultFdLimit,
+				PIDFile:    "/opt/env/var/run/app/%i/tt.pid",
+			},
+		},
+		{
+			name: "restart policy",
+			systemdCtx: SystemdCtx{
+				TT: "/usr/bin/tt", ConfigPath: "/opt/env", User: "admin", Group: "admin",
+			},
+			inst: running.InstanceCtx{
+				AppName:   "single",
+				InstName:  "single",
+				SingleApp: true,
+				PIDFile:   "/opt/env/var/run/single/tt.pid",
+				RestartPolicy: libwatchdog.RestartPolicy{
+					Delay:       1500 * time.Millisecond,
+					MaxRestarts: 5,
+					Window:      time.Minute,
+				},
+			},
+			expected: UnitParams{
+				AppName:            "single",
+				ExecArgs:           "single",
+				TT:                 "/usr/bin/tt",
+				ConfigPath:         "/opt/env",
+				FdLimit:            DefaultFdLimit,
+				PIDFile:            "/opt/env/var/run/single/tt.pid",
+				RestartSec:         2,
+				StartLimitBurst:    5,
+				StartLimitInterval: "60s",
+				User:               "admin",
+				Group:              "admin",
+			},
+		},
+		{
+			name: "watchdog restarts",
+			systemdCtx: SystemdCtx{
+				TT: "/usr/bin/tt", ConfigPath: "/opt/env",
+			},
+			inst: running.InstanceCtx{
+				AppName:     "single",
+				InstName:    "single",
+				SingleApp:   true,
+				PIDFile:     "/opt/env/var/run/single/tt.pid",
+				Restartable: true,
+				RestartPolicy: libwatchdog.RestartPolicy{
+					Delay:       1500 * time.Millisecond,
+					MaxRestarts: 5,
+					Window:      time.Minute,
+				},
+			},
+			expected: UnitParams{
+				AppName:    "single",
+				ExecArgs:   "single",
+				TT:         "/usr/bin/tt",
+				ConfigPath: "/opt/env",
+				FdLimit:    DefaultFdLimit,
+				PIDFile:    "/opt/env/var/run/single/tt.pid",
+				Restart:    "no",
+			},
+		},
+		{
+			name: "user manager",
+			systemdCtx: SystemdCtx{
+				TT: "/usr/bin/tt", ConfigPath: "/opt/env", UserManager: true,
+			},
+			inst: running.InstanceCtx{
+				AppName:       "app",
+				InstName:      "master",
+				PIDFile:       "/tmp/custom.pid",
+				RestartPolicy: libwatchdog.RestartPolicy{MaxRestarts: 3},
+			},
+			expected: UnitParams{
+				AppName:            "app@%i",
+				ExecArgs:           "app:%i",
+				TT:                 "/usr/bin/tt",
+				ConfigPath:         "/opt/env",
+				FdLimit:            DefaultFdLimit,
+				StartLimitBurst:    3,
+				StartLimitInterval: "infinity",
+				UserManager:        true,
+				WantedBy:           userManagerTarget,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.inst.AppDir = t.TempDir()
+			params, err := getUnitParams(&tt.systemdCtx, tt.inst)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+func TestGetUnitParams_paramsFile(t *testing.T) {
+	appDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, UnitParamsFileName),
+		[]byte("FdLimit: 1024\nUser: app\nAppName: custom\n"), 0o644))
+
+	params, err := getUnitParams(&SystemdCtx{TT: "tt"}, running.InstanceCtx{
+		AppDir:   appDir,
+		AppName:  "app",
+		InstName: "router",
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1024, params.FdLimit)
+	assert.Equal(t, "app", params.User)
+	// Application name cannot be overridden.
+	assert.Equal(t, "app@%i", params.AppName)
+}
+
+func TestRenderUnit_userManager(t *testing.T) {
+	content, err := RenderUnit(UnitParams{
+		AppName:            "app@%i",
+		ExecArgs:           "app:%i",
+		TT:                 "/usr/bin/tt",
+		ConfigPath:         "/opt/env",
+		FdLimit:            1024,
+		PIDFile:            "/opt/env/var/run/app/%i/tt.pid",
+		RestartSec:         5,
+		StartLimitBurst:    3,
+		StartLimitInterval: "60s",
+		UserManager:        true,
+		WantedBy:           userManagerTarget,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, content, "StartLimitIntervalSec=60s\nStartLimitBurst=3\n")
+	assert.Contains(t, content, "PIDFile=/opt/env/var/run/app/%i/tt.pid\n")
+	assert.Contains(t, content, "ExecStart=/usr/bin/tt -L /opt/env start app:%i\n")
+	assert.Contains(t, content, "Restart=on-failure\nRestartSec=5\n")
+	assert.Contains(t, content, "WantedBy=default.target")
+	assert.NotContains(t, content, "User=")
+	assert.NotContains(t, content, "OOMScoreAdjust")
+}
+
+func TestRenderUnit_watchdogRestarts(t *testing.T) {
+	params, err := getUnitParams(&SystemdCtx{TT: "tt", ConfigPath: "/env"},
+		running.InstanceCtx{
+			AppDir:        t.TempDir(),
+			AppName:       "app",
+			InstName:      "master",
+			Restartable:   true,
+			RestartPolicy: libwatchdog.RestartPolicy{MaxRestarts: 3},
+		})
+	require.NoError(t, err)
+
+	content, err := RenderUnit(params)
+	require.NoError(t, err)
+	assert.Contains(t, content, "Restart=no\n")
+	assert.NotContains(t, content, "StartLimitBurst")
+}
+
+func TestRenderUnit_resourceLimits(t *testing.T) {
+	params, err := getUnitParams(&SystemdCtx{TT: "tt", ConfigPath: "/env"},
+		running.InstanceCtx{
+			AppDir:   t.TempDir(),
+			AppName:  "app",
+			InstName: "master",
+			ResourceLimits: cgroups.Limits{
+				MemoryMax: 512 << 20,
+				CPUQuota:  150,
+				IOWeight:  200,
+			},
+		})
+	require.NoError(t, err)
+
+	content, err := RenderUnit(params)
+	require.NoError(t, err)
+	assert.Contains(t, content, "LimitNOFILE=65535\n"+
+		"# Resource limits are applied to the unit cgroup\n"+
+		"Environment=TT_CLI_CGROUPS_DISABLED=true\n"+
+		"MemoryMax=536870912\n"+
+		"CPUQuota=150%\n"+
+		"IOWeight=200\n\n")
+
+	params.MemoryMax, params.CPUQuota, params.IOWeight = 0, 0, 0
+	content, err = RenderUnit(params)
+	require.NoError(t, err)
+	assert.Contains(t, content, "LimitNOFILE=65535\n\n")
+	assert.NotContains(t, content, "TT_CLI_CGROUPS_DISABLED")
+}
+
+func testInstances(t *testing.T) []running.InstanceCtx {
+	appDir := t.TempDir()
+	return []running.InstanceCtx{
+		{AppDir: appDir, AppName: "app", InstName: "router"},
+		{AppDir: appDir, AppName: "app", InstName: "storage"},
+		{AppDir: t.TempDir(), AppName: "single", InstName: "single", SingleApp: true},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	systemdCtx := SystemdCtx{Instances: testInstances(t), TT: "tt", ConfigPath: "/env"}
+
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&systemdCtx, "", &buf))
+	assert.True(t, strings.HasPrefix(buf.String(), "# app@.service\n[Unit]\n"))
+	assert.Contains(t, buf.String(), "\n# single.service\n[Unit]\n")
+
+	outputDir := filepath.Join(t.TempDir(), "units")
+	require.NoError(t, Generate(&systemdCtx, outputDir, &buf))
+	assert.FileExists(t, filepath.Join(outputDir, "app@.service"))
+	assert.FileExists(t, filepath.Join(outputDir, "single.service"))
+
+	err := Generate(&SystemdCtx{}, "", &buf)
+	assert.ErrorContains(t, err, "there are no instances")
+}
+
+func TestInstallUninstall(t *testing.T) {
+	var calls []string
+	origSystemctl := systemctl
+	systemctl = func(userManager bool, args ...string) error {
+		if userManager {
+			args = append([]string{"--user"}, args...)
+		}
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+	defer func() { systemctl = origSystemctl }()
+
+	unitDir := t.TempDir()
+	systemdCtx := SystemdCtx{
+		Instances:   testInstances(t),
+		TT:          "tt",
+		ConfigPath:  "/env",
+		UserManager: true,
+		UnitDir:     unitDir,
+	}
+
+	require.NoError(t, Install(&systemdCtx))
+	assert.FileExists(t, filepath.Join(unitDir, "app@.service"))
+	assert.FileExists(t, filepath.Join(unitDir, "single.service"))
+	assert.Equal(t, []string{
+		"--user daemon-reload",
+		"--user enable app@router.service app@storage.service single.service",
+	}, calls)
+
+	calls = nil
+	require.NoError(t, Uninstall(&systemdCtx))
+	assert.NoFileExists(t, filepath.Join(unitDir, "app@.service"))
+	assert.NoFileExists(t, filepath.Join(unitDir, "single.service"))
+	assert.Equal(t, []string{
+		"--user disable --now app@router.service app@storage.service single.service",
+		"--user daemon-reload",
+	}, calls)
+}
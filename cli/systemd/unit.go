@@ -0,0 +1,131 @@
+package systemd
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/util"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultFdLimit is a default limit of open files for an instance.
+	DefaultFdLimit = 65535
+	// UnitParamsFileName is a name of the application file with custom unit
+	// parameters.
+	UnitParamsFileName = "systemd-unit-params.yml"
+)
+
+//go:embed templates/app-inst-unit-template.txt
+var appInstUnitContentTemplate string
+
+// UnitParams are parameters of a systemd unit of an application.
+type UnitParams struct {
+	// AppName is an application name used in the unit description.
+	AppName string `yaml:"AppName"`
+	// ExecArgs are arguments of tt start and stop commands.
+	ExecArgs string `yaml:"ExecArgs"`
+	// TT is a path to the tt executable.
+	TT string `yaml:"TT"`
+	// ConfigPath is a path to the tt environment.
+	ConfigPath string `yaml:"ConfigPath"`
+	// FdLimit is a limit of open files.
+	FdLimit uint64 `yaml:"FdLimit"`
+	// InstanceEnv are environment variables of the instance.
+	InstanceEnv map[string]string `yaml:"instance-env"`
+	// PIDFile is a path to the watchdog PID file. It is omitted if empty.
+	PIDFile string `yaml:"PIDFile,omitempty"`
+	// Restart is a systemd restart setting of the unit. Default: on-failure.
+	Restart string `yaml:"Restart,omitempty"`
+	// RestartSec is a delay in seconds before a restart. Default: 2.
+	RestartSec int `yaml:"RestartSec,omitempty"`
+	// StartLimitBurst is a number of restarts allowed within StartLimitInterval.
+	// There is no limit if it is zero.
+	StartLimitBurst int `yaml:"StartLimitBurst,omitempty"`
+	// StartLimitInterval is an interval to limit restarts, like `60s` or
+	// `infinity`.
+	StartLimitInterval string `yaml:"StartLimitInterval,omitempty"`
+	// User is a user to run the instance. Default: tarantool.
+	User string `yaml:"User,omitempty"`
+	// Group is a group to run the instance. Default: tarantool.
+	Group string `yaml:"Group,omitempty"`
+	// UserManager is true for units of the user service manager. Such units
+	// have no user, group and OOM score settings.
+	UserManager bool `yaml:"UserManager,omitempty"`
+	// WantedBy is a target that wants the unit. Default: multi-user.target.
+	WantedBy string `yaml:"WantedBy,omitempty"`
+	// MemoryMax is a memory usage hard limit in bytes. There is no limit if
+	// it is zero.
+	MemoryMax int64 `yaml:"MemoryMax,omitempty"`
+	// CPUQuota is a CPU time limit in percents of one CPU. There is no limit
+	// if it is zero.
+	CPUQuota int `yaml:"CPUQuota,omitempty"`
+	// IOWeight is a relative IO weight from 1 to 10000. The default weight is
+	// used if it is zero.
+	IOWeight int `yaml:"IOWeight,omitempty"`
+}
+
+// UnitFileName generates systemd unit file name for application.
+func UnitFileName(inst running.InstanceCtx) string {
+	if inst.SingleApp {
+		return fmt.Sprintf("%s.service", inst.AppName)
+	}
+	return fmt.Sprintf("%s@.service", inst.AppName)
+}
+
+// UnitName generates systemd unit name for the instance.
+func UnitName(inst running.InstanceCtx) string {
+	if inst.SingleApp {
+		return fmt.Sprintf("%s.service", inst.AppName)
+	}
+	return fmt.Sprintf("%s@%s.service", inst.AppName, inst.InstName)
+}
+
+// DescriptionAppName generates an app name to use in description line in systemd unit.
+func DescriptionAppName(inst running.InstanceCtx) string {
+	if inst.SingleApp {
+		return inst.AppName
+	}
+	return fmt.Sprintf("%s@%%i", inst.AppName)
+}
+
+// ExecArgsForApp generates CLI arguments for start/stop commands in unit file.
+func ExecArgsForApp(inst running.InstanceCtx) string {
+	if inst.SingleApp {
+		// There are no instances for single instance application. So only app name is returned.
+		return inst.AppName
+	}
+	// Return <app><delimiter>%i format ars. %i will be replaced with instance name.
+	return fmt.Sprintf("%s%c%%i", inst.AppName, running.InstanceDelimiter)
+}
+
+// LoadUnitParams loads custom unit parameters from the YAML file.
+func LoadUnitParams(unitParamsFile string, unitParams *UnitParams) error {
+	unitTemplFile, err := os.Open(unitParamsFile)
+	if err != nil {
+		return fmt.Errorf("cannot open systemd unit parameters file %q: %s",
+			unitParamsFile, err)
+	}
+	defer unitTemplFile.Close()
+
+	if err = yaml.NewDecoder(unitTemplFile).Decode(unitParams); err != nil {
+		return fmt.Errorf("failed to decode systemd unit params: %s", err)
+	}
+	return nil
+}
+
+// RenderUnit returns the unit content.
+func RenderUnit(params UnitParams) (string, error) {
+	return util.GetTextTemplatedStr(&appInstUnitContentTemplate, params)
+}
+
+// InstantiateUnit creates the unit file.
+func InstantiateUnit(unitPath string, params UnitParams) error {
+	if err := util.InstantiateFileFromTemplate(unitPath, appInstUnitContentTemplate,
+		params); err != nil {
+		return fmt.Errorf("failed to create systemd unit file: %s", err)
+	}
+	return nil
+}
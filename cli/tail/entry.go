@@ -0,0 +1,186 @@
+package tail
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogLevel is a Tarantool log level. A lesser level is more severe.
+type LogLevel int
+
+const (
+	LogLevelFatal LogLevel = iota
+	LogLevelSysError
+	LogLevelError
+	LogLevelCrit
+	LogLevelWarn
+	LogLevelInfo
+	LogLevelVerbose
+	LogLevelDebug
+	// LogLevelUnknown is a level of a line without a known level.
+	LogLevelUnknown
+)
+
+// logLevels describes names and plain format letters of log levels.
+var logLevels = []struct {
+	name   string
+	letter string
+}{
+	LogLevelFatal:    {"fatal", "F"},
+	LogLevelSysError: {"syserror", "!"},
+	LogLevelError:    {"error", "E"},
+	LogLevelCrit:     {"crit", "C"},
+	LogLevelWarn:     {"warn", "W"},
+	LogLevelInfo:     {"info", "I"},
+	LogLevelVerbose:  {"verbose", "V"},
+	LogLevelDebug:    {"debug", "D"},
+	LogLevelUnknown:  {"unknown", ""},
+}
+
+// String returns a name of the log level.
+func (level LogLevel) String() string {
+	if level < 0 || int(level) >= len(logLevels) {
+		return logLevels[LogLevelUnknown].name
+	}
+	return logLevels[level].name
+}
+
+// MarshalText encodes the log level as its name.
+func (level LogLevel) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
+}
+
+// ParseLogLevel parses a log level name or a plain format letter.
+func ParseLogLevel(str string) (LogLevel, error) {
+	for level, info := range logLevels[:LogLevelUnknown] {
+		if strings.EqualFold(str, info.name) || str == info.letter {
+			return LogLevel(level), nil
+		}
+	}
+	if strings.EqualFold(str, "warning") {
+		return LogLevelWarn, nil
+	}
+	return LogLevelUnknown, fmt.Errorf("unknown log level %q", str)
+}
+
+const (
+	// plainTimeLayout is a layout of a timestamp in the plain log format.
+	plainTimeLayout = "2006-01-02 15:04:05.000"
+	// jsonTimeLayout is a layout of a timestamp in the JSON log format.
+	jsonTimeLayout = "2006-01-02T15:04:05.000-0700"
+)
+
+// plainLogRe matches a line of the plain log format:
+// `2024-01-02 03:04:05.678 [123] main/104/init.lua [file.c:10] I> message`.
+var plainLogRe = regexp.MustCompile(
+	`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}) \[\d+\] (\S+)(?: \S+)? ([FEWICVD!])> (.*)$`)
+
+// LogEntry is a parsed log line.
+type LogEntry struct {
+	// Instance is a name of the instance that wrote the line.
+	Instance string `json:"instance"`
+	// Time is a timestamp of the entry. Lines without a timestamp inherit it
+	// from the previous entry.
+	Time time.Time `json:"time,omitzero"`
+	// Level is a log level of the entry.
+	Level LogLevel `json:"level"`
+	// Fiber describes a fiber that wrote the entry.
+	Fiber string `json:"fiber,omitempty"`
+	// Message is a message of the entry.
+	Message string `json:"message"`
+	// Line is the raw log line.
+	Line string `json:"-"`
+}
+
+// jsonLogLine is a line of the JSON log format.
+type jsonLogLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+	CordName  string `json:"cord_name"`
+	FiberID   int    `json:"fiber_id"`
+	FiberName string `json:"fiber_name"`
+}
+
+// parseJSONLogLine parses a line of the JSON log format.
+func parseJSONLogLine(line string, entry *LogEntry) bool {
+	if !strings.HasPrefix(line, "{") {
+		return false
+	}
+	var parsed jsonLogLine
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+		return false
+	}
+	ts, err := time.Parse(jsonTimeLayout, parsed.Time)
+	if err != nil {
+		return false
+	}
+	level, err := ParseLogLevel(parsed.Level)
+	if err != nil {
+		return false
+	}
+	entry.Time = ts
+	entry.Level = level
+	entry.Message = parsed.Message
+	if parsed.CordName != "" {
+		entry.Fiber = fmt.Sprintf("%s/%d/%s", parsed.CordName, parsed.FiberID, parsed.FiberName)
+	}
+	return true
+}
+
+// parsePlainLogLine parses a line of the plain log format.
+func parsePlainLogLine(line string, entry *LogEntry) bool {
+	match := plainLogRe.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+	ts, err := time.ParseInLocation(plainTimeLayout, match[1], time.Local)
+	if err != nil {
+		return false
+	}
+	level, err := ParseLogLevel(match[3])
+	if err != nil {
+		return false
+	}
+	entry.Time = ts
+	entry.Fiber = match[2]
+	entry.Level = level
+	entry.Message = match[4]
+	return true
+}
+
+// LogParser parses log lines of an instance in plain or JSON format. Lines
+// of an unknown format, like multiline messages, inherit the timestamp and
+// the level of the previous entry.
+type LogParser struct {
+	instance string
+	prev     LogEntry
+}
+
+// NewLogParser creates a new LogParser for the instance.
+func NewLogParser(instance string) *LogParser {
+	return &LogParser{
+		instance: instance,
+		prev:     LogEntry{Level: LogLevelUnknown},
+	}
+}
+
+// Parse parses the log line.
+func (parser *LogParser) Parse(line string) LogEntry {
+	entry := LogEntry{
+		Instance: parser.instance,
+		Line:     line,
+	}
+	if parseJSONLogLine(line, &entry) || parsePlainLogLine(line, &entry) {
+		parser.prev = entry
+		return entry
+	}
+	entry.Time = parser.prev.Time
+	entry.Level = parser.prev.Level
+	entry.Fiber = parser.prev.Fiber
+	entry.Message = line
+	return entry
+}
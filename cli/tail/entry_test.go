@@ -0,0 +1,124 @@
+package tail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"fatal", LogLevelFatal, false},
+		{"SYSERROR", LogLevelSysError, false},
+		{"!", LogLevelSysError, false},
+		{"E", LogLevelError, false},
+		{"warning", LogLevelWarn, false},
+		{"warn", LogLevelWarn, false},
+		{"I", LogLevelInfo, false},
+		{"debug", LogLevelDebug, false},
+		{"trace", LogLevelUnknown, true},
+		{"", LogLevelUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.str)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, level)
+		})
+	}
+}
+
+func TestLogParser_Parse(t *testing.T) {
+	parser := NewLogParser("app:inst")
+
+	plainTime := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.Local)
+	jsonTime := time.Date(2024, 1, 2, 3, 4, 6, 0, time.FixedZone("", 3*60*60))
+
+	tests := []struct {
+		name string
+		line string
+		want LogEntry
+	}{
+		{
+			name: "plain",
+			line: "2024-01-02 03:04:05.678 [123] main/104/init.lua I> started",
+			want: LogEntry{
+				Time:    plainTime,
+				Level:   LogLevelInfo,
+				Fiber:   "main/104/init.lua",
+				Message: "started",
+			},
+		},
+		{
+			name: "plain with source location",
+			line: "2024-01-02 03:04:05.678 [123] main/104/init.lua box.cc:10 E> failed",
+			want: LogEntry{
+				Time:    plainTime,
+				Level:   LogLevelError,
+				Fiber:   "main/104/init.lua",
+				Message: "failed",
+			},
+		},
+		{
+			name: "continuation",
+			line: "stack traceback:",
+			want: LogEntry{
+				Time:    plainTime,
+				Level:   LogLevelError,
+				Fiber:   "main/104/init.lua",
+				Message: "stack traceback:",
+			},
+		},
+		{
+			name: "json",
+			line: `{"time": "2024-01-02T03:04:06.000+0300", "level": "WARN", ` +
+				`"message": "slow", "pid": 123, "cord_name": "main", "fiber_id": 104, ` +
+				`"fiber_name": "init.lua", "file": "box.cc", "line": 10}`,
+			want: LogEntry{
+				Time:    jsonTime,
+				Level:   LogLevelWarn,
+				Fiber:   "main/104/init.lua",
+				Message: "slow",
+			},
+		},
+		{
+			name: "not a json",
+			line: "{broken",
+			want: LogEntry{
+				Time:    jsonTime,
+				Level:   LogLevelWarn,
+				Fiber:   "main/104/init.lua",
+				Message: "{broken",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := parser.Parse(tt.line)
+			tt.want.Instance = "app:inst"
+			tt.want.Line = tt.line
+			require.True(t, tt.want.Time.Equal(entry.Time), "%s != %s", tt.want.Time, entry.Time)
+			entry.Time = tt.want.Time
+			assert.Equal(t, tt.want, entry)
+		})
+	}
+}
+
+func TestLogParser_ParseUnknown(t *testing.T) {
+	entry := NewLogParser("inst").Parse("some line")
+	assert.Equal(t, LogLevelUnknown, entry.Level)
+	assert.True(t, entry.Time.IsZero())
+	assert.Equal(t, "some line", entry.Message)
+}
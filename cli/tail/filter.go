@@ -0,0 +1,115 @@
+package tail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogFilter describes log entries to keep.
+type LogFilter struct {
+	// Level is the least severe level to keep.
+	Level LogLevel
+	// Since drops entries before the moment if set.
+	Since time.Time
+	// Until drops entries after the moment if set.
+	Until time.Time
+	// Substring drops entries without the substring if set.
+	Substring string
+	// Regexp drops entries not matching the regular expression if set.
+	Regexp *regexp.Regexp
+}
+
+// NewLogFilter creates a new LogFilter that keeps all entries.
+func NewLogFilter() LogFilter {
+	return LogFilter{Level: LogLevelUnknown}
+}
+
+// Match checks whether the entry passes the filter.
+func (filter LogFilter) Match(entry LogEntry) bool {
+	if entry.Level > filter.Level {
+		return false
+	}
+	if !filter.Since.IsZero() && (entry.Time.IsZero() || entry.Time.Before(filter.Since)) {
+		return false
+	}
+	if !filter.Until.IsZero() && (entry.Time.IsZero() || entry.Time.After(filter.Until)) {
+		return false
+	}
+	if filter.Substring != "" && !strings.Contains(entry.Line, filter.Substring) {
+		return false
+	}
+	if filter.Regexp != nil && !filter.Regexp.MatchString(entry.Line) {
+		return false
+	}
+	return true
+}
+
+// ReadEntries parses the log file of the instance and returns the last n
+// entries passing the filter.
+func ReadEntries(ctx context.Context, fileName string, instance string, filter LogFilter,
+	n int,
+) ([]LogEntry, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative lines count is not supported")
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %q: %w", fileName, err)
+	}
+	defer file.Close()
+
+	parser := NewLogParser(instance)
+	// The last n entries are kept in a ring buffer: next is the position of
+	// the oldest entry when the buffer is full.
+	entries := make([]LogEntry, 0, n)
+	next := 0
+	// bufio.Reader is used instead of bufio.Scanner to support lines of any
+	// length.
+	reader := bufio.NewReader(file)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, fmt.Errorf("cannot read %q: %w", fileName, readErr)
+		}
+		if line != "" && n > 0 {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			if entry := parser.Parse(line); filter.Match(entry) {
+				if len(entries) < n {
+					entries = append(entries, entry)
+				} else {
+					entries[next] = entry
+					next = (next + 1) % n
+				}
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return append(entries[next:], entries[:next]...), nil
+}
+
+// MergeEntries merges entries of several instances in chronological order.
+// The order of entries with equal timestamps is preserved.
+func MergeEntries(entries ...[]LogEntry) []LogEntry {
+	var merged []LogEntry
+	for _, instEntries := range entries {
+		merged = append(merged, instEntries...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged
+}
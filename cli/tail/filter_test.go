@@ -0,0 +1,136 @@
+package tail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilter_Match(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := LogEntry{
+		Time:  ts,
+		Level: LogLevelWarn,
+		Line:  "2024-01-02 03:04:05.000 [1] main W> slow request",
+	}
+
+	tests := []struct {
+		name   string
+		filter func(*LogFilter)
+		entry  LogEntry
+		want   bool
+	}{
+		{"all", func(f *LogFilter) {}, entry, true},
+		{"level passes", func(f *LogFilter) { f.Level = LogLevelWarn }, entry, true},
+		{"level drops", func(f *LogFilter) { f.Level = LogLevelError }, entry, false},
+		{"since passes", func(f *LogFilter) { f.Since = ts }, entry, true},
+		{"since drops", func(f *LogFilter) { f.Since = ts.Add(time.Second) }, entry, false},
+		{"until passes", func(f *LogFilter) { f.Until = ts }, entry, true},
+		{"until drops", func(f *LogFilter) { f.Until = ts.Add(-time.Second) }, entry, false},
+		{
+			"since drops no time", func(f *LogFilter) { f.Since = ts },
+			LogEntry{Level: LogLevelUnknown}, false,
+		},
+		{"substring passes", func(f *LogFilter) { f.Substring = "slow" }, entry, true},
+		{"substring drops", func(f *LogFilter) { f.Substring = "fast" }, entry, false},
+		{
+			"regexp passes", func(f *LogFilter) { f.Regexp = regexp.MustCompile(`s\w+ req`) },
+			entry, true,
+		},
+		{
+			"regexp drops", func(f *LogFilter) { f.Regexp = regexp.MustCompile(`^slow`) },
+			entry, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewLogFilter()
+			tt.filter(&filter)
+			assert.Equal(t, tt.want, filter.Match(tt.entry))
+		})
+	}
+}
+
+func TestReadEntries(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "inst.log")
+	require.NoError(t, os.WriteFile(fileName, []byte(
+		"2024-01-02 03:04:01.000 [1] main I> one\n"+
+			"2024-01-02 03:04:02.000 [1] main E> two\n"+
+			"traceback\n"+
+			"2024-01-02 03:04:03.000 [1] main I> three\n"+
+			"2024-01-02 03:04:04.000 [1] main E> four\n"), 0o644))
+
+	filter := NewLogFilter()
+	filter.Level = LogLevelError
+
+	lines := func(entries []LogEntry) []string {
+		var lines []string
+		for _, entry := range entries {
+			lines = append(lines, entry.Message)
+		}
+		return lines
+	}
+
+	entries, err := ReadEntries(context.Background(), fileName, "inst", filter, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "traceback", "four"}, lines(entries))
+
+	entries, err = ReadEntries(context.Background(), fileName, "inst", filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"traceback", "four"}, lines(entries))
+
+	entries, err = ReadEntries(context.Background(), fileName, "inst", filter, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = ReadEntries(context.Background(), fileName+".missing", "inst", filter, 1)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadEntries_longLines(t *testing.T) {
+	long := strings.Repeat("x", 256*1024)
+	var content strings.Builder
+	for i := range 5 {
+		fmt.Fprintf(&content, "2024-01-02 03:04:0%d.000 [1] main I> %d %s\r\n", i, i, long)
+	}
+	// The last line is not terminated.
+	content.WriteString("2024-01-02 03:04:05.000 [1] main I> last")
+	fileName := filepath.Join(t.TempDir(), "inst.log")
+	require.NoError(t, os.WriteFile(fileName, []byte(content.String()), 0o644))
+
+	entries, err := ReadEntries(context.Background(), fileName, "inst", NewLogFilter(), 3)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "3 "+long, entries[0].Message)
+	assert.Equal(t, "4 "+long, entries[1].Message)
+	assert.Equal(t, "last", entries[2].Message)
+}
+
+func TestMergeEntries(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	merged := MergeEntries(
+		[]LogEntry{
+			{Instance: "a", Time: ts, Message: "a1"},
+			{Instance: "a", Time: ts.Add(2 * time.Second), Message: "a2"},
+		},
+		[]LogEntry{
+			{Instance: "b", Time: ts, Message: "b1"},
+			{Instance: "b", Time: ts.Add(time.Second), Message: "b2"},
+		},
+	)
+
+	var messages []string
+	for _, entry := range merged {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"a1", "b1", "b2", "a2"}, messages)
+}
@@ -0,0 +1,60 @@
+local fiber = require('fiber')
+
+if type(box.cfg) == 'function' then
+    return {box_status = 'unconfigured'}
+end
+
+local info = box.info()
+
+local rps = 0
+for name, stat in pairs(box.stat()) do
+    if name ~= 'ERROR' and type(stat) == 'table' and stat.rps ~= nil then
+        rps = rps + stat.rps
+    end
+end
+
+local slab = box.slab.info()
+local memory = box.info.memory()
+
+local fibers = 0
+for _ in pairs(fiber.info({backtrace = false})) do
+    fibers = fibers + 1
+end
+
+local lag = 0
+for _, replica in pairs(info.replication) do
+    if replica.upstream ~= nil and replica.upstream.lag ~= nil and
+            replica.upstream.lag > lag then
+        lag = replica.upstream.lag
+    end
+end
+
+local vclock = {}
+for id, lsn in pairs(info.vclock) do
+    vclock[tostring(id)] = lsn
+end
+
+local gc = box.info.gc()
+local checkpoint_signature = 0
+if #gc.checkpoints > 0 then
+    checkpoint_signature = gc.checkpoints[#gc.checkpoints].signature
+end
+
+return {
+    box_status = info.status,
+    read_only = info.ro,
+    uptime = info.uptime,
+    rps = rps,
+    arena_used = slab.arena_used,
+    arena_size = slab.arena_size,
+    quota_used = slab.quota_used,
+    quota_size = slab.quota_size,
+    lua_memory = memory.lua,
+    tx_memory = memory.tx,
+    fibers = fibers,
+    replication_lag = lag,
+    vclock = vclock,
+    signature = info.signature,
+    wal_rows = info.signature - checkpoint_signature,
+    checkpoint_in_progress = gc.checkpoint_is_in_progress,
+}
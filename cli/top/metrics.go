@@ -0,0 +1,130 @@
+package top
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+)
+
+//go:embed lua/instance_metrics.lua
+var instanceMetricsBody string
+
+// Metrics are metrics of a running instance.
+type Metrics struct {
+	// BoxStatus is the box.info.status value.
+	BoxStatus string `mapstructure:"box_status" json:"box_status"`
+	// ReadOnly is true for a read-only instance.
+	ReadOnly bool `mapstructure:"read_only" json:"read_only"`
+	// Uptime is an uptime of the instance in seconds.
+	Uptime int64 `mapstructure:"uptime" json:"uptime"`
+	// RPS is a number of requests per second of all types.
+	RPS float64 `mapstructure:"rps" json:"rps"`
+	// ArenaUsed is a memory used by tuples in bytes.
+	ArenaUsed int64 `mapstructure:"arena_used" json:"arena_used"`
+	// ArenaSize is a memory allocated for tuples in bytes.
+	ArenaSize int64 `mapstructure:"arena_size" json:"arena_size"`
+	// QuotaUsed is a memory used by slab allocators in bytes.
+	QuotaUsed int64 `mapstructure:"quota_used" json:"quota_used"`
+	// QuotaSize is a memory limit of slab allocators in bytes.
+	QuotaSize int64 `mapstructure:"quota_size" json:"quota_size"`
+	// LuaMemory is a memory used by the Lua runtime in bytes.
+	LuaMemory int64 `mapstructure:"lua_memory" json:"lua_memory"`
+	// TxMemory is a memory used by transactions in bytes.
+	TxMemory int64 `mapstructure:"tx_memory" json:"tx_memory"`
+	// Fibers is a number of fibers.
+	Fibers int `mapstructure:"fibers" json:"fibers"`
+	// ReplicationLag is the maximum lag of upstreams in seconds.
+	ReplicationLag float64 `mapstructure:"replication_lag" json:"replication_lag"`
+	// Vclock is the vclock of the instance.
+	Vclock map[string]int64 `mapstructure:"vclock" json:"vclock"`
+	// Signature is a sum of all vclock components.
+	Signature int64 `mapstructure:"signature" json:"signature"`
+	// WALRows is a number of rows written since the last checkpoint.
+	WALRows int64 `mapstructure:"wal_rows" json:"wal_rows"`
+	// CheckpointInProgress is true if a checkpoint is being made.
+	CheckpointInProgress bool `mapstructure:"checkpoint_in_progress" json:"checkpoint_in_progress"`
+}
+
+// InstanceMetrics are collected metrics of an instance.
+type InstanceMetrics struct {
+	// Instance is a full name of the instance.
+	Instance string `json:"instance"`
+	// Status is a process status of the instance.
+	Status string `json:"status"`
+	// Metrics are metrics of the instance. It is nil if the metrics are not
+	// collected.
+	Metrics *Metrics `json:"metrics,omitempty"`
+	// Error describes a failure of metrics collecting.
+	Error string `json:"error,omitempty"`
+}
+
+// decodeMetrics decodes the result of the metrics script.
+func decodeMetrics(data []any) (Metrics, error) {
+	var metrics Metrics
+	if len(data) == 0 {
+		return metrics, fmt.Errorf("no data returned from the metrics script")
+	}
+	config := &mapstructure.DecoderConfig{
+		Result:           &metrics,
+		WeaklyTypedInput: true,
+	}
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return metrics, err
+	}
+	if err := decoder.Decode(data[0]); err != nil {
+		return metrics, fmt.Errorf("failed to decode metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// collectInstanceMetrics connects to the instance console socket and collects
+// its metrics.
+func collectInstanceMetrics(run running.InstanceCtx) InstanceMetrics {
+	procStatus := running.Status(&run)
+	instMetrics := InstanceMetrics{
+		Instance: running.GetAppInstanceName(run),
+		Status:   procStatus.Status,
+	}
+	if procStatus.Code != process_utils.ProcessRunningCode {
+		return instMetrics
+	}
+
+	conn, err := connector.Connect(connector.ConnectOpts{
+		Network: "unix",
+		Address: run.ConsoleSocket,
+	})
+	if err != nil {
+		instMetrics.Error = fmt.Sprintf("failed to connect to instance %s: %s",
+			instMetrics.Instance, err)
+		return instMetrics
+	}
+	defer conn.Close()
+
+	data, err := conn.Eval(instanceMetricsBody, []any{}, connector.RequestOpts{})
+	if err != nil {
+		instMetrics.Error = fmt.Sprintf("failed to collect metrics of instance %s: %s",
+			instMetrics.Instance, err)
+		return instMetrics
+	}
+	metrics, err := decodeMetrics(data)
+	if err != nil {
+		instMetrics.Error = err.Error()
+		return instMetrics
+	}
+	instMetrics.Metrics = &metrics
+	return instMetrics
+}
+
+// Collect collects metrics of the instances.
+func Collect(instances []running.InstanceCtx) []InstanceMetrics {
+	collected := make([]InstanceMetrics, 0, len(instances))
+	for _, run := range instances {
+		collected = append(collected, collectInstanceMetrics(run))
+	}
+	return collected
+}
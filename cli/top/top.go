@@ -0,0 +1,317 @@
+package top
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/tarantool/tt/cli/running"
+	"golang.org/x/term"
+)
+
+// SortKey is a key to sort instances rows by.
+type SortKey int
+
+const (
+	SortByName SortKey = iota
+	SortByRPS
+	SortByMemory
+	SortByFibers
+	SortByLag
+)
+
+// sortKeys describes names and hotkeys of sort keys.
+var sortKeys = []struct {
+	name string
+	key  byte
+}{
+	SortByName:   {"name", 'n'},
+	SortByRPS:    {"rps", 'r'},
+	SortByMemory: {"memory", 'm'},
+	SortByFibers: {"fibers", 'f'},
+	SortByLag:    {"lag", 'l'},
+}
+
+// String returns a name of the sort key.
+func (key SortKey) String() string {
+	return sortKeys[key].name
+}
+
+// ParseSortKey parses a sort key name.
+func ParseSortKey(str string) (SortKey, error) {
+	for key, info := range sortKeys {
+		if info.name == str {
+			return SortKey(key), nil
+		}
+	}
+	return SortByName, fmt.Errorf("unknown sort key %q: expected name, rps, memory, "+
+		"fibers or lag", str)
+}
+
+// historyLen is a number of RPS values shown in a sparkline.
+const historyLen = 20
+
+// sparkTicks are sparkline bars in ascending order.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns a sparkline of the values scaled from zero to the maximum.
+func Sparkline(values []float64) string {
+	maxValue := 0.0
+	for _, value := range values {
+		maxValue = max(maxValue, value)
+	}
+	var builder strings.Builder
+	for _, value := range values {
+		tick := 0
+		if maxValue > 0 {
+			tick = int(value / maxValue * float64(len(sparkTicks)-1))
+		}
+		builder.WriteRune(sparkTicks[max(0, min(tick, len(sparkTicks)-1))])
+	}
+	return builder.String()
+}
+
+// formatBytes formats the size in bytes with a binary unit.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"K", "M", "G", "T"} {
+		value /= unit
+		if value < unit || suffix == "T" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	return ""
+}
+
+// formatVclock formats the vclock ordered by replica ids.
+func formatVclock(vclock map[string]int64) string {
+	ids := slices.Collect(maps.Keys(vclock))
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s: %d", id, vclock[id]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// sortValue returns a value of the metrics to sort by.
+func sortValue(metrics *Metrics, key SortKey) float64 {
+	switch key {
+	case SortByRPS:
+		return metrics.RPS
+	case SortByMemory:
+		return float64(metrics.ArenaUsed + metrics.LuaMemory)
+	case SortByFibers:
+		return float64(metrics.Fibers)
+	case SortByLag:
+		return metrics.ReplicationLag
+	}
+	return 0
+}
+
+// SortMetrics sorts instances metrics by the key. Instances are sorted by name
+// in ascending order and by metrics in descending order. Instances without
+// metrics go last.
+func SortMetrics(collected []InstanceMetrics, key SortKey) {
+	sort.SliceStable(collected, func(i, j int) bool {
+		left, right := collected[i], collected[j]
+		if key != SortByName && (left.Metrics == nil) != (right.Metrics == nil) {
+			return left.Metrics != nil
+		}
+		if key != SortByName && left.Metrics != nil && right.Metrics != nil {
+			leftValue, rightValue := sortValue(left.Metrics, key), sortValue(right.Metrics, key)
+			if leftValue != rightValue {
+				return leftValue > rightValue
+			}
+		}
+		return left.Instance < right.Instance
+	})
+}
+
+// Dashboard keeps the last collected metrics and RPS history of instances.
+type Dashboard struct {
+	sortKey   SortKey
+	collected []InstanceMetrics
+	history   map[string][]float64
+}
+
+// NewDashboard creates a new Dashboard.
+func NewDashboard(sortKey SortKey) *Dashboard {
+	return &Dashboard{
+		sortKey: sortKey,
+		history: make(map[string][]float64),
+	}
+}
+
+// Update sets the collected metrics and appends RPS values to the history.
+func (dashboard *Dashboard) Update(collected []InstanceMetrics) {
+	for _, instMetrics := range collected {
+		rps := 0.0
+		if instMetrics.Metrics != nil {
+			rps = instMetrics.Metrics.RPS
+		}
+		history := append(dashboard.history[instMetrics.Instance], rps)
+		dashboard.history[instMetrics.Instance] = history[max(0, len(history)-historyLen):]
+	}
+	dashboard.collected = collected
+	SortMetrics(dashboard.collected, dashboard.sortKey)
+}
+
+// SetSortKey changes the sort key of rows.
+func (dashboard *Dashboard) SetSortKey(key SortKey) {
+	dashboard.sortKey = key
+	SortMetrics(dashboard.collected, dashboard.sortKey)
+}
+
+// Render writes the instances table.
+func (dashboard *Dashboard) Render(w io.Writer) {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(w)
+	tw.AppendHeader(table.Row{"INSTANCE", "STATUS", "MODE", "RPS", "RPS HISTORY", "ARENA",
+		"LUA", "FIBERS", "LAG", "VCLOCK", "WAL ROWS"})
+	for _, instMetrics := range dashboard.collected {
+		row := table.Row{instMetrics.Instance, instMetrics.Status}
+		metrics := instMetrics.Metrics
+		if metrics == nil {
+			if instMetrics.Error != "" {
+				row = append(row, instMetrics.Error)
+			}
+			tw.AppendRow(row)
+			continue
+		}
+		mode := "RW"
+		if metrics.ReadOnly {
+			mode = "RO"
+		}
+		walRows := fmt.Sprint(metrics.WALRows)
+		if metrics.CheckpointInProgress {
+			walRows += " (checkpoint)"
+		}
+		row = append(row,
+			mode,
+			fmt.Sprintf("%.0f", metrics.RPS),
+			Sparkline(dashboard.history[instMetrics.Instance]),
+			fmt.Sprintf("%s/%s", formatBytes(metrics.ArenaUsed), formatBytes(metrics.ArenaSize)),
+			formatBytes(metrics.LuaMemory),
+			metrics.Fibers,
+			fmt.Sprintf("%.3f", metrics.ReplicationLag),
+			formatVclock(metrics.Vclock),
+			walRows,
+		)
+		tw.AppendRow(row)
+	}
+	tw.Style().Options.DrawBorder = false
+	tw.Style().Options.SeparateColumns = false
+	tw.Style().Options.SeparateHeader = false
+	tw.Render()
+}
+
+// Once collects metrics of the instances once and writes them in the format:
+// table or json.
+func Once(instances []running.InstanceCtx, sortKey SortKey, format string,
+	w io.Writer,
+) error {
+	collected := Collect(instances)
+	SortMetrics(collected, sortKey)
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(collected, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "table":
+		dashboard := NewDashboard(sortKey)
+		dashboard.Update(collected)
+		dashboard.Render(w)
+		return nil
+	}
+	return fmt.Errorf("unsupported format %q: expected \"table\" or \"json\"", format)
+}
+
+// readKeys sends pressed keys to the channel.
+func readKeys(r io.Reader, keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			close(keys)
+			return
+		}
+		keys <- buf[0]
+	}
+}
+
+// draw clears the terminal and draws the dashboard.
+func draw(dashboard *Dashboard, interval time.Duration) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "tt top - %s, refresh every %s, sorted by %s\n",
+		time.Now().Format(time.TimeOnly), interval, dashboard.sortKey)
+	fmt.Fprintf(&buf, "Keys: n/r/m/f/l sort by name/rps/memory/fibers/lag, "+
+		"s next sort, q quit\n\n")
+	dashboard.Render(&buf)
+	// The terminal is in raw mode, so a carriage return is required.
+	output := strings.ReplaceAll(buf.String(), "\n", "\r\n")
+	fmt.Print("\033[H\033[2J" + output)
+}
+
+// Run shows the dashboard of the instances in the terminal until the user
+// quits. The metrics are collected every interval.
+func Run(instances []running.InstanceCtx, sortKey SortKey, interval time.Duration) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the dashboard requires a terminal, use --once instead")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	keys := make(chan byte)
+	go readKeys(os.Stdin, keys)
+
+	dashboard := NewDashboard(sortKey)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	dashboard.Update(Collect(instances))
+	draw(dashboard, interval)
+	for {
+		select {
+		case <-ticker.C:
+			dashboard.Update(Collect(instances))
+		case key, ok := <-keys:
+			const ctrlC = 3
+			if !ok || key == 'q' || key == ctrlC {
+				fmt.Print("\r\n")
+				return nil
+			}
+			if key == 's' {
+				dashboard.SetSortKey((dashboard.sortKey + 1) % SortKey(len(sortKeys)))
+			}
+			for sortKey, info := range sortKeys {
+				if key == info.key {
+					dashboard.SetSortKey(SortKey(sortKey))
+				}
+			}
+		}
+		draw(dashboard, interval)
+	}
+}
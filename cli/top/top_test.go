@@ -0,0 +1,150 @@
+package top
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestParseSortKey(t *testing.T) {
+	for _, name := range []string{"name", "rps", "memory", "fibers", "lag"} {
+		key, err := ParseSortKey(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, key.String())
+	}
+	_, err := ParseSortKey("cpu")
+	assert.Error(t, err)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", Sparkline(nil))
+	assert.Equal(t, "▁▁▁", Sparkline([]float64{0, 0, 0}))
+	assert.Equal(t, "▁▄█", Sparkline([]float64{0, 50, 100}))
+	assert.Equal(t, "█▁", Sparkline([]float64{7, 0}))
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1.0K"},
+		{1536, "1.5K"},
+		{256 * 1024 * 1024, "256.0M"},
+		{3 << 30, "3.0G"},
+		{2048 << 40, "2048.0T"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatBytes(tt.size))
+	}
+}
+
+func TestFormatVclock(t *testing.T) {
+	assert.Equal(t, "{}", formatVclock(nil))
+	assert.Equal(t, "{1: 10, 2: 5, 10: 1}",
+		formatVclock(map[string]int64{"10": 1, "2": 5, "1": 10}))
+}
+
+func TestSortMetrics(t *testing.T) {
+	collected := []InstanceMetrics{
+		{Instance: "app:c", Metrics: &Metrics{RPS: 10, Fibers: 5, ArenaUsed: 1}},
+		{Instance: "app:a"},
+		{Instance: "app:b", Metrics: &Metrics{RPS: 20, Fibers: 5, LuaMemory: 10}},
+		{Instance: "app:d", Metrics: &Metrics{RPS: 10, Fibers: 1, ReplicationLag: 0.5}},
+	}
+	names := func() []string {
+		var names []string
+		for _, instMetrics := range collected {
+			names = append(names, instMetrics.Instance)
+		}
+		return names
+	}
+
+	tests := []struct {
+		key  SortKey
+		want []string
+	}{
+		{SortByName, []string{"app:a", "app:b", "app:c", "app:d"}},
+		{SortByRPS, []string{"app:b", "app:c", "app:d", "app:a"}},
+		{SortByMemory, []string{"app:b", "app:c", "app:d", "app:a"}},
+		{SortByFibers, []string{"app:b", "app:c", "app:d", "app:a"}},
+		{SortByLag, []string{"app:d", "app:b", "app:c", "app:a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			SortMetrics(collected, tt.key)
+			assert.Equal(t, tt.want, names())
+		})
+	}
+}
+
+func TestDashboard_Update(t *testing.T) {
+	dashboard := NewDashboard(SortByName)
+	for i := range historyLen + 5 {
+		dashboard.Update([]InstanceMetrics{
+			{Instance: "app:a", Metrics: &Metrics{RPS: float64(i)}},
+			{Instance: "app:b"},
+		})
+	}
+	require.Len(t, dashboard.history["app:a"], historyLen)
+	assert.Equal(t, 5.0, dashboard.history["app:a"][0])
+	assert.Equal(t, float64(historyLen+4), dashboard.history["app:a"][historyLen-1])
+	assert.Equal(t, make([]float64, historyLen), dashboard.history["app:b"])
+
+	var buf bytes.Buffer
+	dashboard.Render(&buf)
+	assert.Contains(t, buf.String(), "app:a")
+	assert.Contains(t, buf.String(), "▂▂▃▃▃▃▄▄▄▅▅▅▅▆▆▆▇▇▇█")
+}
+
+func TestDecodeMetrics(t *testing.T) {
+	metrics, err := decodeMetrics([]any{map[any]any{
+		"box_status": "running",
+		"read_only":  true,
+		"rps":        12.5,
+		"fibers":     7,
+		"vclock":     map[any]any{"1": 10, 2: 3},
+		"wal_rows":   42,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, Metrics{
+		BoxStatus: "running",
+		ReadOnly:  true,
+		RPS:       12.5,
+		Fibers:    7,
+		Vclock:    map[string]int64{"1": 10, "2": 3},
+		WALRows:   42,
+	}, metrics)
+
+	_, err = decodeMetrics(nil)
+	assert.Error(t, err)
+}
+
+func TestOnce_notRunning(t *testing.T) {
+	dir := t.TempDir()
+	instances := []running.InstanceCtx{
+		{
+			AppName:       "app",
+			InstName:      "inst",
+			PIDFile:       filepath.Join(dir, "inst.pid"),
+			ConsoleSocket: filepath.Join(dir, "inst.control"),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Once(instances, SortByName, "json", &buf))
+	var collected []InstanceMetrics
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &collected))
+	require.Len(t, collected, 1)
+	assert.Equal(t, "app:inst", collected[0].Instance)
+	assert.Nil(t, collected[0].Metrics)
+
+	assert.Error(t, Once(instances, SortByName, "yaml", &buf))
+}
@@ -1,6 +1,9 @@
 package util
 
-import "github.com/mgutz/ansi"
+import (
+	"github.com/fatih/color"
+	"github.com/mgutz/ansi"
+)
 
 var bold = ansi.ColorFunc("default+b")
 
@@ -8,3 +11,10 @@ var bold = ansi.ColorFunc("default+b")
 func Bold(s string) string {
 	return bold(s)
 }
+
+// IsColorEnabled returns true if the colored output is enabled. It is
+// disabled if the standard output is not a terminal, TERM is "dumb" or the
+// NO_COLOR environment variable is set.
+func IsColorEnabled() bool {
+	return !color.NoColor
+}
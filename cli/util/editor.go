@@ -0,0 +1,33 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// EditorEnv is an environment variable with a text editor command.
+const EditorEnv = "EDITOR"
+
+// DefaultEditor is a text editor used if the EDITOR environment variable
+// is not set.
+const DefaultEditor = "vi"
+
+// EditFile opens the file in a text editor from the EDITOR environment
+// variable and waits until the editor exits.
+func EditFile(path string) error {
+	editor := strings.Fields(os.Getenv(EditorEnv))
+	if len(editor) == 0 {
+		editor = []string{DefaultEditor}
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor[0], err)
+	}
+	return nil
+}
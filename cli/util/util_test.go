@@ -859,3 +859,20 @@ func TestRemoveScheme(t *testing.T) {
 		})
 	}
 }
+
+func TestEditFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	editor := filepath.Join(tmpDir, "editor.sh")
+	require.NoError(t, os.WriteFile(editor,
+		[]byte("#!/bin/sh\necho \"$1 $2\" > \"$2\"\n"), 0o755))
+	path := filepath.Join(tmpDir, "file.txt")
+
+	t.Setenv(EditorEnv, editor+" --wait")
+	require.NoError(t, EditFile(path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "--wait "+path+"\n", string(content))
+
+	t.Setenv(EditorEnv, filepath.Join(tmpDir, "missing"))
+	assert.ErrorContains(t, EditFile(path), "failed to run editor")
+}
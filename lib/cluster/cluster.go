@@ -392,7 +392,23 @@ func CreateCollector(
 		return nil
 	}
 
-	if err := DoOnStorage(connOpts, opts, tarantoolFunc, etcdFunc); err != nil {
+	consulFunc := func(client *ConsulClient) error {
+		if collectors != nil {
+			collector, err = collectors.NewConsul(client, prefix, key, timeout)
+			if err != nil {
+				client.Close()
+				return fmt.Errorf("failed to create consul collector: %w", err)
+			}
+		}
+		closeFunc = func() { client.Close() }
+		return nil
+	}
+
+	if err := DoOnStorageFuncs(connOpts, opts, StorageFuncs{
+		Tarantool: tarantoolFunc,
+		Etcd:      etcdFunc,
+		Consul:    consulFunc,
+	}); err != nil {
 		return nil, nil, err
 	}
 
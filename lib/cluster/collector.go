@@ -25,6 +25,10 @@ type CollectorFactory interface {
 	// tarantool config storage.
 	NewTarantool(conn tarantool.Doer,
 		prefix, key string, timeout time.Duration) (Collector, error)
+	// NewConsul creates a new data collector to collect configuration from
+	// Consul.
+	NewConsul(client *ConsulClient,
+		prefix, key string, timeout time.Duration) (Collector, error)
 }
 
 // yamlDataCollectorFactoryDecorator is a wrapper over DataCollectorFactory turning
@@ -55,6 +59,14 @@ func (factory yamlDataCollectorFactoryDecorator) NewTarantool(conn tarantool.Doe
 	return NewYamlCollectorDecorator(collector), err
 }
 
+// NewConsul creates a new Consul DataCollector and wraps it.
+func (factory yamlDataCollectorFactoryDecorator) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (Collector, error) {
+	collector, err := factory.rawFactory.NewConsul(client, prefix, key, timeout)
+	return NewYamlCollectorDecorator(collector), err
+}
+
 // NewCollectorFactory turns arbitrary DataCollectorFactory into a
 // CollectorFactory using YAML collector decorator.
 func NewCollectorFactory(factory DataCollectorFactory) CollectorFactory {
@@ -44,6 +44,17 @@ func (mock mockTarantoolCollector) Collect() ([]cluster.Data, error) {
 	return nil, errors.New("not implemented")
 }
 
+type mockConsulCollector struct {
+	client  *cluster.ConsulClient
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+func (mock mockConsulCollector) Collect() ([]cluster.Data, error) {
+	return nil, errors.New("not implemented")
+}
+
 type mockDataCollectorFactory struct{}
 
 func (mock mockDataCollectorFactory) NewFile(path string) (cluster.DataCollector, error) {
@@ -74,9 +85,21 @@ func (mock mockDataCollectorFactory) NewTarantool(conn tarantool.Doer,
 	}, nil
 }
 
+func (mock mockDataCollectorFactory) NewConsul(client *cluster.ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (cluster.DataCollector, error) {
+	return mockConsulCollector{
+		client:  client,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}, nil
+}
+
 func TestCollectorFactory(t *testing.T) {
 	etcdcli := &clientv3.Client{}
 	conn := &tarantool.Connection{}
+	consul := &cluster.ConsulClient{}
 	factory := cluster.NewCollectorFactory(mockDataCollectorFactory{})
 
 	noErr := func(collector cluster.Collector, err error) cluster.Collector {
@@ -136,6 +159,16 @@ func TestCollectorFactory(t *testing.T) {
 				timeout: 2,
 			}),
 		},
+		{
+			Name:      "consul_key",
+			Collector: noErr(factory.NewConsul(consul, "foo", "bar", 2)),
+			Expected: cluster.NewYamlCollectorDecorator(mockConsulCollector{
+				client:  consul,
+				prefix:  "foo",
+				key:     "bar",
+				timeout: 2,
+			}),
+		},
 	}
 
 	for _, tc := range cases {
@@ -3,32 +3,70 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	libconnect "github.com/tarantool/tt/lib/connect"
 )
 
+// CSWatchEvent is a change of a key in a config storage.
 type CSWatchEvent struct {
-	Key   string
+	// Key is a changed key.
+	Key string
+	// Value is a new value of the key. It is nil if the key is deleted.
 	Value []byte
+	// Revision is a modification revision of the change.
+	Revision int64
+	// Deleted is true if the key is deleted.
+	Deleted bool
 }
 
 // CSConnection interface is to be used to implement access to config storage.
 type CSConnection interface {
 	// Close closes connection.
 	Close() error
-	// Get retrieves value for key.
+	// Get retrieves value for key. All keys with the prefix are retrieved if
+	// the key ends with a slash.
 	Get(ctx context.Context, key string) ([]Data, error)
 	// Put puts a key-value pair into config storage.
 	Put(ctx context.Context, key, value string) error
 	// Watch watches on a key and return watched events through the returned channel.
+	// All keys with the prefix are watched if the key ends with a slash.
 	Watch(ctx context.Context, key string) <-chan CSWatchEvent
 }
 
+// CSSnapshot is a snapshot of keys in a config storage.
+type CSSnapshot struct {
+	// Data are values of keys at the moment of the snapshot.
+	Data []Data
+	// Revision is a revision of the storage at the moment of the snapshot.
+	Revision int64
+}
+
+// CSSnapshotWatcher is implemented by config storage connections that watch
+// changes since a snapshot. Changes made between the snapshot and the start
+// of watching are not lost and the snapshot values are not reported again.
+type CSSnapshotWatcher interface {
+	// Snapshot retrieves values for key. All keys with the prefix are
+	// retrieved if the key ends with a slash. A missing key is not an error.
+	Snapshot(ctx context.Context, key string) (CSSnapshot, error)
+	// WatchSince watches on a key for changes after the snapshot and returns
+	// watched events through the returned channel.
+	WatchSince(ctx context.Context, key string, snapshot CSSnapshot) <-chan CSWatchEvent
+}
+
 // ConnectCStorage connects to config storage according to connection options.
 func ConnectCStorage(
 	uriOpts libconnect.UriOpts,
 	connOpts ConnectOpts,
 ) (CSConnection, error) {
+	// Only Tarantool connections support SSH tunnels.
+	if uriOpts.Ssh.Host != "" {
+		return connectTarantoolCS(uriOpts, connOpts)
+	}
+	if uriOpts.IsConsul() {
+		return connectConsulCS(uriOpts, connOpts)
+	}
+
 	sc, errEtcd := connectEtcdCS(uriOpts, connOpts)
 	if errEtcd == nil {
 		return sc, nil
@@ -42,3 +80,8 @@ func ConnectCStorage(
 	return nil, fmt.Errorf("failed to establish a connection to tarantool or etcd: %w, %w",
 		errTarantool, errEtcd)
 }
+
+// isPrefixKey returns true if the key is a prefix of keys.
+func isPrefixKey(key string) bool {
+	return strings.HasSuffix(key, "/")
+}
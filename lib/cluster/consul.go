@@ -0,0 +1,719 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+
+	libconnect "github.com/tarantool/tt/lib/connect"
+)
+
+const (
+	// ConsulTokenEnv is an environment variable with a Consul ACL token.
+	ConsulTokenEnv = "CONSUL_HTTP_TOKEN"
+	// consulIndexHeader is a header with an index of a Consul response.
+	consulIndexHeader = "X-Consul-Index"
+	// consulTokenHeader is a header with a Consul ACL token.
+	consulTokenHeader = "X-Consul-Token"
+	// consulWatchWait is a maximum duration of a blocking query to watch.
+	consulWatchWait = time.Minute
+	// consulWatchRetryDelay is a delay before a retry of a failed blocking
+	// query.
+	consulWatchRetryDelay = time.Second
+)
+
+// Consul transaction verbs.
+const (
+	consulVerbSet        = "set"
+	consulVerbDelete     = "delete"
+	consulVerbDeleteTree = "delete-tree"
+	consulVerbCheckIndex = "check-index"
+)
+
+// consulMaxTxnOps is a maximum number of operations in a Consul transaction.
+const consulMaxTxnOps = 64
+
+// ConsulOpts is a way to configure a Consul client.
+type ConsulOpts struct {
+	// Address is an address of the Consul HTTP API: http(s)://host:port.
+	Address string
+	// Token is an ACL token for authorization.
+	Token string
+	// KeyFile is a path to a private SSL key file.
+	KeyFile string
+	// CertFile is a path to an SSL certificate file.
+	CertFile string
+	// CaPath is a path to a trusted certificate authorities (CA) directory.
+	CaPath string
+	// CaFile is a path to a trusted certificate authorities (CA) file.
+	CaFile string
+	// SkipHostVerify controls whether a client verifies the server's
+	// certificate chain and host name. This is dangerous option so by
+	// default it is false.
+	SkipHostVerify bool
+	// Timeout is a timeout for actions.
+	Timeout time.Duration
+}
+
+// isTLS returns true if the options require a TLS connection.
+func (opts ConsulOpts) isTLS() bool {
+	return opts.KeyFile != "" || opts.CertFile != "" || opts.CaFile != "" ||
+		opts.CaPath != "" || opts.SkipHostVerify
+}
+
+// ConsulKV is a key-value pair from a Consul KV storage.
+type ConsulKV struct {
+	// Key is a key with a leading slash.
+	Key string
+	// Value is a value of the key.
+	Value []byte
+	// ModifyIndex is an index of the last modification of the key.
+	ModifyIndex int64
+}
+
+// consulTxnOp is an operation of a Consul transaction.
+type consulTxnOp struct {
+	Verb  string
+	Key   string
+	Value []byte `json:",omitempty"`
+	Index int64  `json:",omitempty"`
+}
+
+// ConsulClient is a client of a Consul KV storage HTTP API. Keys of the
+// client have a leading slash as keys of etcd and the tarantool config
+// storage, it is removed in requests to Consul.
+type ConsulClient struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// NewConsulClient creates a new client object for a Consul from the
+// specified options. It does not connect to Consul.
+func NewConsulClient(opts ConsulOpts) (*ConsulClient, error) {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.isTLS() {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      opts.CertFile,
+			KeyFile:       opts.KeyFile,
+			TrustedCAFile: opts.CaFile,
+		}
+
+		var (
+			tlsConfig *tls.Config
+			err       error
+		)
+		if opts.CertFile != "" || opts.KeyFile != "" || opts.CaFile != "" {
+			if tlsConfig, err = tlsInfo.ClientConfig(); err != nil {
+				return nil, fmt.Errorf("fail to create tls client config: %w", err)
+			}
+		} else {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		if opts.CaPath != "" {
+			if tlsConfig.RootCAs, err = loadRootCA(opts.CaPath); err != nil {
+				return nil, fmt.Errorf("fail to load CA directory: %w", err)
+			}
+		}
+		if opts.SkipHostVerify {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		httpTransport.TLSClientConfig = tlsConfig
+	}
+
+	return &ConsulClient{
+		address: strings.TrimRight(opts.Address, "/"),
+		token:   opts.Token,
+		client:  &http.Client{Transport: httpTransport},
+	}, nil
+}
+
+// ConnectConsul creates a new client object for a Consul from the specified
+// options and checks that Consul is available.
+func ConnectConsul(opts ConsulOpts) (*ConsulClient, error) {
+	client, err := NewConsulClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if opts.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if _, _, err := client.do(ctx, http.MethodGet, "/v1/status/leader", nil, nil); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// ConnectConsulUriOpts creates a new client object for a Consul from URI
+// options. A password from the URI, a password from the connect options or
+// CONSUL_HTTP_TOKEN environment variable is used as an ACL token.
+func ConnectConsulUriOpts(
+	uriOpts libconnect.UriOpts,
+	connOpts ConnectOpts,
+) (*ConsulClient, error) {
+	consulOpts := MakeConsulOptsFromUriOpts(uriOpts)
+	if consulOpts.Token == "" {
+		consulOpts.Token = connOpts.Password
+	}
+	if consulOpts.Token == "" {
+		consulOpts.Token = os.Getenv(ConsulTokenEnv)
+	}
+
+	client, err := ConnectConsul(consulOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to consul: %w", err)
+	}
+	return client, nil
+}
+
+// MakeConsulOptsFromUriOpts create Consul connect options from URI options.
+func MakeConsulOptsFromUriOpts(src libconnect.UriOpts) ConsulOpts {
+	opts := ConsulOpts{
+		Token:          src.Password,
+		KeyFile:        src.KeyFile,
+		CertFile:       src.CertFile,
+		CaPath:         src.CaPath,
+		CaFile:         src.CaFile,
+		SkipHostVerify: src.SkipHostVerify || src.SkipPeerVerify,
+		Timeout:        src.Timeout,
+	}
+	scheme := "http"
+	if opts.isTLS() {
+		scheme = "https"
+	}
+	opts.Address = scheme + "://" + src.Host
+	return opts
+}
+
+// Close closes idle connections of the client.
+func (c *ConsulClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// do sends a request to Consul and returns a response body and an index of
+// the response.
+func (c *ConsulClient) do(ctx context.Context, method, path string, query url.Values,
+	body []byte,
+) ([]byte, int64, error) {
+	target := c.address + path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.token != "" {
+		req.Header.Set(consulTokenHeader, c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index, _ := strconv.ParseInt(resp.Header.Get(consulIndexHeader), 10, 64)
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return nil, index, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, index, fmt.Errorf("unexpected response status %q: %s",
+			resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, index, nil
+}
+
+// kvPath returns a path of a key in the KV API.
+func kvPath(key string) string {
+	return "/v1/kv/" + strings.TrimPrefix(key, "/")
+}
+
+// get retrieves values for a key. All keys with the prefix are retrieved if
+// the key ends with a slash. The request blocks until the index is changed
+// if the index is not 0.
+func (c *ConsulClient) get(ctx context.Context, key string, index int64,
+	wait time.Duration,
+) ([]ConsulKV, int64, error) {
+	query := url.Values{}
+	if isPrefixKey(key) {
+		query.Set("recurse", "true")
+	}
+	if index != 0 {
+		query.Set("index", strconv.FormatInt(index, 10))
+		query.Set("wait", wait.String())
+	}
+
+	data, index, err := c.do(ctx, http.MethodGet, kvPath(key), query, nil)
+	if err != nil || data == nil {
+		return nil, index, err
+	}
+	var kvs []ConsulKV
+	if err := json.Unmarshal(data, &kvs); err != nil {
+		return nil, index, fmt.Errorf("failed to parse a response: %w", err)
+	}
+	for i := range kvs {
+		kvs[i].Key = "/" + kvs[i].Key
+	}
+	return kvs, index, nil
+}
+
+// Get retrieves values for a key. All keys with the prefix are retrieved if
+// the key ends with a slash. It returns the values and an index of the
+// storage.
+func (c *ConsulClient) Get(ctx context.Context, key string) ([]ConsulKV, int64, error) {
+	return c.get(ctx, key, 0, 0)
+}
+
+// Put puts a key-value pair into Consul.
+func (c *ConsulClient) Put(ctx context.Context, key string, value []byte) error {
+	_, _, err := c.do(ctx, http.MethodPut, kvPath(key), nil, value)
+	return err
+}
+
+// txn executes operations in a transaction.
+func (c *ConsulClient) txn(ctx context.Context, ops []consulTxnOp) error {
+	type txnOp struct {
+		KV consulTxnOp
+	}
+	txnOps := make([]txnOp, 0, len(ops))
+	for _, op := range ops {
+		op.Key = strings.TrimPrefix(op.Key, "/")
+		txnOps = append(txnOps, txnOp{KV: op})
+	}
+	body, err := json.Marshal(txnOps)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.do(ctx, http.MethodPut, "/v1/txn", nil, body)
+	return err
+}
+
+// consulContext returns a context with the timeout if it is not 0.
+func consulContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// consulData converts key-value pairs from Consul into data.
+func consulData(kvs []ConsulKV) []Data {
+	collected := make([]Data, 0, len(kvs))
+	for _, kv := range kvs {
+		collected = append(collected, Data{
+			Source:   kv.Key,
+			Value:    kv.Value,
+			Revision: kv.ModifyIndex,
+		})
+	}
+	return collected
+}
+
+// ConsulAllCollector collects data from a Consul KV storage for a whole
+// prefix.
+type ConsulAllCollector struct {
+	client  *ConsulClient
+	prefix  string
+	timeout time.Duration
+}
+
+// NewConsulAllCollector creates a new collector for Consul from the whole
+// prefix.
+func NewConsulAllCollector(client *ConsulClient, prefix string,
+	timeout time.Duration,
+) ConsulAllCollector {
+	return ConsulAllCollector{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+// Collect collects a configuration from the specified prefix with the
+// specified timeout.
+func (collector ConsulAllCollector) Collect() ([]Data, error) {
+	prefix := getConfigPrefix(collector.prefix)
+	ctx, cancel := consulContext(collector.timeout)
+	defer cancel()
+
+	kvs, _, err := collector.client.Get(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+	if len(kvs) == 0 {
+		return nil, CollectEmptyError{"consul", prefix}
+	}
+	return consulData(kvs), nil
+}
+
+// ConsulKeyCollector collects data from a Consul KV storage for a key from
+// a prefix.
+type ConsulKeyCollector struct {
+	client  *ConsulClient
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+// NewConsulKeyCollector creates a new collector for Consul from a key from
+// a prefix.
+func NewConsulKeyCollector(client *ConsulClient, prefix, key string,
+	timeout time.Duration,
+) ConsulKeyCollector {
+	return ConsulKeyCollector{
+		client:  client,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}
+}
+
+// Collect collects a configuration from the specified path with the specified
+// timeout.
+func (collector ConsulKeyCollector) Collect() ([]Data, error) {
+	key := getConfigKey(collector.prefix, collector.key)
+	ctx, cancel := consulContext(collector.timeout)
+	defer cancel()
+
+	kvs, _, err := collector.client.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+	if len(kvs) != 1 {
+		return nil, fmt.Errorf("a configuration data not found in consul for key %q", key)
+	}
+	return consulData(kvs), nil
+}
+
+// ConsulAllDataPublisher publishes a data into a Consul KV storage to
+// a prefix.
+type ConsulAllDataPublisher struct {
+	client  *ConsulClient
+	prefix  string
+	timeout time.Duration
+}
+
+// NewConsulAllDataPublisher creates a new ConsulAllDataPublisher object to
+// publish a data to Consul with the prefix during the timeout.
+func NewConsulAllDataPublisher(client *ConsulClient, prefix string,
+	timeout time.Duration,
+) ConsulAllDataPublisher {
+	return ConsulAllDataPublisher{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+// Publish publishes the configuration into Consul to the given prefix.
+func (publisher ConsulAllDataPublisher) Publish(revision int64, data []byte) error {
+	const key = "all"
+
+	if revision != 0 {
+		return fmt.Errorf(
+			"failed to publish data into consul: target revision %d is not supported",
+			revision)
+	}
+	ops := []consulTxnOp{
+		{Verb: consulVerbDeleteTree, Key: getConfigPrefix(publisher.prefix)},
+		{Verb: consulVerbSet, Key: getConfigKey(publisher.prefix, key), Value: data},
+		// Consul does not keep previous values, so tt keeps a history of
+		// published values.
+		{
+			Verb:  consulVerbSet,
+			Key:   getHistoryKey(publisher.prefix, key, time.Now()),
+			Value: data,
+		},
+	}
+
+	ctx, cancel := consulContext(publisher.timeout)
+	defer cancel()
+	ops = append(ops, consulHistoryPrune(ctx, publisher.client, publisher.prefix, key,
+		consulMaxTxnOps-len(ops))...)
+	if err := publisher.client.txn(ctx, ops); err != nil {
+		return fmt.Errorf("failed to put data into consul: %w", err)
+	}
+	return nil
+}
+
+// ConsulKeyDataPublisher publishes a data into a Consul KV storage for
+// a prefix and a key.
+type ConsulKeyDataPublisher struct {
+	client  *ConsulClient
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+// NewConsulKeyDataPublisher creates a new ConsulKeyDataPublisher object to
+// publish a data to Consul with the prefix and key during the timeout.
+func NewConsulKeyDataPublisher(client *ConsulClient, prefix, key string,
+	timeout time.Duration,
+) ConsulKeyDataPublisher {
+	return ConsulKeyDataPublisher{
+		client:  client,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}
+}
+
+// Publish publishes the configuration into Consul to the given prefix and
+// key. If passed revision is not 0, the data will be published only if target
+// key revision the same.
+func (publisher ConsulKeyDataPublisher) Publish(revision int64, data []byte) error {
+	key := getConfigKey(publisher.prefix, publisher.key)
+
+	var ops []consulTxnOp
+	if revision != 0 {
+		ops = append(ops, consulTxnOp{Verb: consulVerbCheckIndex, Key: key, Index: revision})
+	}
+	ops = append(ops,
+		consulTxnOp{Verb: consulVerbSet, Key: key, Value: data},
+		consulTxnOp{
+			Verb:  consulVerbSet,
+			Key:   getHistoryKey(publisher.prefix, publisher.key, time.Now()),
+			Value: data,
+		})
+
+	ctx, cancel := consulContext(publisher.timeout)
+	defer cancel()
+	ops = append(ops, consulHistoryPrune(ctx, publisher.client, publisher.prefix, publisher.key,
+		consulMaxTxnOps-len(ops))...)
+	if err := publisher.client.txn(ctx, ops); err != nil {
+		return fmt.Errorf("failed to put data into consul: %w", err)
+	}
+	return nil
+}
+
+// consulHistoryPrune returns transaction operations to delete stale history
+// records of the key, at most maxOps operations. The pruning is best-effort:
+// no operations are returned if the history can not be read.
+func consulHistoryPrune(ctx context.Context, client *ConsulClient, prefix, key string,
+	maxOps int,
+) []consulTxnOp {
+	historyPrefix := getHistoryPrefix(prefix, key)
+	history, _, err := client.Get(ctx, historyPrefix)
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(history))
+	for _, kv := range history {
+		keys = append(keys, kv.Key)
+	}
+	var ops []consulTxnOp
+	for _, stale := range staleHistoryKeys(historyPrefix, keys) {
+		if len(ops) >= maxOps {
+			// The rest is deleted by next publishing.
+			break
+		}
+		ops = append(ops, consulTxnOp{Verb: consulVerbDelete, Key: stale})
+	}
+	return ops
+}
+
+// ConsulHistoryReader reads revisions of a configuration key from a history
+// kept by tt in a Consul KV storage. Each publishing of tt stores a history
+// record with the published value and a modification time.
+type ConsulHistoryReader struct {
+	client  *ConsulClient
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+// NewConsulHistoryReader creates a new ConsulHistoryReader object to read
+// revisions of the key from the prefix during the timeout.
+func NewConsulHistoryReader(client *ConsulClient, prefix, key string,
+	timeout time.Duration,
+) ConsulHistoryReader {
+	return ConsulHistoryReader{
+		client:  client,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}
+}
+
+// History returns revisions of the key from the newest to the oldest.
+func (reader ConsulHistoryReader) History(limit int) ([]KeyRevision, error) {
+	key := getConfigKey(reader.prefix, reader.key)
+	historyPrefix := getHistoryPrefix(reader.prefix, reader.key)
+	ctx, cancel := consulContext(reader.timeout)
+	defer cancel()
+
+	kvs, _, err := reader.client.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+	if len(kvs) != 1 {
+		return nil, fmt.Errorf("a configuration data not found in consul for key %q", key)
+	}
+	history, _, err := reader.client.Get(ctx, historyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+
+	records := make([]historyRecord, 0, len(history))
+	for _, kv := range history {
+		records = append(records, historyRecord{
+			Path:     kv.Key,
+			Revision: kv.ModifyIndex,
+			Value:    kv.Value,
+		})
+	}
+	current := KeyRevision{Revision: kvs[0].ModifyIndex, Value: kvs[0].Value}
+	return makeHistory(historyPrefix, current, records, limit), nil
+}
+
+type consulCSConnection struct {
+	client *ConsulClient
+}
+
+func connectConsulCS(uriOpts libconnect.UriOpts, connOpts ConnectOpts) (CSConnection, error) {
+	client, err := ConnectConsulUriOpts(uriOpts, connOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &consulCSConnection{
+		client: client,
+	}, nil
+}
+
+// Close implements ConnectCStorage interface.
+func (c *consulCSConnection) Close() error {
+	return c.client.Close()
+}
+
+// Get implements ConnectCStorage interface.
+func (c *consulCSConnection) Get(ctx context.Context, key string) ([]Data, error) {
+	kvs, _, err := c.client.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+	if !isPrefixKey(key) && len(kvs) != 1 {
+		return nil, fmt.Errorf("a configuration data not found in consul for key %q", key)
+	}
+	return consulData(kvs), nil
+}
+
+// Put implements ConnectCStorage interface.
+func (c *consulCSConnection) Put(ctx context.Context, key, value string) error {
+	return c.client.Put(ctx, key, []byte(value))
+}
+
+// Snapshot implements CSSnapshotWatcher interface.
+func (c *consulCSConnection) Snapshot(ctx context.Context, key string) (CSSnapshot, error) {
+	kvs, index, err := c.client.Get(ctx, key)
+	if err != nil {
+		return CSSnapshot{}, fmt.Errorf("failed to fetch data from consul: %w", err)
+	}
+	return CSSnapshot{Data: consulData(kvs), Revision: index}, nil
+}
+
+// WatchSince implements CSSnapshotWatcher interface. The first blocking query
+// waits for changes after the snapshot index and its result is compared with
+// the snapshot values.
+func (c *consulCSConnection) WatchSince(ctx context.Context, key string,
+	snapshot CSSnapshot,
+) <-chan CSWatchEvent {
+	known := make(map[string]int64, len(snapshot.Data))
+	for _, data := range snapshot.Data {
+		known[data.Source] = data.Revision
+	}
+	return c.watch(ctx, key, snapshot.Revision, known)
+}
+
+// Watch implements ConnectCStorage interface. Changes are received with
+// blocking queries.
+func (c *consulCSConnection) Watch(ctx context.Context, key string) <-chan CSWatchEvent {
+	return c.watch(ctx, key, 0, nil)
+}
+
+// watch watches on a key since the index. The known values are modification
+// indexes of keys to compare the first result with. Changes before the first
+// result are skipped if the known values are nil.
+func (c *consulCSConnection) watch(ctx context.Context, key string, index int64,
+	known map[string]int64,
+) <-chan CSWatchEvent {
+	ch := make(chan CSWatchEvent)
+	go func() {
+		defer close(ch)
+
+		var events []CSWatchEvent
+		for ctx.Err() == nil {
+			kvs, newIndex, err := c.client.get(ctx, key, index, consulWatchWait)
+			if err != nil {
+				select {
+				case <-time.After(consulWatchRetryDelay):
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			current := make(map[string]int64, len(kvs))
+			for _, kv := range kvs {
+				current[kv.Key] = kv.ModifyIndex
+				// Changes before the watch are skipped.
+				if known != nil && known[kv.Key] != kv.ModifyIndex {
+					events = append(events, CSWatchEvent{
+						Key:      kv.Key,
+						Value:    kv.Value,
+						Revision: kv.ModifyIndex,
+					})
+				}
+			}
+			for key := range known {
+				if _, ok := current[key]; !ok {
+					events = append(events, CSWatchEvent{
+						Key:      key,
+						Revision: newIndex,
+						Deleted:  true,
+					})
+				}
+			}
+			known = current
+
+			for _, event := range events {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			events = events[:0]
+
+			// The index must be reset if it goes backwards.
+			if newIndex < index {
+				newIndex = 0
+			}
+			index = max(newIndex, 1)
+		}
+	}()
+	return ch
+}
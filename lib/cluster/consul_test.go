@@ -0,0 +1,514 @@
+package cluster_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/lib/cluster"
+	libconnect "github.com/tarantool/tt/lib/connect"
+)
+
+// consulStubKV is a key-value pair of a Consul stub.
+type consulStubKV struct {
+	Key         string
+	Value       []byte
+	ModifyIndex int64
+}
+
+// consulStub is a stand-in for a Consul KV HTTP API.
+type consulStub struct {
+	mu      sync.Mutex
+	index   int64
+	kvs     map[string]consulStubKV
+	changed chan struct{}
+	token   string
+}
+
+// newConsulStub creates and starts a Consul stub.
+func newConsulStub(t *testing.T, token string) (*consulStub, *httptest.Server) {
+	t.Helper()
+
+	stub := &consulStub{
+		index:   1,
+		kvs:     map[string]consulStubKV{},
+		changed: make(chan struct{}),
+		token:   token,
+	}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+// put sets a value of the key, the lock must be held.
+func (stub *consulStub) put(key string, value []byte) {
+	stub.kvs[key] = consulStubKV{Key: key, Value: value, ModifyIndex: stub.index}
+}
+
+// commit finishes a modification, the lock must be held.
+func (stub *consulStub) commit() {
+	stub.index++
+	close(stub.changed)
+	stub.changed = make(chan struct{})
+}
+
+// Put sets a value of the key.
+func (stub *consulStub) Put(key, value string) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	stub.put(key, []byte(value))
+	stub.commit()
+}
+
+// Delete deletes the key.
+func (stub *consulStub) Delete(key string) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	delete(stub.kvs, key)
+	stub.commit()
+}
+
+// Keys returns sorted keys of the stub.
+func (stub *consulStub) Keys() []string {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	keys := make([]string, 0, len(stub.kvs))
+	for key := range stub.kvs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// find returns values for the key or the prefix, the lock must be held.
+func (stub *consulStub) find(key string, recurse bool) []consulStubKV {
+	var kvs []consulStubKV
+	for _, kv := range stub.kvs {
+		if kv.Key == key || recurse && strings.HasPrefix(kv.Key, key) {
+			kvs = append(kvs, kv)
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+func (stub *consulStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != stub.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	key, isKV := strings.CutPrefix(r.URL.Path, "/v1/kv/")
+	switch {
+	case r.URL.Path == "/v1/status/leader":
+		w.Write([]byte(`"127.0.0.1:8300"`))
+	case isKV && r.Method == http.MethodGet:
+		stub.serveGet(w, r.URL.Query(), key)
+	case isKV && r.Method == http.MethodPut:
+		stub.Put(key, string(body))
+		w.Write([]byte("true"))
+	case r.URL.Path == "/v1/txn" && r.Method == http.MethodPut:
+		stub.serveTxn(w, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (stub *consulStub) serveGet(w http.ResponseWriter, query url.Values, key string) {
+	index, _ := strconv.ParseInt(query.Get("index"), 10, 64)
+	wait, _ := time.ParseDuration(query.Get("wait"))
+
+	stub.mu.Lock()
+	if index != 0 && index >= stub.index-1 {
+		changed := stub.changed
+		stub.mu.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(wait):
+		}
+		stub.mu.Lock()
+	}
+	kvs := stub.find(key, query.Has("recurse"))
+	w.Header().Set("X-Consul-Index", strconv.FormatInt(stub.index-1, 10))
+	stub.mu.Unlock()
+
+	if len(kvs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	data, _ := json.Marshal(kvs)
+	w.Write(data)
+}
+
+func (stub *consulStub) serveTxn(w http.ResponseWriter, body []byte) {
+	var ops []struct {
+		KV struct {
+			Verb  string
+			Key   string
+			Value []byte
+			Index int64
+		}
+	}
+	if err := json.Unmarshal(body, &ops); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	for _, op := range ops {
+		if op.KV.Verb == "check-index" && stub.kvs[op.KV.Key].ModifyIndex != op.KV.Index {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"Errors":[{"OpIndex":0,"What":"index mismatch"}]}`))
+			return
+		}
+	}
+	for _, op := range ops {
+		switch op.KV.Verb {
+		case "set":
+			stub.put(op.KV.Key, op.KV.Value)
+		case "delete":
+			delete(stub.kvs, op.KV.Key)
+		case "delete-tree":
+			for _, kv := range stub.find(op.KV.Key, true) {
+				delete(stub.kvs, kv.Key)
+			}
+		}
+	}
+	stub.commit()
+	w.Write([]byte(`{"Results":[],"Errors":null}`))
+}
+
+// connectConsulStub connects to the stub.
+func connectConsulStub(t *testing.T, server *httptest.Server, token string) *cluster.ConsulClient {
+	t.Helper()
+
+	client, err := cluster.ConnectConsul(cluster.ConsulOpts{
+		Address: server.URL,
+		Token:   token,
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestConnectConsul(t *testing.T) {
+	_, server := newConsulStub(t, "token")
+
+	_, err := cluster.ConnectConsul(cluster.ConsulOpts{Address: server.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unexpected response status "403 Forbidden"`)
+
+	uriOpts, err := libconnect.CreateUriOpts(strings.Replace(server.URL, "http", "consul", 1))
+	require.NoError(t, err)
+	_, err = cluster.ConnectConsulUriOpts(uriOpts, cluster.ConnectOpts{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to consul")
+
+	client, err := cluster.ConnectConsulUriOpts(uriOpts, cluster.ConnectOpts{Password: "token"})
+	require.NoError(t, err)
+	client.Close()
+
+	t.Setenv(cluster.ConsulTokenEnv, "token")
+	client, err = cluster.ConnectConsulUriOpts(uriOpts, cluster.ConnectOpts{})
+	require.NoError(t, err)
+	client.Close()
+}
+
+func TestMakeConsulOptsFromUriOpts(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected cluster.ConsulOpts
+	}{
+		{
+			url: "consul://:token@localhost:8500/prefix?timeout=2",
+			expected: cluster.ConsulOpts{
+				Address: "http://localhost:8500",
+				Token:   "token",
+				Timeout: 2 * time.Second,
+			},
+		},
+		{
+			url: "consul://localhost:8501/prefix?ssl_ca_file=ca.crt&verify_host=false",
+			expected: cluster.ConsulOpts{
+				Address:        "https://localhost:8501",
+				CaFile:         "ca.crt",
+				SkipHostVerify: true,
+				Timeout:        3 * time.Second,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			uriOpts, err := libconnect.CreateUriOpts(tc.url)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cluster.MakeConsulOptsFromUriOpts(uriOpts))
+		})
+	}
+}
+
+func TestConsulCollectors(t *testing.T) {
+	stub, server := newConsulStub(t, "")
+	client := connectConsulStub(t, server, "")
+
+	_, err := cluster.NewConsulAllCollector(client, "/prefix", time.Second).Collect()
+	assert.EqualError(t, err,
+		`a configuration data not found in consul for prefix "/prefix/config/"`)
+	_, err = cluster.NewConsulKeyCollector(client, "/prefix", "a", time.Second).Collect()
+	assert.EqualError(t, err, `a configuration data not found in consul for key "/prefix/config/a"`)
+
+	stub.Put("prefix/config/a", "foo: 1")
+	stub.Put("prefix/config/b", "bar: 2")
+	stub.Put("prefix/other", "baz: 3")
+
+	data, err := cluster.NewConsulAllCollector(client, "/prefix", time.Second).Collect()
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.Data{
+		{Source: "/prefix/config/a", Value: []byte("foo: 1"), Revision: 1},
+		{Source: "/prefix/config/b", Value: []byte("bar: 2"), Revision: 2},
+	}, data)
+
+	data, err = cluster.NewConsulKeyCollector(client, "/prefix", "b", time.Second).Collect()
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.Data{
+		{Source: "/prefix/config/b", Value: []byte("bar: 2"), Revision: 2},
+	}, data)
+
+	config, err := cluster.NewCollectorFactory(cluster.NewDataCollectorFactory()).
+		NewConsul(client, "/prefix", "", time.Second)
+	require.NoError(t, err)
+	merged, err := config.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, "bar: 2\nfoo: 1\n", merged.String())
+}
+
+func TestConsulDataPublishers(t *testing.T) {
+	stub, server := newConsulStub(t, "token")
+	client := connectConsulStub(t, server, "token")
+	stub.Put("prefix/config/old", "old")
+	stub.Put("prefix/other", "other")
+
+	publisher := cluster.NewConsulAllDataPublisher(client, "/prefix", time.Second)
+	err := publisher.Publish(1, []byte("foo"))
+	assert.EqualError(t, err,
+		"failed to publish data into consul: target revision 1 is not supported")
+
+	require.NoError(t, publisher.Publish(0, []byte("foo")))
+	keys := stub.Keys()
+	require.Len(t, keys, 3)
+	assert.Equal(t, "prefix/config/all", keys[0])
+	assert.True(t, strings.HasPrefix(keys[1], "prefix/history/all/"))
+	assert.Equal(t, "prefix/other", keys[2])
+
+	data, err := cluster.NewConsulKeyCollector(client, "/prefix", "all", time.Second).Collect()
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, []byte("foo"), data[0].Value)
+
+	keyPublisher := cluster.NewConsulKeyDataPublisher(client, "/prefix", "all", time.Second)
+	err = keyPublisher.Publish(data[0].Revision+1, []byte("bar"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to put data into consul")
+
+	require.NoError(t, keyPublisher.Publish(data[0].Revision, []byte("bar")))
+	require.NoError(t, keyPublisher.Publish(0, []byte("baz")))
+
+	data, err = cluster.NewConsulKeyCollector(client, "/prefix", "all", time.Second).Collect()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("baz"), data[0].Value)
+}
+
+func TestConsulDataPublishers_historyLimit(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Publisher func(client *cluster.ConsulClient) cluster.DataPublisher
+	}{
+		{"all", func(client *cluster.ConsulClient) cluster.DataPublisher {
+			return cluster.NewConsulAllDataPublisher(client, "/prefix", time.Second)
+		}},
+		{"key", func(client *cluster.ConsulClient) cluster.DataPublisher {
+			return cluster.NewConsulKeyDataPublisher(client, "/prefix", "all", time.Second)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stub, server := newConsulStub(t, "")
+			client := connectConsulStub(t, server, "")
+			historyKeys := func() []string {
+				var keys []string
+				for _, key := range stub.Keys() {
+					if strings.HasPrefix(key, "prefix/history/all/") {
+						keys = append(keys, key)
+					}
+				}
+				return keys
+			}
+			publisher := tc.Publisher(client)
+
+			for i := range cluster.HistoryLimit {
+				stub.Put("prefix/history/all/"+strconv.Itoa(1000+i), "old")
+			}
+			require.NoError(t, publisher.Publish(0, []byte("new")))
+			keys := historyKeys()
+			assert.Len(t, keys, cluster.HistoryLimit)
+			// The oldest record is deleted.
+			assert.NotContains(t, keys, "prefix/history/all/1000")
+			assert.Contains(t, keys, "prefix/history/all/1001")
+
+			// The transaction size is limited, so too many records are
+			// deleted by several publishings.
+			for i := range 2 * cluster.HistoryLimit {
+				stub.Put("prefix/history/all/"+strconv.Itoa(2000+i), "old")
+			}
+			require.NoError(t, publisher.Publish(0, []byte("new")))
+			assert.Greater(t, len(historyKeys()), cluster.HistoryLimit)
+			for range 5 {
+				require.NoError(t, publisher.Publish(0, []byte("new")))
+			}
+			assert.Len(t, historyKeys(), cluster.HistoryLimit)
+		})
+	}
+}
+
+func TestConsulHistoryReader_History(t *testing.T) {
+	stub, server := newConsulStub(t, "")
+	client := connectConsulStub(t, server, "")
+	reader := cluster.NewConsulHistoryReader(client, "/prefix", "all", time.Second)
+
+	_, err := reader.History(0)
+	assert.EqualError(t, err,
+		`a configuration data not found in consul for key "/prefix/config/all"`)
+
+	publisher := cluster.NewConsulKeyDataPublisher(client, "/prefix", "all", time.Second)
+	for _, value := range []string{"a", "b", "c"} {
+		require.NoError(t, publisher.Publish(0, []byte(value)))
+		time.Sleep(time.Millisecond)
+	}
+
+	revisions, err := reader.History(0)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	for i, value := range []string{"c", "b", "a"} {
+		assert.Equal(t, []byte(value), revisions[i].Value)
+		assert.False(t, revisions[i].Time.IsZero())
+	}
+	assert.Greater(t, revisions[0].Revision, revisions[1].Revision)
+
+	// A value published without tt.
+	stub.Put("prefix/config/all", "d")
+	revisions, err = reader.History(2)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, []byte("d"), revisions[0].Value)
+	assert.True(t, revisions[0].Time.IsZero())
+	assert.Equal(t, []byte("c"), revisions[1].Value)
+}
+
+func TestConnectCStorage_consul(t *testing.T) {
+	stub, server := newConsulStub(t, "")
+	uriOpts, err := libconnect.CreateUriOpts(
+		strings.Replace(server.URL, "http", "consul", 1) + "/prefix")
+	require.NoError(t, err)
+
+	conn, err := cluster.ConnectCStorage(uriOpts, cluster.ConnectOpts{})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, conn.Put(ctx, "/prefix/config/all", "foo"))
+	data, err := conn.Get(ctx, "/prefix/config/all")
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.Data{
+		{Source: "/prefix/config/all", Value: []byte("foo"), Revision: 1},
+	}, data)
+
+	_, err = conn.Get(ctx, "/prefix/config/unknown")
+	assert.EqualError(t, err,
+		`a configuration data not found in consul for key "/prefix/config/unknown"`)
+	data, err = conn.Get(ctx, "/prefix/unknown/")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	events := conn.Watch(ctx, "/prefix/config/")
+	// Wait for the first snapshot.
+	time.Sleep(100 * time.Millisecond)
+
+	stub.Put("prefix/config/b", "bar")
+	assert.Equal(t, cluster.CSWatchEvent{
+		Key:      "/prefix/config/b",
+		Value:    []byte("bar"),
+		Revision: 2,
+	}, <-events)
+
+	stub.Delete("prefix/config/all")
+	assert.Equal(t, cluster.CSWatchEvent{
+		Key:      "/prefix/config/all",
+		Revision: 3,
+		Deleted:  true,
+	}, <-events)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestConsulCSConnection_WatchSince(t *testing.T) {
+	stub, server := newConsulStub(t, "")
+	uriOpts, err := libconnect.CreateUriOpts(
+		strings.Replace(server.URL, "http", "consul", 1) + "/prefix")
+	require.NoError(t, err)
+
+	conn, err := cluster.ConnectCStorage(uriOpts, cluster.ConnectOpts{})
+	require.NoError(t, err)
+	defer conn.Close()
+	watcher, ok := conn.(cluster.CSSnapshotWatcher)
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stub.Put("prefix/config/a", "foo")
+	stub.Put("prefix/config/b", "bar")
+	snapshot, err := watcher.Snapshot(ctx, "/prefix/config/")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), snapshot.Revision)
+	assert.Len(t, snapshot.Data, 2)
+
+	// A change between the snapshot and the watching is not lost.
+	stub.Put("prefix/config/b", "baz")
+	events := watcher.WatchSince(ctx, "/prefix/config/", snapshot)
+	assert.Equal(t, cluster.CSWatchEvent{
+		Key:      "/prefix/config/b",
+		Value:    []byte("baz"),
+		Revision: 3,
+	}, <-events)
+
+	stub.Delete("prefix/config/a")
+	assert.Equal(t, cluster.CSWatchEvent{
+		Key:      "/prefix/config/a",
+		Revision: 4,
+		Deleted:  true,
+	}, <-events)
+
+	cancel()
+	for range events {
+	}
+}
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/tarantool/go-tarantool/v2"
@@ -34,6 +35,10 @@ type DataCollectorFactory interface {
 	// tarantool config storage.
 	NewTarantool(conn tarantool.Doer,
 		prefix, key string, timeout time.Duration) (DataCollector, error)
+	// NewConsul creates a new data collector to collect configuration from
+	// Consul.
+	NewConsul(client *ConsulClient,
+		prefix, key string, timeout time.Duration) (DataCollector, error)
 }
 
 // collectorsFactory is a type that implements a default DataCollectorFactory.
@@ -70,6 +75,16 @@ func (factory collectorsFactory) NewTarantool(conn tarantool.Doer,
 	return NewTarantoolKeyCollector(conn, prefix, key, timeout), nil
 }
 
+// NewConsul creates a new Consul configuration collector.
+func (factory collectorsFactory) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (DataCollector, error) {
+	if key == "" {
+		return NewConsulAllCollector(client, prefix, timeout), nil
+	}
+	return NewConsulKeyCollector(client, prefix, key, timeout), nil
+}
+
 // integrityCollectorsFactory is a type that implements a default CollectorFactory.
 type integrityCollectorsFactory struct {
 	checkFunc    CheckFunc
@@ -114,3 +129,11 @@ func (factory integrityCollectorsFactory) NewTarantool(conn tarantool.Doer,
 	return NewIntegrityTarantoolKeyCollector(factory.checkFunc,
 		conn, prefix, key, timeout), nil
 }
+
+// NewConsul returns an error because integrity checks are not supported for
+// Consul.
+func (factory integrityCollectorsFactory) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (DataCollector, error) {
+	return nil, fmt.Errorf("collecting from consul with integrity checks is not supported")
+}
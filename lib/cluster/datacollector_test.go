@@ -17,6 +17,7 @@ import (
 func TestDataCollectorFactory(t *testing.T) {
 	etcdcli := &clientv3.Client{}
 	conn := &tarantool.Connection{}
+	consul := &cluster.ConsulClient{}
 	factory := cluster.NewDataCollectorFactory()
 
 	noErr := func(collector cluster.DataCollector, err error) cluster.DataCollector {
@@ -49,6 +50,16 @@ func TestDataCollectorFactory(t *testing.T) {
 			Collector: noErr(factory.NewTarantool(conn, "foo", "bar", 2)),
 			Expected:  cluster.NewTarantoolKeyCollector(conn, "foo", "bar", 2),
 		},
+		{
+			Name:      "consul_all",
+			Collector: noErr(factory.NewConsul(consul, "foo", "", 1)),
+			Expected:  cluster.NewConsulAllCollector(consul, "foo", 1),
+		},
+		{
+			Name:      "consul_key",
+			Collector: noErr(factory.NewConsul(consul, "foo", "bar", 2)),
+			Expected:  cluster.NewConsulKeyCollector(consul, "foo", "bar", 2),
+		},
 	}
 
 	for _, tc := range cases {
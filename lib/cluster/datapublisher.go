@@ -26,6 +26,9 @@ type DataPublisherFactory interface {
 	// config storage.
 	NewTarantool(conn tarantool.Doer,
 		prefix, key string, timeout time.Duration) (DataPublisher, error)
+	// NewConsul creates a new data publisher to publish data into Consul.
+	NewConsul(client *ConsulClient,
+		prefix, key string, timeout time.Duration) (DataPublisher, error)
 }
 
 // publishersFactory is a type that implements a default DataPublisherFactory.
@@ -61,6 +64,16 @@ func (factory publishersFactory) NewTarantool(conn tarantool.Doer,
 	return NewTarantoolKeyDataPublisher(conn, prefix, key, timeout), nil
 }
 
+// NewConsul creates a new Consul data publisher.
+func (factory publishersFactory) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (DataPublisher, error) {
+	if key == "" {
+		return NewConsulAllDataPublisher(client, prefix, timeout), nil
+	}
+	return NewConsulKeyDataPublisher(client, prefix, key, timeout), nil
+}
+
 // integrityPublishersFactory is a type that implements a default
 // DataPublisherFactory.
 type integrityPublishersFactory struct {
@@ -102,3 +115,11 @@ func (factory integrityPublishersFactory) NewTarantool(conn tarantool.Doer,
 	return NewIntegrityTarantoolKeyDataPublisher(factory.signFunc,
 		conn, prefix, key, timeout), nil
 }
+
+// NewConsul returns an error because integrity signing is not supported for
+// Consul.
+func (factory integrityPublishersFactory) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (DataPublisher, error) {
+	return nil, fmt.Errorf("publishing into consul with integrity data is not supported")
+}
@@ -14,6 +14,7 @@ import (
 func TestDataPublisherFactory(t *testing.T) {
 	etcdcli := &clientv3.Client{}
 	conn := &tarantool.Connection{}
+	consul := &cluster.ConsulClient{}
 	factory := cluster.NewDataPublisherFactory()
 
 	noErr := func(publisher cluster.DataPublisher, err error) cluster.DataPublisher {
@@ -51,6 +52,16 @@ func TestDataPublisherFactory(t *testing.T) {
 			Publisher: noErr(factory.NewTarantool(conn, "foo", "bar", 2)),
 			Expected:  cluster.NewTarantoolKeyDataPublisher(conn, "foo", "bar", 2),
 		},
+		{
+			Name:      "consul_all",
+			Publisher: noErr(factory.NewConsul(consul, "foo", "", 1)),
+			Expected:  cluster.NewConsulAllDataPublisher(consul, "foo", 1),
+		},
+		{
+			Name:      "consul_key",
+			Publisher: noErr(factory.NewConsul(consul, "foo", "bar", 2)),
+			Expected:  cluster.NewConsulKeyDataPublisher(consul, "foo", "bar", 2),
+		},
 	}
 
 	for _, tc := range cases {
@@ -68,3 +79,12 @@ func TestIntegrityDataPublisherFactory_NewFile(t *testing.T) {
 	assert.EqualError(t, err,
 		"publishing into a file with integrity data is not supported")
 }
+
+func TestIntegrityDataPublisherFactory_NewConsul(t *testing.T) {
+	factory := cluster.NewIntegrityDataPublisherFactory(nil)
+	publisher, err := factory.NewConsul(nil, "foo", "", 0)
+
+	assert.Nil(t, publisher)
+	assert.EqualError(t, err,
+		"publishing into consul with integrity data is not supported")
+}
@@ -0,0 +1,195 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DiffKind is a kind of a configuration difference.
+type DiffKind string
+
+const (
+	// DiffAdded means that a path exists only in the new configuration.
+	DiffAdded DiffKind = "added"
+	// DiffRemoved means that a path exists only in the old configuration.
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged means that a path has different values.
+	DiffChanged DiffKind = "changed"
+)
+
+// Scope kinds of configuration paths.
+const (
+	ScopeGlobal     = "global"
+	ScopeGroup      = "group"
+	ScopeReplicaset = "replicaset"
+	ScopeInstance   = "instance"
+)
+
+// DiffScope is a scope of the cluster configuration a difference belongs to.
+type DiffScope struct {
+	// Kind is a kind of the scope: global, group, replicaset or instance.
+	Kind string
+	// Name is a name of the group, replicaset or instance.
+	Name string
+}
+
+// String returns a human-readable scope.
+func (scope DiffScope) String() string {
+	if scope.Kind == ScopeGlobal {
+		return scope.Kind
+	}
+	return fmt.Sprintf("%s %q", scope.Kind, scope.Name)
+}
+
+// DiffEntry is a difference of a configuration path. A whole subtree that
+// exists only in one configuration is a single entry.
+type DiffEntry struct {
+	// Kind is a kind of the difference.
+	Kind DiffKind
+	// Path is a configuration path.
+	Path []string
+	// Old is a value from the old configuration.
+	Old any
+	// New is a value from the new configuration.
+	New any
+}
+
+// scopeLevels are path prefixes of group, replicaset and instance scopes.
+var scopeLevels = []struct {
+	kind   string
+	prefix []string
+}{
+	{ScopeInstance, []string{"groups", "", "replicasets", "", "instances", ""}},
+	{ScopeReplicaset, []string{"groups", "", "replicasets", ""}},
+	{ScopeGroup, []string{"groups", ""}},
+}
+
+// Scope returns the deepest scope that contains the path and the path
+// relative to the scope.
+func (entry DiffEntry) Scope() (DiffScope, []string) {
+	for _, level := range scopeLevels {
+		if len(entry.Path) <= len(level.prefix) {
+			continue
+		}
+		matched := true
+		for i, key := range level.prefix {
+			if key != "" && entry.Path[i] != key {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			scope := DiffScope{Kind: level.kind, Name: entry.Path[len(level.prefix)-1]}
+			return scope, entry.Path[len(level.prefix):]
+		}
+	}
+	return DiffScope{Kind: ScopeGlobal}, entry.Path
+}
+
+// MarshalJSON encodes the entry into JSON.
+func (entry DiffEntry) MarshalJSON() ([]byte, error) {
+	scope, _ := entry.Scope()
+	return json.Marshal(struct {
+		Kind  DiffKind `json:"kind"`
+		Path  []string `json:"path"`
+		Scope string   `json:"scope"`
+		Name  string   `json:"name,omitempty"`
+		Old   any      `json:"old,omitempty"`
+		New   any      `json:"new,omitempty"`
+	}{
+		Kind:  entry.Kind,
+		Path:  entry.Path,
+		Scope: scope.Kind,
+		Name:  scope.Name,
+		Old:   JSONValue(entry.Old),
+		New:   JSONValue(entry.New),
+	})
+}
+
+// JSONValue converts a configuration value into a value that could be
+// encoded into JSON: maps with non-string keys are converted into maps with
+// string keys.
+func JSONValue(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = JSONValue(elem)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, elem := range v {
+			s[i] = JSONValue(elem)
+		}
+		return s
+	default:
+		return value
+	}
+}
+
+// diffValues appends differences between the values to the entries.
+func diffValues(path []string, old, new any, entries []DiffEntry) []DiffEntry {
+	oldMap, oldIsMap := old.(map[any]any)
+	newMap, newIsMap := new.(map[any]any)
+	if !oldIsMap || !newIsMap {
+		switch {
+		case old == nil && new == nil:
+		case old == nil:
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Path: path, New: new})
+		case new == nil:
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: path, Old: old})
+		case !reflect.DeepEqual(old, new):
+			entries = append(entries, DiffEntry{
+				Kind: DiffChanged, Path: path, Old: old, New: new,
+			})
+		}
+		return entries
+	}
+
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for _, m := range []map[any]any{oldMap, newMap} {
+		for key := range m {
+			if str, ok := key.(string); ok && !slices.Contains(keys, str) {
+				keys = append(keys, str)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		entries = diffValues(append(slices.Clip(path), key), oldMap[key], newMap[key], entries)
+	}
+	return entries
+}
+
+// Diff returns a path-level difference between the configurations sorted by
+// paths. A nil configuration is treated as an empty one.
+func Diff(old, new *Config) []DiffEntry {
+	var oldPaths, newPaths any = map[any]any{}, map[any]any{}
+	if old != nil && old.paths != nil {
+		oldPaths = old.paths
+	}
+	if new != nil && new.paths != nil {
+		newPaths = new.paths
+	}
+	return diffValues(nil, oldPaths, newPaths, nil)
+}
+
+// FormatDiffValue formats a configuration value of the difference into
+// a compact single-line form.
+func FormatDiffValue(value any) string {
+	data, err := json.Marshal(JSONValue(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// FormatDiffPath formats a configuration path of the difference.
+func FormatDiffPath(path []string) string {
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,195 @@
+package cluster_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/lib/cluster"
+)
+
+func mustCollectYaml(t *testing.T, data string) *cluster.Config {
+	t.Helper()
+	config, err := cluster.NewYamlCollector([]byte(data)).Collect()
+	require.NoError(t, err)
+	return config
+}
+
+func TestDiff(t *testing.T) {
+	old := mustCollectYaml(t, `
+credentials:
+  users:
+    guest:
+      roles: [super]
+groups:
+  storages:
+    replicasets:
+      s-001:
+        leader: s-001-a
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3301
+          s-001-b:
+            iproto:
+              listen:
+              - uri: localhost:3302
+`)
+	new := mustCollectYaml(t, `
+credentials:
+  users:
+    guest:
+      roles: [super]
+replication:
+  failover: manual
+groups:
+  storages:
+    sharding:
+      roles: [storage]
+    replicasets:
+      s-001:
+        leader: s-001-b
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3311
+          s-001-c:
+            iproto:
+              listen:
+              - uri: localhost:3303
+`)
+
+	entries := cluster.Diff(old, new)
+	expected := []cluster.DiffEntry{
+		{
+			Kind: cluster.DiffAdded,
+			Path: []string{"groups", "storages", "replicasets", "s-001", "instances",
+				"s-001-c"},
+			New: map[any]any{
+				"iproto": map[any]any{
+					"listen": []any{map[any]any{"uri": "localhost:3303"}},
+				},
+			},
+		},
+		{
+			Kind: cluster.DiffRemoved,
+			Path: []string{"groups", "storages", "replicasets", "s-001", "instances",
+				"s-001-b"},
+			Old: map[any]any{
+				"iproto": map[any]any{
+					"listen": []any{map[any]any{"uri": "localhost:3302"}},
+				},
+			},
+		},
+		{
+			Kind: cluster.DiffChanged,
+			Path: []string{"groups", "storages", "replicasets", "s-001", "instances",
+				"s-001-a", "iproto", "listen"},
+			Old: []any{map[any]any{"uri": "localhost:3301"}},
+			New: []any{map[any]any{"uri": "localhost:3311"}},
+		},
+		{
+			Kind: cluster.DiffChanged,
+			Path: []string{"groups", "storages", "replicasets", "s-001", "leader"},
+			Old:  "s-001-a",
+			New:  "s-001-b",
+		},
+		{
+			Kind: cluster.DiffAdded,
+			Path: []string{"groups", "storages", "sharding"},
+			New:  map[any]any{"roles": []any{"storage"}},
+		},
+		{
+			Kind: cluster.DiffAdded,
+			Path: []string{"replication"},
+			New:  map[any]any{"failover": "manual"},
+		},
+	}
+	assert.ElementsMatch(t, expected, entries)
+
+	// Entries are sorted by paths.
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, cluster.FormatDiffPath(entry.Path))
+	}
+	assert.IsNonDecreasing(t, paths)
+
+	assert.Empty(t, cluster.Diff(old, old))
+	assert.Empty(t, cluster.Diff(nil, cluster.NewConfig()))
+	assert.Equal(t, []cluster.DiffEntry{
+		{Kind: cluster.DiffRemoved, Path: []string{"replication"},
+			Old: map[any]any{"failover": "manual"}},
+	}, cluster.Diff(mustCollectYaml(t, "replication:\n  failover: manual\n"), nil))
+}
+
+func TestDiffEntry_Scope(t *testing.T) {
+	tests := []struct {
+		path          []string
+		expectedScope cluster.DiffScope
+		expectedPath  []string
+	}{
+		{
+			[]string{"replication", "failover"},
+			cluster.DiffScope{Kind: cluster.ScopeGlobal},
+			[]string{"replication", "failover"},
+		},
+		{
+			[]string{"groups", "g"},
+			cluster.DiffScope{Kind: cluster.ScopeGlobal},
+			[]string{"groups", "g"},
+		},
+		{
+			[]string{"groups", "g", "replicasets", "r"},
+			cluster.DiffScope{Kind: cluster.ScopeGroup, Name: "g"},
+			[]string{"replicasets", "r"},
+		},
+		{
+			[]string{"groups", "g", "replicasets", "r", "leader"},
+			cluster.DiffScope{Kind: cluster.ScopeReplicaset, Name: "r"},
+			[]string{"leader"},
+		},
+		{
+			[]string{"groups", "g", "replicasets", "r", "instances", "i", "roles"},
+			cluster.DiffScope{Kind: cluster.ScopeInstance, Name: "i"},
+			[]string{"roles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(cluster.FormatDiffPath(tt.path), func(t *testing.T) {
+			scope, path := cluster.DiffEntry{Path: tt.path}.Scope()
+			assert.Equal(t, tt.expectedScope, scope)
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+
+	assert.Equal(t, "global", cluster.DiffScope{Kind: cluster.ScopeGlobal}.String())
+	assert.Equal(t, `instance "i"`,
+		cluster.DiffScope{Kind: cluster.ScopeInstance, Name: "i"}.String())
+}
+
+func TestDiffEntry_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(cluster.DiffEntry{
+		Kind: cluster.DiffChanged,
+		Path: []string{"groups", "g", "replicasets", "r", "instances", "i", "iproto"},
+		Old:  map[any]any{"listen": []any{map[any]any{"uri": "localhost:3301"}}},
+		New:  false,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"kind": "changed",
+		"path": ["groups", "g", "replicasets", "r", "instances", "i", "iproto"],
+		"scope": "instance",
+		"name": "i",
+		"old": {"listen": [{"uri": "localhost:3301"}]},
+		"new": false
+	}`, string(data))
+
+	assert.Equal(t, `{"listen":[{"uri":"localhost:3301"}]}`,
+		cluster.FormatDiffValue(map[any]any{"listen": []any{map[any]any{"uri": "localhost:3301"}}}))
+	assert.Equal(t, `"foo"`, cluster.FormatDiffValue("foo"))
+}
@@ -847,18 +847,60 @@ func ConnectEtcdUriOpts(
 	return etcdcli, nil
 }
 
+// StorageFuncs are functions to work with a connected storage by a storage
+// type.
+type StorageFuncs struct {
+	// Tarantool is called for a tarantool config storage.
+	Tarantool func(tarantool.Connector) error
+	// Etcd is called for an etcd storage.
+	Etcd func(*clientv3.Client) error
+	// Consul is called for a Consul KV storage. Consul storages are not
+	// supported if it is nil.
+	Consul func(*ConsulClient) error
+}
+
 // DoOnStorage determines a storage based on the opts.
 func DoOnStorage(connOpts ConnectOpts, opts libconnect.UriOpts,
 	tarantoolFunc func(tarantool.Connector) error, etcdFunc func(*clientv3.Client) error,
 ) error {
+	return DoOnStorageFuncs(connOpts, opts, StorageFuncs{
+		Tarantool: tarantoolFunc,
+		Etcd:      etcdFunc,
+	})
+}
+
+// DoOnStorageFuncs determines a storage based on the opts and calls
+// a function for the storage type.
+func DoOnStorageFuncs(connOpts ConnectOpts, opts libconnect.UriOpts,
+	funcs StorageFuncs,
+) error {
+	// Only Tarantool connections support SSH tunnels.
+	if opts.Ssh.Host != "" {
+		conn, err := ConnectTarantool(opts, connOpts)
+		if err != nil {
+			return err
+		}
+		return funcs.Tarantool(conn)
+	}
+	if opts.IsConsul() {
+		if funcs.Consul == nil {
+			return fmt.Errorf("consul storage is not supported")
+		}
+		client, err := ConnectConsulUriOpts(opts, connOpts)
+		if err != nil {
+			return err
+		}
+		return funcs.Consul(client)
+	}
+
 	etcdcli, errEtcd := ConnectEtcdUriOpts(opts, connOpts)
 	if errEtcd == nil {
-		return etcdFunc(etcdcli)
+		return funcs.Etcd(etcdcli)
 	}
 
 	conn, errTarantool := ConnectTarantool(opts, connOpts)
 	if errTarantool == nil {
-		return tarantoolFunc(conn)
+		return funcs.Tarantool(conn)
 	}
 
 	return fmt.Errorf("failed to establish a connection to tarantool or etcd: %w, %w",
@@ -906,11 +948,27 @@ func (c *etcdCSConnection) Close() error {
 
 // Get implements ConnectCStorage interface.
 func (c *etcdCSConnection) Get(ctx context.Context, key string) ([]Data, error) {
-	resp, err := c.cli.Get(ctx, key)
+	var opts []clientv3.OpOption
+	if isPrefixKey(key) {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	resp, err := c.cli.Get(ctx, key, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data from etcd: %w", err)
 	}
 
+	if isPrefixKey(key) {
+		collected := make([]Data, 0, len(resp.Kvs))
+		for _, kv := range resp.Kvs {
+			collected = append(collected, Data{
+				Source:   string(kv.Key),
+				Value:    kv.Value,
+				Revision: kv.ModRevision,
+			})
+		}
+		return collected, nil
+	}
+
 	switch {
 	case len(resp.Kvs) == 0:
 		// It should not happen, but we need to be sure to avoid a null pointer
@@ -938,21 +996,71 @@ func (c *etcdCSConnection) Put(ctx context.Context, key, value string) error {
 	return err
 }
 
+// Snapshot implements CSSnapshotWatcher interface.
+func (c *etcdCSConnection) Snapshot(ctx context.Context, key string) (CSSnapshot, error) {
+	var opts []clientv3.OpOption
+	if isPrefixKey(key) {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	resp, err := c.cli.Get(ctx, key, opts...)
+	if err != nil {
+		return CSSnapshot{}, fmt.Errorf("failed to fetch data from etcd: %w", err)
+	}
+
+	snapshot := CSSnapshot{Revision: resp.Header.Revision}
+	for _, kv := range resp.Kvs {
+		snapshot.Data = append(snapshot.Data, Data{
+			Source:   string(kv.Key),
+			Value:    kv.Value,
+			Revision: kv.ModRevision,
+		})
+	}
+	return snapshot, nil
+}
+
+// WatchSince implements CSSnapshotWatcher interface. Etcd keeps previous
+// revisions, so the watching starts right after the snapshot revision.
+func (c *etcdCSConnection) WatchSince(ctx context.Context, key string,
+	snapshot CSSnapshot,
+) <-chan CSWatchEvent {
+	return c.watch(ctx, key, clientv3.WithRev(snapshot.Revision+1))
+}
+
 // Watch implements ConnectCStorage interface.
 func (c *etcdCSConnection) Watch(ctx context.Context, key string) <-chan CSWatchEvent {
+	return c.watch(ctx, key)
+}
+
+// watch watches on a key with the options.
+func (c *etcdCSConnection) watch(ctx context.Context, key string,
+	opts ...clientv3.OpOption,
+) <-chan CSWatchEvent {
+	if isPrefixKey(key) {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
 	ch := make(chan CSWatchEvent)
-	innerCh := c.cli.Watch(ctx, key)
+	innerCh := c.cli.Watch(ctx, key, opts...)
 	go func() {
 		defer close(ch)
 
 		for resp := range innerCh {
 			for _, ev := range resp.Events {
+				event := CSWatchEvent{
+					Key:      string(ev.Kv.Key),
+					Revision: ev.Kv.ModRevision,
+				}
 				switch ev.Type {
 				case mvccpb.PUT:
-					ch <- CSWatchEvent{
-						Key:   string(ev.Kv.Value),
-						Value: ev.Kv.Value,
-					}
+					event.Value = ev.Kv.Value
+				case mvccpb.DELETE:
+					event.Deleted = true
+				}
+
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
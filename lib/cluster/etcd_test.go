@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarantool/go-tarantool/v2"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 
@@ -745,3 +746,18 @@ func TestMakeEtcdOptsFromUriOpts(t *testing.T) {
 		})
 	}
 }
+
+func TestDoOnStorage_consulNotSupported(t *testing.T) {
+	opts := connect.UriOpts{Endpoint: connect.ConsulScheme + "://127.0.0.1:8500"}
+	called := false
+	call := func() error {
+		called = true
+		return nil
+	}
+
+	err := cluster.DoOnStorage(cluster.ConnectOpts{}, opts,
+		func(tarantool.Connector) error { return call() },
+		func(*clientv3.Client) error { return call() })
+	require.EqualError(t, err, "consul storage is not supported")
+	assert.False(t, called)
+}
@@ -0,0 +1,251 @@
+package cluster
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// KeyRevision is a revision of a configuration key.
+type KeyRevision struct {
+	// Revision is a modification revision of the key.
+	Revision int64
+	// Time is a moment of the modification. It is zero if it is unknown.
+	Time time.Time
+	// Value is a value of the key at the revision.
+	Value []byte
+}
+
+// HistoryReader reads revisions of a configuration key.
+type HistoryReader interface {
+	// History returns revisions of the key from the newest to the oldest.
+	// The first revision is the current one. Zero limit means no limit.
+	History(limit int) ([]KeyRevision, error)
+}
+
+// EtcdHistoryReader reads revisions of a configuration key from the
+// multi-version storage of etcd. Revisions are available until etcd compacts
+// them. Etcd does not store modification time.
+type EtcdHistoryReader struct {
+	getter  EtcdGetter
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+// NewEtcdHistoryReader creates a new EtcdHistoryReader object to read
+// revisions of the key from the prefix during the timeout.
+func NewEtcdHistoryReader(getter EtcdGetter,
+	prefix, key string, timeout time.Duration,
+) EtcdHistoryReader {
+	return EtcdHistoryReader{
+		getter:  getter,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}
+}
+
+// History returns revisions of the key from the newest to the oldest.
+func (reader EtcdHistoryReader) History(limit int) ([]KeyRevision, error) {
+	key := getConfigKey(reader.prefix, reader.key)
+	ctx := context.Background()
+	if reader.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, reader.timeout)
+		defer cancel()
+	}
+
+	var revisions []KeyRevision
+	for revision := int64(0); limit == 0 || len(revisions) < limit; {
+		var opts []clientv3.OpOption
+		if revision != 0 {
+			opts = append(opts, clientv3.WithRev(revision))
+		}
+		resp, err := reader.getter.Get(ctx, key, opts...)
+		if errors.Is(err, rpctypes.ErrCompacted) {
+			// Older revisions are removed by the compaction.
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch data from etcd: %w", err)
+		}
+		if len(resp.Kvs) == 0 {
+			break
+		}
+
+		kv := resp.Kvs[0]
+		revisions = append(revisions, KeyRevision{
+			Revision: kv.ModRevision,
+			Value:    kv.Value,
+		})
+		if kv.ModRevision <= 1 {
+			break
+		}
+		revision = kv.ModRevision - 1
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("a configuration data not found in etcd for key %q", key)
+	}
+	return revisions, nil
+}
+
+// TarantoolHistoryReader reads revisions of a configuration key from
+// a history kept by tt in the tarantool config storage. Each publishing of
+// tt stores a history record with the published value and a modification
+// time.
+type TarantoolHistoryReader struct {
+	conn    tarantool.Doer
+	prefix  string
+	key     string
+	timeout time.Duration
+}
+
+// NewTarantoolHistoryReader creates a new TarantoolHistoryReader object to
+// read revisions of the key from the prefix during the timeout.
+func NewTarantoolHistoryReader(conn tarantool.Doer,
+	prefix, key string, timeout time.Duration,
+) TarantoolHistoryReader {
+	return TarantoolHistoryReader{
+		conn:    conn,
+		prefix:  prefix,
+		key:     key,
+		timeout: timeout,
+	}
+}
+
+// History returns revisions of the key from the newest to the oldest.
+func (reader TarantoolHistoryReader) History(limit int) ([]KeyRevision, error) {
+	key := getConfigKey(reader.prefix, reader.key)
+	historyPrefix := getHistoryPrefix(reader.prefix, reader.key)
+	resp, err := tarantoolTxnGetRevisions(reader.conn, []string{key, historyPrefix},
+		reader.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		current *KeyRevision
+		records []historyRecord
+	)
+	for _, data := range resp.Data.Responses[0] {
+		current = &KeyRevision{Revision: data.ModRevision, Value: []byte(data.Value)}
+	}
+	for _, data := range resp.Data.Responses[1] {
+		records = append(records, historyRecord{
+			Path:     data.Path,
+			Revision: data.ModRevision,
+			Value:    []byte(data.Value),
+		})
+	}
+	if current == nil {
+		return nil, fmt.Errorf("a configuration data not found in tarantool for key %q", key)
+	}
+	return makeHistory(historyPrefix, *current, records, limit), nil
+}
+
+// historyRecord is a history record of a key kept by tt in a storage.
+type historyRecord struct {
+	// Path is a full path of the record.
+	Path string
+	// Revision is a modification revision of the record.
+	Revision int64
+	// Value is a value of the key.
+	Value []byte
+}
+
+// makeHistory returns revisions of a key from the newest to the oldest made
+// from the history records kept by tt with the prefix and the current value
+// of the key. Zero limit means no limit.
+func makeHistory(historyPrefix string, current KeyRevision, records []historyRecord,
+	limit int,
+) []KeyRevision {
+	revisions := make([]KeyRevision, 0, len(records)+1)
+	for _, record := range records {
+		revision := KeyRevision{Revision: record.Revision, Value: record.Value}
+		nsec, err := strconv.ParseInt(strings.TrimPrefix(record.Path, historyPrefix), 10, 64)
+		if err == nil {
+			revision.Time = time.Unix(0, nsec)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	slices.SortFunc(revisions, func(a, b KeyRevision) int {
+		return cmp.Compare(b.Revision, a.Revision)
+	})
+	// The current value could be published without tt.
+	if len(revisions) == 0 || revisions[0].Revision != current.Revision {
+		revisions = append([]KeyRevision{current}, revisions...)
+	}
+	if limit != 0 && len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions
+}
+
+// tarantoolTxnGetRevisionsResponse is a response of a txn get requests with
+// modification revisions.
+type tarantoolTxnGetRevisionsResponse struct {
+	Data struct {
+		Responses [][]struct {
+			Path        string
+			Value       string
+			ModRevision int64 `mapstructure:"mod_revision"`
+		}
+	}
+}
+
+// tarantoolTxnGetRevisions returns a data with modification revisions from
+// a tarantool config storage for a set of prefixes and keys.
+func tarantoolTxnGetRevisions(conn tarantool.Doer,
+	paths []string, timeout time.Duration,
+) (tarantoolTxnGetRevisionsResponse, error) {
+	resp := tarantoolTxnGetRevisionsResponse{}
+
+	onSuccess := []any{}
+	for _, path := range paths {
+		onSuccess = append(onSuccess, []any{"get", path})
+	}
+	args := []any{
+		map[any]any{
+			"on_success": onSuccess,
+		},
+	}
+
+	data, err := tarantoolCall(conn, "config.storage.txn", args, timeout)
+	if err != nil {
+		return resp, fmt.Errorf("failed to fetch data from tarantool: %w", err)
+	}
+	if len(data) != 1 {
+		return resp, fmt.Errorf("unexpected response from tarantool: %q", data)
+	}
+	if err := mapstructure.Decode(data[0], &resp); err != nil {
+		return resp, fmt.Errorf("failed to map response from tarantool: %q", data[0])
+	}
+	if len(resp.Data.Responses) != len(paths) {
+		return resp, fmt.Errorf("unexpected response from tarantool: %q", data[0])
+	}
+	return resp, nil
+}
+
+// FindRevision returns the revision with the number from the list.
+func FindRevision(revisions []KeyRevision, revision int64) (KeyRevision, bool) {
+	for _, rev := range revisions {
+		if rev.Revision == revision {
+			return rev, true
+		}
+	}
+	return KeyRevision{}, false
+}
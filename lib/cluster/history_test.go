@@ -0,0 +1,157 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/tarantool/go-tarantool/v2/test_helpers"
+	"github.com/tarantool/tt/lib/cluster"
+)
+
+// historyEtcdGetter returns values of a key at revisions.
+type historyEtcdGetter struct {
+	// values are values of the key by modification revisions.
+	values map[int64]string
+	// compacted is a compaction revision.
+	compacted int64
+	// latest is the latest revision of the storage.
+	latest int64
+}
+
+func (getter historyEtcdGetter) Get(ctx context.Context, key string,
+	opts ...clientv3.OpOption,
+) (*clientv3.GetResponse, error) {
+	revision := clientv3.OpGet(key, opts...).Rev()
+	if revision == 0 {
+		revision = getter.latest
+	}
+	if revision <= getter.compacted {
+		return nil, rpctypes.ErrCompacted
+	}
+
+	resp := &clientv3.GetResponse{}
+	for rev := revision; rev > 0; rev-- {
+		if value, ok := getter.values[rev]; ok {
+			resp.Kvs = []*mvccpb.KeyValue{
+				{Key: []byte(key), Value: []byte(value), ModRevision: rev},
+			}
+			break
+		}
+	}
+	return resp, nil
+}
+
+func TestEtcdHistoryReader_History(t *testing.T) {
+	getter := historyEtcdGetter{
+		values: map[int64]string{2: "a", 5: "b", 9: "c"},
+		latest: 12,
+	}
+	reader := cluster.NewEtcdHistoryReader(getter, "/prefix", "all", time.Second)
+
+	revisions, err := reader.History(0)
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.KeyRevision{
+		{Revision: 9, Value: []byte("c")},
+		{Revision: 5, Value: []byte("b")},
+		{Revision: 2, Value: []byte("a")},
+	}, revisions)
+
+	revisions, err = reader.History(2)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+
+	getter.compacted = 4
+	revisions, err = cluster.NewEtcdHistoryReader(getter, "/prefix", "all", 0).History(0)
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.KeyRevision{
+		{Revision: 9, Value: []byte("c")},
+		{Revision: 5, Value: []byte("b")},
+	}, revisions)
+
+	_, err = cluster.NewEtcdHistoryReader(historyEtcdGetter{latest: 3}, "/prefix", "all",
+		0).History(0)
+	assert.EqualError(t, err,
+		`a configuration data not found in etcd for key "/prefix/config/all"`)
+
+	_, err = cluster.NewEtcdHistoryReader(&MockEtcdGetter{Err: errors.New("foo")},
+		"/prefix", "all", 0).History(0)
+	assert.EqualError(t, err, "failed to fetch data from etcd: foo")
+}
+
+func tarantoolTxnResponse(t *testing.T, responses ...[]map[string]any) *test_helpers.MockResponse {
+	return test_helpers.NewMockResponse(t, []any{
+		map[string]any{
+			"data": map[string]any{"responses": responses},
+		},
+	})
+}
+
+func TestTarantoolHistoryReader_History(t *testing.T) {
+	first := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	history := []map[string]any{
+		{
+			"path":         "/prefix/history/all/" + "1792317600000000000",
+			"value":        "a",
+			"mod_revision": 3,
+		},
+		{
+			"path":         "/prefix/history/all/" + "1792321200000000000",
+			"value":        "b",
+			"mod_revision": 7,
+		},
+	}
+
+	doer := test_helpers.NewMockDoer(t,
+		tarantoolTxnResponse(t,
+			[]map[string]any{{"path": "/prefix/config/all", "value": "b", "mod_revision": 7}},
+			history),
+	)
+	revisions, err := cluster.NewTarantoolHistoryReader(&doer, "/prefix", "all",
+		time.Second).History(0)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(7), revisions[0].Revision)
+	assert.True(t, second.Equal(revisions[0].Time))
+	assert.Equal(t, []byte("b"), revisions[0].Value)
+	assert.Equal(t, int64(3), revisions[1].Revision)
+	assert.True(t, first.Equal(revisions[1].Time))
+
+	// The current value is published without tt.
+	doer = test_helpers.NewMockDoer(t,
+		tarantoolTxnResponse(t,
+			[]map[string]any{{"path": "/prefix/config/all", "value": "c", "mod_revision": 9}},
+			history),
+	)
+	revisions, err = cluster.NewTarantoolHistoryReader(&doer, "/prefix", "all",
+		0).History(2)
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.KeyRevision{
+		{Revision: 9, Value: []byte("c")},
+		{Revision: 7, Time: revisions[1].Time, Value: []byte("b")},
+	}, revisions)
+
+	doer = test_helpers.NewMockDoer(t,
+		tarantoolTxnResponse(t, []map[string]any{}, []map[string]any{}))
+	_, err = cluster.NewTarantoolHistoryReader(&doer, "/prefix", "all", 0).History(0)
+	assert.EqualError(t, err,
+		`a configuration data not found in tarantool for key "/prefix/config/all"`)
+}
+
+func TestFindRevision(t *testing.T) {
+	revisions := []cluster.KeyRevision{{Revision: 3}, {Revision: 1}}
+	revision, found := cluster.FindRevision(revisions, 1)
+	assert.True(t, found)
+	assert.Equal(t, cluster.KeyRevision{Revision: 1}, revision)
+
+	_, found = cluster.FindRevision(revisions, 2)
+	assert.False(t, found)
+}
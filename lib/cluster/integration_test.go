@@ -23,6 +23,7 @@ import (
 	tcs_helper "github.com/tarantool/go-tarantool/v2/test_helpers/tcs"
 
 	"github.com/tarantool/tt/lib/cluster"
+	libconnect "github.com/tarantool/tt/lib/connect"
 )
 
 const timeout = 5 * time.Second
@@ -349,6 +350,38 @@ func TestEtcdAllCollector_merge(t *testing.T) {
 	assert.Equal(t, "car", value)
 }
 
+func TestEtcdCSConnection_Get_prefix(t *testing.T) {
+	inst := startEtcd(t, etcdOpts{})
+	defer inst.Terminate()
+
+	endpoints := inst.EndpointsGRPC()
+	conn, err := cluster.ConnectCStorage(libconnect.UriOpts{
+		Endpoint: endpoints[0],
+		Timeout:  timeout,
+	}, cluster.ConnectOpts{})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	require.NoError(t, conn.Put(ctx, "/foo/config/a", "foo: bar"))
+	require.NoError(t, conn.Put(ctx, "/foo/config/b", "zoo: car"))
+	require.NoError(t, conn.Put(ctx, "/foo/hashes/a", "hash"))
+
+	data, err := conn.Get(ctx, "/foo/config/")
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, "/foo/config/a", data[0].Source)
+	assert.Equal(t, []byte("foo: bar"), data[0].Value)
+	assert.Equal(t, "/foo/config/b", data[1].Source)
+	assert.Equal(t, []byte("zoo: car"), data[1].Value)
+
+	data, err = conn.Get(ctx, "/bar/config/")
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
 func TestEtcdCollectors_empty(t *testing.T) {
 	inst := startEtcd(t, etcdOpts{})
 	defer inst.Terminate()
@@ -0,0 +1,537 @@
+package cluster
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LintSeverity is a severity of a lint issue.
+type LintSeverity string
+
+const (
+	// LintError is a severity of issues that break the cluster.
+	LintError LintSeverity = "error"
+	// LintWarning is a severity of issues that are likely mistakes.
+	LintWarning LintSeverity = "warning"
+)
+
+// Identifiers of lint rules.
+const (
+	LintRuleDuplicateListen     = "duplicate-listen"
+	LintRuleUnreachableInstance = "unreachable-instance"
+	LintRuleReplicasetLeader    = "replicaset-leader"
+	LintRuleShardingRoles       = "sharding-roles"
+	LintRuleUnknownRole         = "unknown-role"
+	LintRuleMissingCredential   = "missing-credential"
+	LintRuleBucketCount         = "bucket-count"
+)
+
+// LintRule describes a rule of the linter.
+type LintRule struct {
+	// ID is an identifier of the rule.
+	ID string
+	// Description is a short description of the rule.
+	Description string
+}
+
+// LintRules is a list of rules checked by Lint.
+var LintRules = []LintRule{
+	{
+		ID:          LintRuleDuplicateListen,
+		Description: "instances must not listen on the same iproto URI",
+	},
+	{
+		ID:          LintRuleUnreachableInstance,
+		Description: "instances must have a URI reachable by other instances",
+	},
+	{
+		ID:          LintRuleReplicasetLeader,
+		Description: "replicasets must have a single leader according to the failover mode",
+	},
+	{
+		ID:          LintRuleShardingRoles,
+		Description: "vshard storages and routers must be configured with sharding.roles",
+	},
+	{
+		ID:          LintRuleUnknownRole,
+		Description: "roles must be known",
+	},
+	{
+		ID:          LintRuleMissingCredential,
+		Description: "credentials must reference existing users and roles",
+	},
+	{
+		ID:          LintRuleBucketCount,
+		Description: "sharding.bucket_count must be the same in the cluster",
+	},
+}
+
+// LintIssue is an issue found by the linter.
+type LintIssue struct {
+	// Rule is an identifier of the violated rule.
+	Rule string `json:"rule"`
+	// Severity is a severity of the issue.
+	Severity LintSeverity `json:"severity"`
+	// Path is a configuration path of the issue.
+	Path []string `json:"path"`
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// LintOpts describes options of the linter.
+type LintOpts struct {
+	// AppDir is an application directory to look for modules of roles.
+	// Modules are not checked if it is empty.
+	AppDir string
+}
+
+const (
+	// lintDefaultBucketCount is a default value of sharding.bucket_count.
+	lintDefaultBucketCount = 3000
+	// lintManualFailover is a failover mode with a leader.
+	lintManualFailover = "manual"
+	// lintOffFailover is a failover mode with database.mode.
+	lintOffFailover = "off"
+)
+
+var (
+	// lintBuiltinRoles are roles provided by tarantool.
+	lintBuiltinRoles = []string{"config.storage"}
+	// lintShardingRoles are allowed values of sharding.roles.
+	lintShardingRoles = []string{"storage", "router", "rebalancer"}
+	// lintBuiltinUsers are users created by tarantool.
+	lintBuiltinUsers = []string{"admin", "guest"}
+	// lintBuiltinCredentialRoles are roles created by tarantool.
+	lintBuiltinCredentialRoles = []string{"super", "public", "replication", "sharding"}
+	// lintTemplateRe matches variables supported in configuration values.
+	lintTemplateRe = regexp.MustCompile(
+		`\{\{\s*(instance_name|replicaset_name|group_name)\s*\}\}`)
+)
+
+// lintInstance is an instance to lint.
+type lintInstance struct {
+	group, replicaset, name string
+	// config is an instance configuration merged from all scopes.
+	config *Config
+}
+
+// linter checks a cluster configuration.
+type linter struct {
+	cconfig   ClusterConfig
+	opts      LintOpts
+	instances []lintInstance
+	issues    []LintIssue
+}
+
+// Lint checks semantics of a cluster configuration and returns found issues
+// sorted by paths.
+func Lint(cconfig ClusterConfig, opts LintOpts) []LintIssue {
+	l := linter{cconfig: cconfig, opts: opts}
+	for _, gname := range slices.Sorted(maps.Keys(cconfig.Groups)) {
+		group := cconfig.Groups[gname]
+		for _, rname := range slices.Sorted(maps.Keys(group.Replicasets)) {
+			replicaset := group.Replicasets[rname]
+			for _, iname := range slices.Sorted(maps.Keys(replicaset.Instances)) {
+				l.instances = append(l.instances, lintInstance{
+					group:      gname,
+					replicaset: rname,
+					name:       iname,
+					config:     Instantiate(cconfig, iname),
+				})
+			}
+		}
+	}
+
+	l.lintListen()
+	l.lintReachability()
+	l.lintLeaders()
+	l.lintSharding()
+	l.lintRoles()
+	l.lintCredentials()
+	l.lintBucketCount()
+
+	slices.SortStableFunc(l.issues, func(a, b LintIssue) int {
+		return strings.Compare(strings.Join(a.Path, "."), strings.Join(b.Path, "."))
+	})
+	return slices.CompactFunc(l.issues, func(a, b LintIssue) bool {
+		return a.Rule == b.Rule && slices.Equal(a.Path, b.Path) && a.Message == b.Message
+	})
+}
+
+// report adds an issue.
+func (l *linter) report(rule string, severity LintSeverity, path []string,
+	format string, args ...any,
+) {
+	l.issues = append(l.issues, LintIssue{
+		Rule:     rule,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// replicasetPath returns a path of the replicaset scope.
+func replicasetPath(group, replicaset string, path ...string) []string {
+	return append([]string{groupsLabel, group, replicasetsLabel, replicaset}, path...)
+}
+
+// scopePath returns a path of the most specific scope where the instance
+// option is defined. It returns a path of the instance scope if the option is
+// not defined.
+func (l *linter) scopePath(inst lintInstance, path ...string) []string {
+	scopes := [][]string{
+		replicasetPath(inst.group, inst.replicaset, instancesLabel, inst.name),
+		replicasetPath(inst.group, inst.replicaset),
+		{groupsLabel, inst.group},
+		{},
+	}
+	for _, scope := range scopes {
+		full := append(slices.Clone(scope), path...)
+		if _, err := l.cconfig.RawConfig.Get(full); err == nil {
+			return full
+		}
+	}
+	return append(scopes[0], path...)
+}
+
+// lintString returns a string value of the path or an empty string.
+func lintString(config *Config, path ...string) string {
+	value, _ := config.Get(path)
+	str, _ := value.(string)
+	return str
+}
+
+// lintStrings returns string values of a list by the path.
+func lintStrings(config *Config, path ...string) []string {
+	value, _ := config.Get(path)
+	list, _ := value.([]any)
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+// lintListenUris returns iproto.listen URIs of the instance with substituted
+// variables.
+func lintListenUris(inst lintInstance) []string {
+	value, _ := inst.config.Get([]string{"iproto", "listen"})
+	list, _ := value.([]any)
+	var uris []string
+	for _, item := range list {
+		listen, _ := item.(map[any]any)
+		uri, _ := listen["uri"].(string)
+		if uri == "" {
+			continue
+		}
+		uri = lintTemplateRe.ReplaceAllStringFunc(uri, func(match string) string {
+			switch lintTemplateRe.FindStringSubmatch(match)[1] {
+			case "instance_name":
+				return inst.name
+			case "replicaset_name":
+				return inst.replicaset
+			default:
+				return inst.group
+			}
+		})
+		uris = append(uris, strings.TrimSpace(uri))
+	}
+	return uris
+}
+
+// lintListen checks that instances do not listen on the same URI.
+func (l *linter) lintListen() {
+	owners := map[string][]string{}
+	for _, inst := range l.instances {
+		for _, uri := range lintListenUris(inst) {
+			// A zero port is chosen by a system.
+			if strings.HasSuffix(uri, ":0") || slices.Contains(owners[uri], inst.name) {
+				continue
+			}
+			owners[uri] = append(owners[uri], inst.name)
+		}
+	}
+
+	for _, inst := range l.instances {
+		for _, uri := range lintListenUris(inst) {
+			if len(owners[uri]) < 2 {
+				continue
+			}
+			l.report(LintRuleDuplicateListen, LintError,
+				l.scopePath(inst, "iproto", "listen"),
+				"URI %q is used by instances: %s", uri, strings.Join(owners[uri], ", "))
+		}
+	}
+}
+
+// lintReachability checks that instances have URIs to connect to.
+func (l *linter) lintReachability() {
+	for _, inst := range l.instances {
+		uri := lintString(inst.config, "iproto", "advertise", "peer", "uri")
+		if uri != "" {
+			continue
+		}
+		uris := lintListenUris(inst)
+		if len(uris) == 0 {
+			l.report(LintRuleUnreachableInstance, LintWarning,
+				l.scopePath(inst, "iproto", "listen"),
+				"instance %q has no URI to connect: set iproto.listen", inst.name)
+			continue
+		}
+		host, _, err := net.SplitHostPort(uris[0])
+		if err == nil && (host == "0.0.0.0" || host == "::") {
+			l.report(LintRuleUnreachableInstance, LintError,
+				l.scopePath(inst, "iproto", "listen"),
+				"instance %q listens on a wildcard address %q that can not be used "+
+					"to connect: set iproto.advertise.peer.uri", inst.name, host)
+		}
+	}
+}
+
+// lintLeaders checks leaders of replicasets according to failover modes.
+func (l *linter) lintLeaders() {
+	for _, instances := range l.replicasets() {
+		first := instances[0]
+		path := replicasetPath(first.group, first.replicaset)
+		failover := lintString(first.config, "replication", "failover")
+		leader := lintString(first.config, "leader")
+
+		var rw []string
+		for _, inst := range instances {
+			mode := lintString(inst.config, "database", "mode")
+			if mode == "rw" || mode == "" && len(instances) == 1 &&
+				failover != lintManualFailover {
+				rw = append(rw, inst.name)
+			}
+		}
+
+		switch failover {
+		case lintManualFailover:
+			if leader == "" {
+				l.report(LintRuleReplicasetLeader, LintError, path,
+					"replicaset %q has no leader with the manual failover", first.replicaset)
+				break
+			}
+			if !slices.ContainsFunc(instances, func(inst lintInstance) bool {
+				return inst.name == leader
+			}) {
+				l.report(LintRuleReplicasetLeader, LintError, append(path, "leader"),
+					"leader %q is not an instance of replicaset %q",
+					leader, first.replicaset)
+			}
+			for _, inst := range instances {
+				if slices.Contains(rw, inst.name) && inst.name != leader {
+					l.report(LintRuleReplicasetLeader, LintError,
+						l.scopePath(inst, "database", "mode"),
+						"instance %q is rw, but the leader of replicaset %q is %q",
+						inst.name, first.replicaset, leader)
+				}
+			}
+		case lintOffFailover, "":
+			if leader != "" {
+				l.report(LintRuleReplicasetLeader, LintError, append(path, "leader"),
+					"leader is supported only with the manual failover")
+			}
+			if len(rw) > 1 {
+				l.report(LintRuleReplicasetLeader, LintWarning, path,
+					"replicaset %q has multiple rw instances: %s",
+					first.replicaset, strings.Join(rw, ", "))
+			} else if len(rw) == 0 {
+				l.report(LintRuleReplicasetLeader, LintWarning, path,
+					"replicaset %q has no rw instance, set database.mode", first.replicaset)
+			}
+		default:
+			if leader != "" {
+				l.report(LintRuleReplicasetLeader, LintError, append(path, "leader"),
+					"leader is supported only with the manual failover")
+			}
+		}
+	}
+}
+
+// replicasets returns instances grouped by replicasets.
+func (l *linter) replicasets() [][]lintInstance {
+	var replicasets [][]lintInstance
+	for i, inst := range l.instances {
+		if i == 0 || l.instances[i-1].group != inst.group ||
+			l.instances[i-1].replicaset != inst.replicaset {
+			replicasets = append(replicasets, nil)
+		}
+		last := len(replicasets) - 1
+		replicasets[last] = append(replicasets[last], inst)
+	}
+	return replicasets
+}
+
+// lintSharding checks that vshard storages and routers are configured with
+// sharding.roles.
+func (l *linter) lintSharding() {
+	var storages, routers bool
+	for _, instances := range l.replicasets() {
+		first := instances[0]
+		expected := lintStrings(first.config, "sharding", "roles")
+		for _, inst := range instances {
+			roles := lintStrings(inst.config, "sharding", "roles")
+			if !slices.Equal(roles, expected) {
+				l.report(LintRuleShardingRoles, LintError,
+					l.scopePath(inst, "sharding", "roles"),
+					"sharding.roles of instance %q differ from other instances of "+
+						"replicaset %q", inst.name, inst.replicaset)
+			}
+			storages = storages || slices.Contains(roles, "storage")
+			routers = routers || slices.Contains(roles, "router")
+
+			appRoles := lintStrings(inst.config, "roles")
+			if slices.Contains(appRoles, "roles.crud-storage") &&
+				!slices.Contains(roles, "storage") {
+				l.report(LintRuleShardingRoles, LintError, l.scopePath(inst, "roles"),
+					"instance %q has the roles.crud-storage role, but it is not "+
+						"a vshard storage: add storage to sharding.roles", inst.name)
+			}
+			if slices.Contains(appRoles, "roles.crud-router") &&
+				!slices.Contains(roles, "router") {
+				l.report(LintRuleShardingRoles, LintError, l.scopePath(inst, "roles"),
+					"instance %q has the roles.crud-router role, but it is not "+
+						"a vshard router: add router to sharding.roles", inst.name)
+			}
+		}
+	}
+
+	if routers && !storages {
+		l.report(LintRuleShardingRoles, LintError, []string{groupsLabel},
+			"the cluster has vshard routers, but no storages: add storage to "+
+				"sharding.roles of storage replicasets")
+	}
+	if storages && !routers {
+		l.report(LintRuleShardingRoles, LintWarning, []string{groupsLabel},
+			"the cluster has vshard storages, but no routers")
+	}
+}
+
+// hasRoleModule returns true if a module of the role exists in
+// the application directory.
+func (l *linter) hasRoleModule(role string) bool {
+	module := filepath.Join(strings.Split(role, ".")...)
+	for _, dir := range []string{
+		l.opts.AppDir,
+		filepath.Join(l.opts.AppDir, ".rocks", "share", "tarantool"),
+	} {
+		for _, path := range []string{
+			filepath.Join(dir, module+".lua"),
+			filepath.Join(dir, module, "init.lua"),
+		} {
+			if _, err := os.Stat(path); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lintRoles checks that roles are known.
+func (l *linter) lintRoles() {
+	enabled := map[string]bool{}
+	for _, inst := range l.instances {
+		for _, role := range lintStrings(inst.config, "roles") {
+			enabled[role] = true
+			if l.opts.AppDir == "" || slices.Contains(lintBuiltinRoles, role) ||
+				l.hasRoleModule(role) {
+				continue
+			}
+			l.report(LintRuleUnknownRole, LintWarning, l.scopePath(inst, "roles"),
+				"module of role %q is not found in the application", role)
+		}
+
+		for _, role := range lintStrings(inst.config, "sharding", "roles") {
+			if !slices.Contains(lintShardingRoles, role) {
+				l.report(LintRuleUnknownRole, LintError,
+					l.scopePath(inst, "sharding", "roles"),
+					"unknown sharding role %q, expected one of: %s",
+					role, strings.Join(lintShardingRoles, ", "))
+			}
+		}
+	}
+
+	for _, inst := range l.instances {
+		configured, _ := inst.config.Elems([]string{"roles_cfg"})
+		slices.Sort(configured)
+		for _, role := range configured {
+			if !enabled[role] {
+				l.report(LintRuleUnknownRole, LintWarning,
+					l.scopePath(inst, "roles_cfg", role),
+					"role %q is configured, but it is not enabled for any instance", role)
+			}
+		}
+	}
+}
+
+// lintCredentials checks that credentials reference existing users and
+// roles.
+func (l *linter) lintCredentials() {
+	for _, inst := range l.instances {
+		users, _ := inst.config.Elems([]string{"credentials", "users"})
+		roles, _ := inst.config.Elems([]string{"credentials", "roles"})
+		users = append(users, lintBuiltinUsers...)
+		roles = append(roles, lintBuiltinCredentialRoles...)
+
+		for _, target := range []string{"peer", "sharding"} {
+			path := []string{"iproto", "advertise", target, "login"}
+			login := lintString(inst.config, path...)
+			if login != "" && !slices.Contains(users, login) {
+				l.report(LintRuleMissingCredential, LintError, l.scopePath(inst, path...),
+					"user %q is not found in credentials.users", login)
+			}
+		}
+
+		for _, kind := range []string{"users", "roles"} {
+			names, _ := inst.config.Elems([]string{"credentials", kind})
+			slices.Sort(names)
+			for _, name := range names {
+				path := []string{"credentials", kind, name, "roles"}
+				for _, role := range lintStrings(inst.config, path...) {
+					if !slices.Contains(roles, role) {
+						l.report(LintRuleMissingCredential, LintError,
+							l.scopePath(inst, path...),
+							"role %q is not found in credentials.roles", role)
+					}
+				}
+			}
+		}
+	}
+}
+
+// lintBucketCount checks that sharding.bucket_count is the same for all
+// vshard storages and routers.
+func (l *linter) lintBucketCount() {
+	var (
+		expected any
+		source   string
+	)
+	for _, inst := range l.instances {
+		if len(lintStrings(inst.config, "sharding", "roles")) == 0 {
+			continue
+		}
+		count, err := inst.config.Get([]string{"sharding", "bucket_count"})
+		if err != nil {
+			count = lintDefaultBucketCount
+		}
+		if expected == nil {
+			expected, source = count, inst.name
+			continue
+		}
+		if fmt.Sprint(count) != fmt.Sprint(expected) {
+			l.report(LintRuleBucketCount, LintError,
+				l.scopePath(inst, "sharding", "bucket_count"),
+				"sharding.bucket_count %v of instance %q differs from %v of instance %q",
+				count, inst.name, expected, source)
+		}
+	}
+}
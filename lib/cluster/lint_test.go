@@ -0,0 +1,191 @@
+package cluster_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/lib/cluster"
+)
+
+func lintYaml(t *testing.T, data string, opts cluster.LintOpts) []cluster.LintIssue {
+	t.Helper()
+
+	cconfig, err := cluster.MakeClusterConfig(mustCollectYaml(t, data))
+	require.NoError(t, err)
+	return cluster.Lint(cconfig, opts)
+}
+
+func TestLint_valid(t *testing.T) {
+	appDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "roles"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "roles", "app.lua"), nil, 0o644))
+
+	issues := lintYaml(t, `
+credentials:
+  users:
+    replicator:
+      roles: [replication]
+    storage:
+      roles: [sharding]
+iproto:
+  listen:
+  - uri: localhost:{{ instance_name }}
+  advertise:
+    peer:
+      login: replicator
+    sharding:
+      login: storage
+sharding:
+  bucket_count: 1000
+groups:
+  routers:
+    sharding:
+      roles: [router]
+    roles: [roles.app]
+    roles_cfg:
+      roles.app:
+        foo: bar
+    replicasets:
+      r-001:
+        instances:
+          router-001: {}
+  storages:
+    replication:
+      failover: manual
+    sharding:
+      roles: [storage]
+    replicasets:
+      s-001:
+        leader: s-001-a
+        instances:
+          s-001-a: {}
+          s-001-b: {}
+      s-002:
+        leader: s-002-a
+        instances:
+          s-002-a: {}
+`, cluster.LintOpts{AppDir: appDir})
+	assert.Empty(t, issues)
+}
+
+func TestLint(t *testing.T) {
+	issues := lintYaml(t, `
+credentials:
+  users:
+    replicator:
+      roles: [replication, missing]
+iproto:
+  advertise:
+    peer:
+      login: unknown
+groups:
+  routers:
+    sharding:
+      roles: [router, master]
+    roles: [roles.crud-router, roles.app]
+    roles_cfg:
+      roles.other: {}
+    replicasets:
+      r-001:
+        instances:
+          router-001:
+            iproto:
+              listen:
+              - uri: 0.0.0.0:3301
+            sharding:
+              bucket_count: 100
+  storages:
+    replicasets:
+      s-001:
+        replication:
+          failover: manual
+        roles: [roles.crud-storage]
+        instances:
+          s-001-a:
+            iproto:
+              listen:
+              - uri: localhost:3302
+          s-001-b:
+            iproto:
+              listen:
+              - uri: localhost:3302
+      s-002:
+        leader: s-002-a
+        instances:
+          s-002-a:
+            database:
+              mode: rw
+            iproto:
+              listen:
+              - uri: localhost:3303
+          s-002-b:
+            database:
+              mode: rw
+            sharding:
+              roles: [storage]
+            iproto:
+              listen:
+              - uri: localhost:3304
+`, cluster.LintOpts{AppDir: t.TempDir()})
+
+	type issue struct {
+		Rule     string
+		Severity cluster.LintSeverity
+		Path     string
+	}
+	actual := []issue{}
+	for _, i := range issues {
+		actual = append(actual, issue{i.Rule, i.Severity, cluster.FormatDiffPath(i.Path)})
+		assert.NotEmpty(t, i.Message)
+	}
+
+	assert.Equal(t, []issue{
+		{cluster.LintRuleMissingCredential, cluster.LintError,
+			"credentials.users.replicator.roles"},
+		{cluster.LintRuleUnreachableInstance, cluster.LintError,
+			"groups.routers.replicasets.r-001.instances.router-001.iproto.listen"},
+		{cluster.LintRuleUnknownRole, cluster.LintWarning, "groups.routers.roles"},
+		{cluster.LintRuleUnknownRole, cluster.LintWarning, "groups.routers.roles"},
+		{cluster.LintRuleUnknownRole, cluster.LintWarning, "groups.routers.roles_cfg.roles.other"},
+		{cluster.LintRuleUnknownRole, cluster.LintError, "groups.routers.sharding.roles"},
+		{cluster.LintRuleReplicasetLeader, cluster.LintError,
+			"groups.storages.replicasets.s-001"},
+		{cluster.LintRuleDuplicateListen, cluster.LintError,
+			"groups.storages.replicasets.s-001.instances.s-001-a.iproto.listen"},
+		{cluster.LintRuleDuplicateListen, cluster.LintError,
+			"groups.storages.replicasets.s-001.instances.s-001-b.iproto.listen"},
+		{cluster.LintRuleShardingRoles, cluster.LintError,
+			"groups.storages.replicasets.s-001.roles"},
+		{cluster.LintRuleShardingRoles, cluster.LintError,
+			"groups.storages.replicasets.s-001.roles"},
+		{cluster.LintRuleUnknownRole, cluster.LintWarning,
+			"groups.storages.replicasets.s-001.roles"},
+		{cluster.LintRuleReplicasetLeader, cluster.LintWarning,
+			"groups.storages.replicasets.s-002"},
+		{cluster.LintRuleBucketCount, cluster.LintError,
+			"groups.storages.replicasets.s-002.instances.s-002-b.sharding.bucket_count"},
+		{cluster.LintRuleShardingRoles, cluster.LintError,
+			"groups.storages.replicasets.s-002.instances.s-002-b.sharding.roles"},
+		{cluster.LintRuleReplicasetLeader, cluster.LintError,
+			"groups.storages.replicasets.s-002.leader"},
+		{cluster.LintRuleMissingCredential, cluster.LintError,
+			"iproto.advertise.peer.login"},
+	}, actual)
+
+	issues = lintYaml(t, `
+groups:
+  g:
+    replicasets:
+      r:
+        instances:
+          i:
+            iproto:
+              listen:
+              - uri: localhost:3301
+`, cluster.LintOpts{})
+	assert.Empty(t, issues)
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// varRe matches an escaped "$${" or a "${NAME}" variable reference.
+var varRe = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// VarLookupFunc returns a value of a variable and true if the variable is
+// defined.
+type VarLookupFunc func(name string) (string, bool)
+
+// expandVarsString replaces variable references in the string. Names of
+// undefined variables are appended to the list.
+func expandVarsString(str string, lookup VarLookupFunc, undefined *[]string) string {
+	return varRe.ReplaceAllStringFunc(str, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		name := match[2 : len(match)-1]
+		value, ok := lookup(name)
+		if !ok {
+			*undefined = append(*undefined, name)
+			return match
+		}
+		return value
+	})
+}
+
+// expandVarsValue replaces variable references in strings of the value.
+func expandVarsValue(value any, lookup VarLookupFunc, undefined *[]string) any {
+	switch v := value.(type) {
+	case string:
+		return expandVarsString(v, lookup, undefined)
+	case map[any]any:
+		expanded := make(map[any]any, len(v))
+		for key, elem := range v {
+			expanded[key] = expandVarsValue(elem, lookup, undefined)
+		}
+		return expanded
+	case []any:
+		expanded := make([]any, len(v))
+		for i, elem := range v {
+			expanded[i] = expandVarsValue(elem, lookup, undefined)
+		}
+		return expanded
+	}
+	return value
+}
+
+// ExpandVars returns a copy of the configuration with "${NAME}" references in
+// string values replaced by values of the variables. A value is substituted
+// as is and the result is a string, so it could not change the structure or
+// the type of the configuration. A "$${" sequence is replaced with "${" and
+// could be used to keep a reference as is. It returns an error if some of
+// the variables are not defined. The configuration is not modified.
+func ExpandVars(config *Config, lookup VarLookupFunc) (*Config, error) {
+	var undefined []string
+	paths := expandVarsValue(config.paths, lookup, &undefined)
+	if len(undefined) != 0 {
+		slices.Sort(undefined)
+		undefined = slices.Compact(undefined)
+		return nil, fmt.Errorf("undefined variables: %s", strings.Join(undefined, ", "))
+	}
+	return &Config{paths: paths}, nil
+}
+
+// MergeOverlays merges the overlays on top of the base configuration. An
+// overlay has a higher priority than the base configuration and previous
+// overlays. Values, including lists, are replaced as a whole while maps are
+// merged recursively. The base configuration is not modified.
+func MergeOverlays(base *Config, overlays ...*Config) *Config {
+	config := NewConfig()
+	for _, overlay := range slices.Backward(overlays) {
+		config.Merge(overlay)
+	}
+	config.Merge(base)
+	return config
+}
@@ -0,0 +1,120 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/lib/cluster"
+)
+
+func TestExpandVars(t *testing.T) {
+	vars := map[string]string{
+		"HOST":    "localhost",
+		"PORT":    "3301",
+		"EMPTY":   "",
+		"BOOL":    "yes",
+		"COLON":   "a: b # c",
+		"NEWLINE": "line1\nline2",
+		"ANCHOR":  "*ref",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	cases := []struct {
+		data     string
+		expected string
+		err      string
+	}{
+		{"a: b", "a: b\n", ""},
+		{"uri: ${HOST}:${PORT}", "uri: localhost:3301\n", ""},
+		{"a: '${EMPTY}'", "a: \"\"\n", ""},
+		{"a: $${HOST}", "a: ${HOST}\n", ""},
+		{"a: $HOST {HOST} ${ HOST} ${1A}", "a: $HOST {HOST} ${ HOST} ${1A}\n", ""},
+		// Values do not change types and the structure.
+		{"a: ${PORT}\nb: ${BOOL}", "a: \"3301\"\nb: \"yes\"\n", ""},
+		{"a: ${COLON}\nb:\n- ${ANCHOR}", "a: 'a: b # c'\nb:\n  - '*ref'\n", ""},
+		{"a:\n  b: ${NEWLINE}", "a:\n  b: |-\n    line1\n    line2\n", ""},
+		// Keys and comments are kept as is.
+		{"${HOST}: a # ${UNDEFINED}", "${HOST}: a\n", ""},
+		{"a: ${X} ${HOST} ${B}\nb:\n- ${X}", "", "undefined variables: B, X"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.data, func(t *testing.T) {
+			config, err := cluster.ExpandVars(mustCollectYaml(t, tc.data), lookup)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, config.String())
+		})
+	}
+}
+
+func TestMergeOverlays(t *testing.T) {
+	base := mustCollectYaml(t, `
+credentials:
+  users:
+    guest:
+      roles: [super]
+iproto:
+  listen:
+  - uri: localhost:3301
+groups:
+  storages:
+    replicasets:
+      s-001:
+        instances:
+          s-001-a: {}
+`)
+	first := mustCollectYaml(t, `
+credentials:
+  users:
+    admin:
+      password: secret
+iproto:
+  listen:
+  - uri: 0.0.0.0:3301
+`)
+	second := mustCollectYaml(t, `
+credentials:
+  users:
+    admin:
+      password: top-secret
+log:
+  level: 5
+`)
+
+	config := cluster.MergeOverlays(base, first, second)
+	assert.YAMLEq(t, `
+credentials:
+  users:
+    admin:
+      password: top-secret
+    guest:
+      roles: [super]
+iproto:
+  listen:
+  - uri: 0.0.0.0:3301
+log:
+  level: 5
+groups:
+  storages:
+    replicasets:
+      s-001:
+        instances:
+          s-001-a: {}
+`, config.String())
+
+	// The base configuration is not modified.
+	value, err := base.Get([]string{"iproto", "listen"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[any]any{"uri": "localhost:3301"}}, value)
+
+	assert.Equal(t, base, cluster.MergeOverlays(base))
+}
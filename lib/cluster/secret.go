@@ -0,0 +1,302 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tarantool/go-tarantool/v2"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// secretRe matches a `{{ secret "<scheme>:<reference>" }}` secret reference.
+var secretRe = regexp.MustCompile(`\{\{\s*secret\s+"([^"]*)"\s*\}\}`)
+
+// defaultHTTPSecretTimeout is a default timeout of a request to an HTTP secret
+// storage.
+const defaultHTTPSecretTimeout = 10 * time.Second
+
+// SecretResolver resolves secret references of a scheme.
+type SecretResolver interface {
+	// Resolve returns a value of a secret by a reference without a scheme.
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolvers are secret resolvers by schemes.
+type SecretResolvers map[string]SecretResolver
+
+// NewSecretResolvers creates secret resolvers for the "file" and the "env"
+// schemes.
+func NewSecretResolvers() SecretResolvers {
+	return SecretResolvers{
+		"file": FileSecretResolver{},
+		"env":  EnvSecretResolver{},
+	}
+}
+
+// Resolve returns a value of a secret by a reference with a scheme.
+func (resolvers SecretResolvers) Resolve(ref string) (string, error) {
+	scheme, value, found := strings.Cut(ref, ":")
+	if !found {
+		return "", fmt.Errorf("secret reference %q must have a scheme", ref)
+	}
+	resolver, ok := resolvers[scheme]
+	if !ok {
+		return "", fmt.Errorf("unsupported secret scheme %q in %q", scheme, ref)
+	}
+	secret, err := resolver.Resolve(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %q: %w", ref, err)
+	}
+	return secret, nil
+}
+
+// FileSecretResolver reads secrets from files. A trailing newline is
+// removed from a secret.
+type FileSecretResolver struct{}
+
+// Resolve returns content of a file by a path.
+func (FileSecretResolver) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// EnvSecretResolver reads secrets from environment variables.
+type EnvSecretResolver struct{}
+
+// Resolve returns a value of an environment variable by a name.
+func (EnvSecretResolver) Resolve(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", ref)
+	}
+	return value, nil
+}
+
+// HTTPSecretResolver reads secrets from an HTTP secret storage with
+// a Vault-compatible key-value API. A reference has a format
+// "<path>#<field>", the field could be omitted if a secret has a single
+// field.
+type HTTPSecretResolver struct {
+	// Address is a base address of the storage.
+	Address string
+	// Token is a token sent in the X-Vault-Token header if set.
+	Token string
+	// Client is an HTTP client for requests.
+	Client *http.Client
+}
+
+// NewHTTPSecretResolver creates a new HTTP secret resolver.
+func NewHTTPSecretResolver(address, token string) HTTPSecretResolver {
+	return HTTPSecretResolver{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: defaultHTTPSecretTimeout},
+	}
+}
+
+// Resolve requests a secret from the storage.
+func (resolver HTTPSecretResolver) Resolve(ref string) (string, error) {
+	path, field, _ := strings.Cut(ref, "#")
+	url := resolver.Address + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if resolver.Token != "" {
+		req.Header.Set("X-Vault-Token", resolver.Token)
+	}
+
+	resp, err := resolver.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse a response: %w", err)
+	}
+	data := secret.Data
+	// A key-value storage of the second version wraps fields with metadata.
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	if field == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret has %d fields, a field must be specified",
+				len(data))
+		}
+		for _, value := range data {
+			return fmt.Sprint(value), nil
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", field)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// HasSecrets returns true if the data contains secret references.
+func HasSecrets(data []byte) bool {
+	return secretRe.Match(data)
+}
+
+// resolveSecretsString replaces secret references in the string.
+func resolveSecretsString(str string, resolvers SecretResolvers) (string, error) {
+	var resolveErr error
+	resolved := secretRe.ReplaceAllStringFunc(str, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		ref := secretRe.FindStringSubmatch(match)[1]
+		secret, err := resolvers.Resolve(ref)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		return secret
+	})
+	return resolved, resolveErr
+}
+
+// resolveSecretsValue replaces secret references in strings of the value.
+func resolveSecretsValue(value any, resolvers SecretResolvers) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return resolveSecretsString(v, resolvers)
+	case map[any]any:
+		resolved := make(map[any]any, len(v))
+		for key, elem := range v {
+			var err error
+			if resolved[key], err = resolveSecretsValue(elem, resolvers); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(v))
+		for i, elem := range v {
+			var err error
+			if resolved[i], err = resolveSecretsValue(elem, resolvers); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// ResolveSecrets returns a copy of the configuration with secret references
+// replaced by values of the secrets. A secret reference has a format
+// `{{ secret "<scheme>:<reference>" }}` and could be a part of a string
+// value. The configuration is not modified.
+func ResolveSecrets(config *Config, resolvers SecretResolvers) (*Config, error) {
+	paths, err := resolveSecretsValue(config.paths, resolvers)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{paths: paths}, nil
+}
+
+// SecretCollectorDecorator is a wrapper over Collector resolving secret
+// references in a collected configuration.
+type SecretCollectorDecorator struct {
+	collector Collector
+	resolvers SecretResolvers
+}
+
+// NewSecretCollectorDecorator wraps a Collector to resolve secret references
+// with the resolvers.
+func NewSecretCollectorDecorator(collector Collector,
+	resolvers SecretResolvers,
+) SecretCollectorDecorator {
+	return SecretCollectorDecorator{
+		collector: collector,
+		resolvers: resolvers,
+	}
+}
+
+// Collect collects a configuration and resolves secret references in it.
+func (collector SecretCollectorDecorator) Collect() (*Config, error) {
+	config, err := collector.collector.Collect()
+	if err != nil {
+		return nil, err
+	}
+	return ResolveSecrets(config, collector.resolvers)
+}
+
+// secretCollectorFactoryDecorator is a wrapper over CollectorFactory
+// resolving secret references in collected configurations.
+type secretCollectorFactoryDecorator struct {
+	factory   CollectorFactory
+	resolvers SecretResolvers
+}
+
+// wrap wraps a created collector.
+func (factory secretCollectorFactoryDecorator) wrap(collector Collector,
+	err error,
+) (Collector, error) {
+	if err != nil {
+		return nil, err
+	}
+	return NewSecretCollectorDecorator(collector, factory.resolvers), nil
+}
+
+// NewFile creates a new file configuration Collector and wraps it.
+func (factory secretCollectorFactoryDecorator) NewFile(path string) (Collector, error) {
+	return factory.wrap(factory.factory.NewFile(path))
+}
+
+// NewEtcd creates a new etcd Collector and wraps it.
+func (factory secretCollectorFactoryDecorator) NewEtcd(etcdcli *clientv3.Client,
+	prefix, key string, timeout time.Duration,
+) (Collector, error) {
+	return factory.wrap(factory.factory.NewEtcd(etcdcli, prefix, key, timeout))
+}
+
+// NewTarantool creates a new tarantool Collector and wraps it.
+func (factory secretCollectorFactoryDecorator) NewTarantool(conn tarantool.Doer,
+	prefix, key string, timeout time.Duration,
+) (Collector, error) {
+	return factory.wrap(factory.factory.NewTarantool(conn, prefix, key, timeout))
+}
+
+// NewConsul creates a new Consul Collector and wraps it.
+func (factory secretCollectorFactoryDecorator) NewConsul(client *ConsulClient,
+	prefix, key string, timeout time.Duration,
+) (Collector, error) {
+	return factory.wrap(factory.factory.NewConsul(client, prefix, key, timeout))
+}
+
+// NewSecretCollectorFactory wraps a CollectorFactory to resolve secret
+// references in collected configurations with the resolvers.
+func NewSecretCollectorFactory(factory CollectorFactory,
+	resolvers SecretResolvers,
+) CollectorFactory {
+	return secretCollectorFactoryDecorator{
+		factory:   factory,
+		resolvers: resolvers,
+	}
+}
@@ -0,0 +1,182 @@
+package cluster_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/lib/cluster"
+)
+
+// newSecretStorageStub creates a stub of a Vault-compatible secret storage.
+func newSecretStorageStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/replicator":
+			w.Write([]byte(`{"data": {"password": "kv1", "login": "replicator"}}`))
+		case "/v1/secret/data/replicator":
+			w.Write([]byte(`{"data": {"data": {"password": "kv2"}, "metadata": {}}}`))
+		case "/v1/invalid":
+			w.Write([]byte(`{`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPSecretResolver(t *testing.T) {
+	server := newSecretStorageStub(t)
+	resolver := cluster.NewHTTPSecretResolver(server.URL+"/", "token")
+
+	cases := []struct {
+		ref      string
+		expected string
+		err      string
+	}{
+		{ref: "kv/replicator#password", expected: "kv1"},
+		{ref: "/kv/replicator#login", expected: "replicator"},
+		{ref: "secret/data/replicator", expected: "kv2"},
+		{ref: "secret/data/replicator#password", expected: "kv2"},
+		{ref: "kv/replicator", err: "secret has 2 fields, a field must be specified"},
+		{ref: "kv/replicator#foo", err: `secret has no field "foo"`},
+		{ref: "unknown", err: `unexpected response status "404 Not Found"`},
+		{ref: "invalid", err: "failed to parse a response"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.ref, func(t *testing.T) {
+			secret, err := resolver.Resolve(tc.ref)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, secret)
+		})
+	}
+
+	_, err := cluster.NewHTTPSecretResolver(server.URL, "").Resolve("kv/replicator#login")
+	assert.EqualError(t, err, `unexpected response status "403 Forbidden"`)
+}
+
+func TestSecretResolvers_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from_file\n"), 0o600))
+	t.Setenv("TT_TEST_SECRET", "from_env")
+
+	resolvers := cluster.NewSecretResolvers()
+	cases := []struct {
+		ref      string
+		expected string
+		err      string
+	}{
+		{ref: "file:" + file, expected: "from_file"},
+		{ref: "env:TT_TEST_SECRET", expected: "from_env"},
+		{ref: "TT_TEST_SECRET", err: `secret reference "TT_TEST_SECRET" must have a scheme`},
+		{ref: "vault:foo", err: `unsupported secret scheme "vault" in "vault:foo"`},
+		{
+			ref: "env:TT_TEST_SECRET_UNKNOWN",
+			err: `failed to resolve secret "env:TT_TEST_SECRET_UNKNOWN": ` +
+				`environment variable "TT_TEST_SECRET_UNKNOWN" is not set`,
+		},
+		{
+			ref: "file:" + filepath.Join(dir, "missing"),
+			err: `failed to resolve secret "file:` + filepath.Join(dir, "missing"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.ref, func(t *testing.T) {
+			secret, err := resolvers.Resolve(tc.ref)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, secret)
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TT_TEST_SECRET", "secret")
+	server := newSecretStorageStub(t)
+	resolvers := cluster.NewSecretResolvers()
+	resolvers["vault"] = cluster.NewHTTPSecretResolver(server.URL, "token")
+
+	data := []byte(`credentials:
+  users:
+    replicator:
+      password: '{{ secret "vault:kv/replicator#password" }}'
+    admin:
+      password: '{{secret "env:TT_TEST_SECRET"}}'
+iproto:
+  listen:
+  - uri: 'localhost:3301'
+    params:
+      transport: '{{ secret "env:TT_TEST_SECRET" }}-{{ secret "env:TT_TEST_SECRET" }}'
+`)
+	assert.True(t, cluster.HasSecrets(data))
+	assert.False(t, cluster.HasSecrets([]byte("password: '{{ context.password }}'")))
+
+	config, err := cluster.NewYamlCollector(data).Collect()
+	require.NoError(t, err)
+	source := config.String()
+
+	resolved, err := cluster.ResolveSecrets(config, resolvers)
+	require.NoError(t, err)
+	assert.Equal(t, `credentials:
+  users:
+    admin:
+      password: secret
+    replicator:
+      password: kv1
+iproto:
+  listen:
+    - params:
+        transport: secret-secret
+      uri: localhost:3301
+`, resolved.String())
+	assert.Equal(t, source, config.String())
+
+	_, err = cluster.ResolveSecrets(config, cluster.NewSecretResolvers())
+	assert.EqualError(t, err,
+		`unsupported secret scheme "vault" in "vault:kv/replicator#password"`)
+}
+
+func TestSecretCollectorFactory(t *testing.T) {
+	t.Setenv("TT_TEST_SECRET", "secret")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`password: '{{ secret "env:TT_TEST_SECRET" }}'`), 0o644))
+
+	factory := cluster.NewSecretCollectorFactory(
+		cluster.NewCollectorFactory(cluster.NewDataCollectorFactory()),
+		cluster.NewSecretResolvers())
+	collector, err := factory.NewFile(path)
+	require.NoError(t, err)
+	config, err := collector.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, "password: secret\n", config.String())
+
+	collector, err = factory.NewFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	_, err = collector.Collect()
+	assert.Error(t, err)
+}
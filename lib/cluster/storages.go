@@ -1,8 +1,12 @@
 package cluster
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // getConfigPrefix returns a full configuration prefix.
@@ -16,6 +20,12 @@ func getConfigKey(basePrefix, key string) string {
 	return getConfigPrefix(basePrefix) + key
 }
 
+// ConfigKey returns a full path to a configuration key in a storage with
+// the base prefix.
+func ConfigKey(basePrefix, key string) string {
+	return getConfigKey(basePrefix, key)
+}
+
 // getHashesPrefix returns a full hashes prefix.
 func getHashesPrefix(basePrefix string) string {
 	prefix := strings.TrimRight(basePrefix, "/")
@@ -38,6 +48,50 @@ func getSignKey(basePrefix, key string) string {
 	return getSignPrefix(basePrefix) + key
 }
 
+// HistoryLimit is a maximum number of history records kept by tt for a key in
+// storages without their own history. The oldest records are deleted on
+// publishing.
+const HistoryLimit = 100
+
+// staleHistoryKeys returns the oldest history records that must be deleted to
+// keep at most HistoryLimit records after a new one is published.
+func staleHistoryKeys(historyPrefix string, keys []string) []string {
+	type record struct {
+		key  string
+		nsec int64
+	}
+	var records []record
+	for _, key := range keys {
+		nsec, err := strconv.ParseInt(strings.TrimPrefix(key, historyPrefix), 10, 64)
+		if err == nil {
+			records = append(records, record{key: key, nsec: nsec})
+		}
+	}
+	if len(records) < HistoryLimit {
+		return nil
+	}
+
+	slices.SortFunc(records, func(a, b record) int {
+		return cmp.Compare(a.nsec, b.nsec)
+	})
+	var stale []string
+	for _, record := range records[:len(records)-HistoryLimit+1] {
+		stale = append(stale, record.key)
+	}
+	return stale
+}
+
+// getHistoryPrefix returns a full prefix of a key history.
+func getHistoryPrefix(basePrefix, key string) string {
+	prefix := strings.TrimRight(basePrefix, "/")
+	return fmt.Sprintf("%s/%s/%s/", prefix, "history", key)
+}
+
+// getHistoryKey returns a full path to a history record of a key.
+func getHistoryKey(basePrefix, key string, time time.Time) string {
+	return getHistoryPrefix(basePrefix, key) + strconv.FormatInt(time.UnixNano(), 10)
+}
+
 // parseHashPath parses a full key path and returns true, hash, key on success.
 func parseHashPath(path, prefix string) (bool, string, string) {
 	hashesPrefix := getHashesPrefix(prefix)
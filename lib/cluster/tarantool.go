@@ -352,6 +352,9 @@ func (publisher TarantoolAllDataPublisher) Publish(revision int64, data []byte)
 	onSuccess := []any{
 		[]any{"delete", prefix},
 		[]any{"put", targetKey, string(data)},
+		// The config storage does not keep previous values, so tt keeps
+		// a history of published values.
+		[]any{"put", getHistoryKey(publisher.prefix, key, time.Now()), string(data)},
 	}
 	if publisher.signFunc != nil {
 		hashes, sign, err := publisher.signFunc(data)
@@ -369,17 +372,14 @@ func (publisher TarantoolAllDataPublisher) Publish(revision int64, data []byte)
 		onSuccess = append(onSuccess,
 			[]any{"put", getSignKey(publisher.prefix, key), string(sign)})
 	}
-	args := []any{
-		map[any]any{
-			"on_success": onSuccess,
-		},
-	}
+	prune := tarantoolHistoryPrune(publisher.conn, publisher.prefix, key,
+		publisher.timeout)
+	onSuccess = append(onSuccess, prune...)
 
-	_, err := tarantoolCall(publisher.conn, "config.storage.txn", args, publisher.timeout)
-	if err != nil {
-		return fmt.Errorf("failed to put data into tarantool: %w", err)
+	txn := map[any]any{
+		"on_success": onSuccess,
 	}
-	return nil
+	return tarantoolTxnPut(publisher.conn, txn, publisher.timeout)
 }
 
 // TarantoolKeyDataPublisher publishes a data into Tarantool for a prefix
@@ -430,6 +430,7 @@ func (publisher TarantoolKeyDataPublisher) Publish(revision int64, data []byte)
 
 	onSuccess := []any{
 		[]any{"put", key, string(data)},
+		[]any{"put", getHistoryKey(publisher.prefix, publisher.key, time.Now()), string(data)},
 	}
 	if publisher.signFunc != nil {
 		hashes, sign, err := publisher.signFunc(data)
@@ -444,6 +445,10 @@ func (publisher TarantoolKeyDataPublisher) Publish(revision int64, data []byte)
 			[]any{"put", getSignKey(publisher.prefix, publisher.key), string(sign)})
 	}
 
+	prune := tarantoolHistoryPrune(publisher.conn, publisher.prefix, publisher.key,
+		publisher.timeout)
+	onSuccess = append(onSuccess, prune...)
+
 	txn := map[any]any{
 		"on_success": onSuccess,
 	}
@@ -451,15 +456,60 @@ func (publisher TarantoolKeyDataPublisher) Publish(revision int64, data []byte)
 	if revision != 0 {
 		txn["predicates"] = []any{[]any{"mod_revision", "==", revision, key}}
 	}
-	args := []any{txn}
+	return tarantoolTxnPut(publisher.conn, txn, publisher.timeout)
+}
+
+// tarantoolTxnPutResponse is a response of a txn put request.
+type tarantoolTxnPutResponse struct {
+	Data struct {
+		IsSuccess bool `mapstructure:"is_success"`
+	}
+}
 
-	_, err := tarantoolCall(publisher.conn, "config.storage.txn", args, publisher.timeout)
+// tarantoolTxnPut executes a txn request that modifies a tarantool config
+// storage. It returns an error if the predicates of the request fail.
+func tarantoolTxnPut(conn tarantool.Doer, txn map[any]any, timeout time.Duration) error {
+	data, err := tarantoolCall(conn, "config.storage.txn", []any{txn}, timeout)
 	if err != nil {
 		return fmt.Errorf("failed to put data into tarantool: %w", err)
 	}
+	if len(data) != 1 {
+		return fmt.Errorf("unexpected response from tarantool: %q", data)
+	}
+	resp := tarantoolTxnPutResponse{}
+	if err := mapstructure.Decode(data[0], &resp); err != nil {
+		return fmt.Errorf("failed to map response from tarantool: %q", data[0])
+	}
+	if !resp.Data.IsSuccess {
+		return fmt.Errorf("failed to put data into tarantool: wrong revision")
+	}
 	return nil
 }
 
+// tarantoolHistoryPrune returns txn operations to delete stale history
+// records of the key. The pruning is best-effort: no operations are returned
+// if the history can not be read, so a publishing does not require a read
+// access.
+func tarantoolHistoryPrune(conn tarantool.Doer, prefix, key string,
+	timeout time.Duration,
+) []any {
+	historyPrefix := getHistoryPrefix(prefix, key)
+	resp, err := tarantoolGet(conn, historyPrefix, timeout)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, data := range resp.Data {
+		keys = append(keys, data.Path)
+	}
+	var ops []any
+	for _, stale := range staleHistoryKeys(historyPrefix, keys) {
+		ops = append(ops, []any{"delete", stale})
+	}
+	return ops
+}
+
 // tarantoolTxnResponse is a response of a txn get requests.
 type tarantoolTxnGetResponse struct {
 	Data struct {
@@ -477,6 +527,7 @@ type tarantoolGetResponse struct {
 		Value       string
 		ModRevision int64 `mapstructure:"mod_revision"`
 	}
+	Revision int64
 }
 
 // tarantoolCall returns result of a function call via tarantool connector.
@@ -580,6 +631,7 @@ func ConnectTarantool(uriOpts libconnect.UriOpts,
 		SslCertFile: uriOpts.CertFile,
 		SslCaFile:   uriOpts.CaFile,
 		SslCiphers:  uriOpts.Ciphers,
+		Ssh:         dial.SshOpts(uriOpts.Ssh),
 	}
 
 	dialer, err := dial.New(dialOpts)
@@ -623,19 +675,41 @@ func (c *tarantoolCSConnection) Close() error {
 	return c.conn.Close()
 }
 
-// Get implements ConnectCStorage interface.
-func (c *tarantoolCSConnection) Get(ctx context.Context, key string) ([]Data, error) {
+// get retrieves a response of a get request for key.
+func (c *tarantoolCSConnection) get(ctx context.Context, key string,
+) (tarantoolGetResponse, error) {
+	var resp tarantoolGetResponse
 	data, err := c.call(ctx, "config.storage.get", []any{key})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data from tarantool: %w", err)
+		return resp, fmt.Errorf("failed to fetch data from tarantool: %w", err)
 	}
 	if len(data) != 1 {
-		return nil, fmt.Errorf("unexpected response from tarantool: %q", data)
+		return resp, fmt.Errorf("unexpected response from tarantool: %q", data)
 	}
 
-	var resp tarantoolGetResponse
 	if err := mapstructure.Decode(data[0], &resp); err != nil {
-		return nil, fmt.Errorf("failed to map response from tarantool: %q", data[0])
+		return resp, fmt.Errorf("failed to map response from tarantool: %q", data[0])
+	}
+	return resp, nil
+}
+
+// Get implements ConnectCStorage interface.
+func (c *tarantoolCSConnection) Get(ctx context.Context, key string) ([]Data, error) {
+	resp, err := c.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPrefixKey(key) {
+		collected := make([]Data, 0, len(resp.Data))
+		for _, data := range resp.Data {
+			collected = append(collected, Data{
+				Source:   data.Path,
+				Value:    []byte(data.Value),
+				Revision: data.ModRevision,
+			})
+		}
+		return collected, nil
 	}
 
 	switch {
@@ -667,24 +741,116 @@ func (c *tarantoolCSConnection) Put(ctx context.Context, key, value string) erro
 	return nil
 }
 
+// prefixChanges returns changes of keys with the prefix since the revisions
+// of keys and updates the revisions.
+func (c *tarantoolCSConnection) prefixChanges(ctx context.Context, prefix string,
+	revisions map[string]int64,
+) ([]CSWatchEvent, error) {
+	values, err := c.Get(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []CSWatchEvent
+	found := map[string]bool{}
+	for _, value := range values {
+		found[value.Source] = true
+		if revisions[value.Source] == value.Revision {
+			continue
+		}
+		revisions[value.Source] = value.Revision
+		events = append(events, CSWatchEvent{
+			Key:      value.Source,
+			Value:    value.Value,
+			Revision: value.Revision,
+		})
+	}
+	for key := range revisions {
+		if !found[key] {
+			delete(revisions, key)
+			events = append(events, CSWatchEvent{Key: key, Deleted: true})
+		}
+	}
+	return events, nil
+}
+
+// Snapshot implements CSSnapshotWatcher interface.
+func (c *tarantoolCSConnection) Snapshot(ctx context.Context, key string) (CSSnapshot, error) {
+	resp, err := c.get(ctx, key)
+	if err != nil {
+		return CSSnapshot{}, err
+	}
+
+	snapshot := CSSnapshot{Revision: resp.Revision}
+	for _, data := range resp.Data {
+		snapshot.Data = append(snapshot.Data, Data{
+			Source:   data.Path,
+			Value:    []byte(data.Value),
+			Revision: data.ModRevision,
+		})
+	}
+	return snapshot, nil
+}
+
+// WatchSince implements CSSnapshotWatcher interface. The config storage does
+// not keep previous values, so changes are found by revisions of keys from
+// the snapshot.
+func (c *tarantoolCSConnection) WatchSince(ctx context.Context, key string,
+	snapshot CSSnapshot,
+) <-chan CSWatchEvent {
+	revisions := map[string]int64{}
+	for _, data := range snapshot.Data {
+		revisions[data.Source] = data.Revision
+	}
+	return c.watch(ctx, key, revisions)
+}
+
 // Watch implements ConnectCStorage interface.
 func (c *tarantoolCSConnection) Watch(ctx context.Context, key string) <-chan CSWatchEvent {
+	revisions := map[string]int64{}
+	if isPrefixKey(key) {
+		// All keys are reported as changed on errors.
+		_, _ = c.prefixChanges(ctx, key, revisions)
+	}
+	return c.watch(ctx, key, revisions)
+}
+
+// watch watches on a key. The config storage notifies about a change of
+// a prefix without changed keys, so the revisions of keys are tracked to find
+// the changes.
+func (c *tarantoolCSConnection) watch(ctx context.Context, key string,
+	revisions map[string]int64,
+) <-chan CSWatchEvent {
 	ch := make(chan CSWatchEvent)
 
 	// To watch for config storage key "config.storage:" prefix should be used.
 	watcher, err := c.conn.NewWatcher("config.storage:"+key, func(ev tarantool.WatchEvent) {
 		// WatchEvent only contains revision number, so separate request is needed to get data.
-		if ev.Value != nil {
-			// NOTE: At the moment watcher is only used to catch the fact that value has been
-			// changed and it doesn't matter if the value obtained with the subsequent request
-			// has revision number other than revision that triggered this event, so for now
-			// no consistency checking is implemented.
-			value, err := c.Get(ctx, key)
-			if err == nil {
-				ch <- CSWatchEvent{
-					Key:   key,
-					Value: value[0].Value,
-				}
+		if ev.Value == nil {
+			return
+		}
+		if isPrefixKey(key) {
+			events, err := c.prefixChanges(ctx, key, revisions)
+			if err != nil {
+				return
+			}
+			for _, event := range events {
+				ch <- event
+			}
+			return
+		}
+		// NOTE: At the moment watcher is only used to catch the fact that value has been
+		// changed and it doesn't matter if the value obtained with the subsequent request
+		// has revision number other than revision that triggered this event, so for now
+		// no consistency checking is implemented.
+		value, err := c.Get(ctx, key)
+		// The watcher is notified on registration, the value could be known.
+		if err == nil && revisions[key] != value[0].Revision {
+			revisions[key] = value[0].Revision
+			ch <- CSWatchEvent{
+				Key:      key,
+				Value:    value[0].Value,
+				Revision: value[0].Revision,
 			}
 		}
 	})
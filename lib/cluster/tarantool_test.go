@@ -1,9 +1,16 @@
 package cluster_test
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/go-tarantool/v2"
+	"github.com/tarantool/go-tarantool/v2/test_helpers"
+	"github.com/vmihailenco/msgpack/v5"
 
 	"github.com/tarantool/tt/lib/cluster"
 )
@@ -66,3 +73,85 @@ func TestNewTarantoolDataPublishers_Publish_nil_evaler(t *testing.T) {
 		})
 	}
 }
+
+// tarantoolCallArgs returns arguments of a call request.
+func tarantoolCallArgs(t *testing.T, req tarantool.Request) []any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, req.Body(nil, msgpack.NewEncoder(&buf)))
+	body, err := msgpack.NewDecoder(&buf).DecodeUntypedMap()
+	require.NoError(t, err)
+	// 0x21 is the IPROTO_TUPLE key of call arguments.
+	for key, value := range body {
+		if fmt.Sprint(key) == fmt.Sprint(0x21) {
+			return value.([]any)
+		}
+	}
+	require.FailNow(t, "call arguments not found")
+	return nil
+}
+
+func TestTarantoolKeyDataPublisher_Publish(t *testing.T) {
+	var history []map[string]any
+	for i := range cluster.HistoryLimit {
+		history = append(history, map[string]any{
+			"path":  fmt.Sprintf("/prefix/history/all/%d", 1000+i),
+			"value": "a",
+		})
+	}
+	getResponse := test_helpers.NewMockResponse(t, []any{map[string]any{"data": history}})
+	txnResponse := func(success bool) *test_helpers.MockResponse {
+		return test_helpers.NewMockResponse(t, []any{
+			map[string]any{"data": map[string]any{"is_success": success}},
+		})
+	}
+
+	doer := test_helpers.NewMockDoer(t, getResponse, txnResponse(true))
+	publisher := cluster.NewTarantoolKeyDataPublisher(&doer, "/prefix", "all", time.Second)
+	require.NoError(t, publisher.Publish(3, []byte("b")))
+	require.Len(t, doer.Requests, 2)
+
+	txn := tarantoolCallArgs(t, doer.Requests[1])[0].(map[string]any)
+	assert.Equal(t, []any{[]any{"mod_revision", "==", int64(3), "/prefix/config/all"}},
+		txn["predicates"])
+	assert.Contains(t, txn["on_success"], []any{"put", "/prefix/config/all", "b"})
+	// The oldest record is deleted to keep the limit.
+	assert.Contains(t, txn["on_success"], []any{"delete", "/prefix/history/all/1000"})
+	assert.NotContains(t, txn["on_success"], []any{"delete", "/prefix/history/all/1001"})
+
+	doer = test_helpers.NewMockDoer(t, getResponse, txnResponse(false))
+	publisher = cluster.NewTarantoolKeyDataPublisher(&doer, "/prefix", "all", time.Second)
+	err := publisher.Publish(3, []byte("b"))
+	assert.EqualError(t, err, "failed to put data into tarantool: wrong revision")
+}
+
+func TestTarantoolDataPublishers_Publish_historyNotReadable(t *testing.T) {
+	txnResponse := test_helpers.NewMockResponse(t, []any{
+		map[string]any{"data": map[string]any{"is_success": true}},
+	})
+	getErr := fmt.Errorf("Read access to function 'config.storage.get' is denied")
+
+	cases := []struct {
+		Name         string
+		NewPublisher func(doer tarantool.Doer) cluster.DataPublisher
+	}{
+		{"all", func(doer tarantool.Doer) cluster.DataPublisher {
+			return cluster.NewTarantoolAllDataPublisher(doer, "/prefix", time.Second)
+		}},
+		{"key", func(doer tarantool.Doer) cluster.DataPublisher {
+			return cluster.NewTarantoolKeyDataPublisher(doer, "/prefix", "all", time.Second)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			doer := test_helpers.NewMockDoer(t, getErr, txnResponse)
+			require.NoError(t, tc.NewPublisher(&doer).Publish(0, []byte("b")))
+			require.Len(t, doer.Requests, 2)
+
+			txn := tarantoolCallArgs(t, doer.Requests[1])[0].(map[string]any)
+			assert.Contains(t, txn["on_success"], []any{"put", "/prefix/config/all", "b"})
+		})
+	}
+}
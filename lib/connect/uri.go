@@ -54,6 +54,20 @@ const (
 	// peer’s SSL certificate.
 	verifyPeerParam = "verify_peer"
 
+	// sshKeyFileParam is a URL parameter with SSH private key file path.
+	sshKeyFileParam = "ssh_key_file"
+
+	// sshKnownHostsParam is a URL parameter with SSH known_hosts file path.
+	sshKnownHostsParam = "ssh_known_hosts"
+
+	// sshScheme is a URL scheme to connect through an SSH server:
+	// ssh://[user@]ssh_host[:port]/host:port[/prefix].
+	sshScheme = "ssh"
+
+	// ConsulScheme is a URL scheme to connect to a Consul KV storage:
+	// consul://[:token@]host[:port][/prefix].
+	ConsulScheme = "consul"
+
 	// defaultVerifyHostParam is the default VerifyHostParam.
 	defaultVerifyHostParam = true
 
@@ -97,10 +111,31 @@ type UriOpts struct {
 	SkipPeerVerify bool
 	// Timeout is a timeout for actions.
 	Timeout time.Duration
+	// Ssh is a set of SSH tunnel options. The tunnel is used if Ssh.Host
+	// is not empty.
+	Ssh SshOpts
 	// Params keeps extra URL parameters.
 	Params map[string]string
 }
 
+// IsConsul returns true if the options describe a connection to a Consul KV
+// storage.
+func (opts UriOpts) IsConsul() bool {
+	return strings.HasPrefix(opts.Endpoint, ConsulScheme+"://")
+}
+
+// SshOpts is a list of options of an SSH server to connect through.
+type SshOpts struct {
+	// Host is an SSH server address: host[:port].
+	Host string
+	// User is an SSH user name.
+	User string
+	// KeyFile is a path to an SSH private key file.
+	KeyFile string
+	// KnownHostsFile is a path to an SSH known_hosts file.
+	KnownHostsFile string
+}
+
 // IsBaseURI returns true if a string is a valid URI.
 func IsBaseURI(str string) bool {
 	// tcp://host:port
@@ -245,6 +280,29 @@ func parseUriOpts(uri *url.URL) (UriOpts, error) {
 		opts.Password = password
 	}
 
+	if uri.Scheme == sshScheme {
+		// ssh://[user@]ssh_host[:port]/host:port[/prefix]: the user is an
+		// SSH user and the first path element is an address to connect.
+		target := strings.TrimPrefix(uri.Path, "/")
+		prefix := ""
+		if pos := strings.Index(target, "/"); pos >= 0 {
+			target, prefix = target[:pos], target[pos:]
+		}
+		if target == "" {
+			return opts, fmt.Errorf("an address to connect through SSH is not "+
+				"specified, expected %s://[user@]host[:port]/host:port", sshScheme)
+		}
+		opts.Endpoint = "tcp://" + target
+		opts.Host = target
+		opts.Prefix = prefix
+		opts.Username = ""
+		opts.Password = ""
+		opts.Ssh = SshOpts{
+			Host: uri.Host,
+			User: uri.User.Username(),
+		}
+	}
+
 	values := uri.Query()
 	for k, v := range values {
 		switch k {
@@ -258,6 +316,10 @@ func parseUriOpts(uri *url.URL) (UriOpts, error) {
 			opts.CaFile = v[0]
 		case sslCiphersParam:
 			opts.Ciphers = v[0]
+		case sshKeyFileParam:
+			opts.Ssh.KeyFile = v[0]
+		case sshKnownHostsParam:
+			opts.Ssh.KnownHostsFile = v[0]
 
 		case timeoutParam:
 			opts.Timeout, err = getDurationParam(v[0], defaultTimeoutParam)
@@ -14,9 +14,9 @@ func New(opts Opts) (tarantool.Dialer, error) {
 	}
 
 	if transport == TransportDefault {
-		if opts.SslKeyFile != "" || opts.SslCaFile != "" || opts.SslCertFile != "" ||
-			opts.SslCiphers != "" || opts.SslPassword != "" || opts.SslPasswordFile != "" {
-
+		if opts.Ssh.Host != "" {
+			transport = TransportSsh
+		} else if hasSslOpts(opts) {
 			transport = TransportSsl
 		} else {
 			transport = TransportPlain
@@ -32,7 +32,15 @@ func New(opts Opts) (tarantool.Dialer, error) {
 		}, nil
 	case TransportSsl:
 		return ssl(opts)
+	case TransportSsh:
+		return sshTunnel(opts)
 	default:
 		return nil, fmt.Errorf("unsupported transport type: %s", opts.Transport)
 	}
 }
+
+// hasSslOpts returns true if any of SSL options is set.
+func hasSslOpts(opts Opts) bool {
+	return opts.SslKeyFile != "" || opts.SslCaFile != "" || opts.SslCertFile != "" ||
+		opts.SslCiphers != "" || opts.SslPassword != "" || opts.SslPasswordFile != ""
+}
@@ -6,6 +6,7 @@ require (
 	github.com/stretchr/testify v1.10.0
 	github.com/tarantool/go-tarantool/v2 v2.4.2
 	github.com/tarantool/go-tlsdialer v1.0.2
+	golang.org/x/crypto v0.51.0
 )
 
 require (
@@ -17,6 +18,6 @@ require (
 	github.com/tarantool/go-openssl v1.2.1 // indirect
 	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
-	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/sys v0.44.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
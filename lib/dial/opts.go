@@ -15,4 +15,20 @@ type Opts struct {
 	SslPassword     string
 	SslPasswordFile string
 	Transport       string
+	Ssh             SshOpts
+}
+
+// SshOpts represents set of parameters of an SSH tunnel to dial through.
+type SshOpts struct {
+	// Host is an SSH server address: host[:port]. The tunnel is not used
+	// if the host is empty.
+	Host string
+	// User is an SSH user name. The current system user is used if empty.
+	User string
+	// KeyFile is a path to a private key file. Keys from the SSH agent
+	// are used if empty.
+	KeyFile string
+	// KnownHostsFile is a path to a known_hosts file to verify the SSH
+	// server key. ~/.ssh/known_hosts is used if empty.
+	KnownHostsFile string
 }
@@ -0,0 +1,322 @@
+package dial
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tarantool/go-tarantool/v2"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// sshDefaultPort is a port of an SSH server used if it is not specified.
+	sshDefaultPort = "22"
+	// sshBufSize is a size of read and write buffers of a tunneled connection.
+	sshBufSize = 128 * 1024
+)
+
+// sshTunnel creates a dialer that connects to the address through an SSH
+// server.
+func sshTunnel(opts Opts) (tarantool.Dialer, error) {
+	if opts.Ssh.Host == "" {
+		return nil, errors.New("SSH host is not specified")
+	}
+	if hasSslOpts(opts) {
+		return nil, errors.New("SSL connections through an SSH tunnel are not supported")
+	}
+
+	return tarantool.AuthDialer{
+		Dialer: tarantool.ProtocolDialer{
+			Dialer: tarantool.GreetingDialer{
+				Dialer: sshDialer{
+					address: opts.Address,
+					ssh:     opts.Ssh,
+				},
+			},
+		},
+		Auth:     opts.Auth,
+		Username: opts.User,
+		Password: opts.Password,
+	}, nil
+}
+
+// sshDialer is a base dialer that opens a connection to the address through
+// an SSH server.
+type sshDialer struct {
+	address string
+	ssh     SshOpts
+}
+
+// Dial makes sshDialer satisfy the tarantool.Dialer interface.
+func (d sshDialer) Dial(ctx context.Context, opts tarantool.DialOpts) (tarantool.Conn, error) {
+	client, err := sshConnect(ctx, d.ssh)
+	if err != nil {
+		return nil, err
+	}
+
+	network, address := parseAddress(d.address)
+	conn, err := client.DialContext(ctx, network, address)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to dial %q through SSH server %q: %w",
+			d.address, d.ssh.Host, err)
+	}
+
+	return &sshTunnelConn{
+		net:    conn,
+		client: client,
+		reader: bufio.NewReaderSize(conn, sshBufSize),
+		writer: bufio.NewWriterSize(conn, sshBufSize),
+	}, nil
+}
+
+// sshConnect establishes a connection to the SSH server.
+func sshConnect(ctx context.Context, opts SshOpts) (*ssh.Client, error) {
+	config, release, err := sshClientConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	host := opts.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, sshDefaultPort)
+	}
+
+	dialer := net.Dialer{}
+	netConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial SSH server %q: %w", host, err)
+	}
+
+	// The handshake does not support a context, so the deadline is the only
+	// way to limit it.
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, host, config)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to establish SSH connection to %q: %w", host, err)
+	}
+	netConn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// SshForward is a local TCP port forwarded to an address through an SSH
+// server. It allows clients that can not use a custom dialer to connect
+// through the SSH server.
+type SshForward struct {
+	listener net.Listener
+	client   *ssh.Client
+	network  string
+	address  string
+}
+
+// NewSshForward connects to the SSH server and starts forwarding connections
+// from a local TCP port to the address.
+func NewSshForward(ctx context.Context, opts SshOpts, address string) (*SshForward, error) {
+	if opts.Host == "" {
+		return nil, errors.New("SSH host is not specified")
+	}
+	client, err := sshConnect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to listen a local port: %w", err)
+	}
+
+	network, address := parseAddress(address)
+	forward := &SshForward{
+		listener: listener,
+		client:   client,
+		network:  network,
+		address:  address,
+	}
+	go forward.serve()
+	return forward, nil
+}
+
+// Addr returns a local address to connect to.
+func (f *SshForward) Addr() string {
+	return f.listener.Addr().String()
+}
+
+// Close stops the forwarding and closes the SSH connection.
+func (f *SshForward) Close() error {
+	return errors.Join(f.listener.Close(), f.client.Close())
+}
+
+// serve accepts local connections and forwards them until the listener is
+// closed.
+func (f *SshForward) serve() {
+	for {
+		local, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.forward(local)
+	}
+}
+
+// forward copies data between a local connection and a connection to the
+// address through the SSH server.
+func (f *SshForward) forward(local net.Conn) {
+	defer local.Close()
+	remote, err := f.client.Dial(f.network, f.address)
+	if err != nil {
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	// Wait for one direction to finish, the deferred closes stop the other.
+	<-done
+}
+
+// sshTunnelConn is a connection through an SSH tunnel. It does not support
+// I/O timeouts because SSH channels do not support deadlines.
+type sshTunnelConn struct {
+	net    net.Conn
+	client *ssh.Client
+	reader io.Reader
+	writer *bufio.Writer
+}
+
+// Read makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// Write makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) Write(p []byte) (int, error) {
+	if l, err := c.writer.Write(p); err != nil {
+		return l, err
+	} else if l != len(p) {
+		return l, errors.New("wrong length written")
+	} else {
+		return l, nil
+	}
+}
+
+// Flush makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) Flush() error {
+	return c.writer.Flush()
+}
+
+// Close makes sshTunnelConn satisfy the tarantool.Conn interface. It closes
+// both the tunneled connection and the SSH connection.
+func (c *sshTunnelConn) Close() error {
+	return errors.Join(c.net.Close(), c.client.Close())
+}
+
+// Greeting makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) Greeting() tarantool.Greeting {
+	return tarantool.Greeting{}
+}
+
+// ProtocolInfo makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) ProtocolInfo() tarantool.ProtocolInfo {
+	return tarantool.ProtocolInfo{}
+}
+
+// Addr makes sshTunnelConn satisfy the tarantool.Conn interface.
+func (c *sshTunnelConn) Addr() net.Addr {
+	return c.net.RemoteAddr()
+}
+
+// sshClientConfig creates an SSH client configuration with agent or key file
+// authentication and known hosts verification. The returned function releases
+// resources used by the authentication and must be called after the handshake.
+func sshClientConfig(opts SshOpts) (*ssh.ClientConfig, func(), error) {
+	userName := opts.User
+	if userName == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get SSH user name: %w", err)
+		}
+		userName = current.Username
+	}
+
+	knownHostsFile := opts.KnownHostsFile
+	if knownHostsFile == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find known_hosts file: %w", err)
+		}
+		knownHostsFile = filepath.Join(homeDir, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load known hosts: %w", err)
+	}
+
+	release := func() {}
+	var auth []ssh.AuthMethod
+	if opts.KeyFile != "" {
+		key, err := os.ReadFile(opts.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read SSH key file: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SSH key file %q: %w",
+				opts.KeyFile, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		agentConn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to SSH agent: %w", err)
+		}
+		release = func() { agentConn.Close() }
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	} else {
+		return nil, nil, errors.New("no SSH authentication method: " +
+			"specify a key file or run an SSH agent")
+	}
+
+	return &ssh.ClientConfig{
+		User:            userName,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}, release, nil
+}
+
+// parseAddress splits an address into network and address parts.
+func parseAddress(address string) (string, string) {
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		return "unix", address[len("unix://"):]
+	case strings.HasPrefix(address, "unix/:"):
+		return "unix", address[len("unix/:"):]
+	case strings.HasPrefix(address, "/") || strings.HasPrefix(address, "."):
+		return "unix", address
+	case strings.HasPrefix(address, "tcp://"):
+		return "tcp", address[len("tcp://"):]
+	default:
+		return "tcp", address
+	}
+}
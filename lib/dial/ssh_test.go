@@ -0,0 +1,350 @@
+package dial
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/go-tarantool/v2"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	testGreetingVersion = "Tarantool 3.2.0 (Binary) 7b2ec16b-2e6e-4b3b-9d34-3c4dcbd8b59e"
+	testGreetingSalt    = "c2FsdHNhbHRzYWx0c2FsdHNhbHRzYWx0c2FsdHNhbHQ="
+)
+
+// testSshServer is an in-process SSH server that supports only direct TCP
+// forwarding.
+type testSshServer struct {
+	addr           string
+	keyFile        string
+	knownHostsFile string
+}
+
+// startTestSshServer starts an SSH server that accepts the generated client
+// key and returns files required to connect to it.
+func startTestSshServer(t *testing.T) testSshServer {
+	t.Helper()
+	dir := t.TempDir()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	clientPub, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientSshPub, err := ssh.NewPublicKey(clientPub)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600))
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions,
+			error,
+		) {
+			if conn.User() == "tt" &&
+				string(key.Marshal()) == string(clientSshPub.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key for %q", conn.User())
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSsh(conn, config)
+		}
+	}()
+
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{listener.Addr().String()}, hostSigner.PublicKey())
+	require.NoError(t, os.WriteFile(knownHostsFile, []byte(line+"\n"), 0o600))
+
+	return testSshServer{
+		addr:           listener.Addr().String(),
+		keyFile:        keyFile,
+		knownHostsFile: knownHostsFile,
+	}
+}
+
+func serveTestSsh(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		var payload struct {
+			Addr     string
+			Port     uint32
+			OrigAddr string
+			OrigPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		target, err := net.Dial("tcp",
+			net.JoinHostPort(payload.Addr, fmt.Sprint(payload.Port)))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(requests)
+		go func() {
+			io.Copy(channel, target)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(target, channel)
+			target.Close()
+		}()
+	}
+}
+
+// startTestGreeter starts a TCP server that sends a Tarantool greeting to
+// every client.
+func startTestGreeter(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	greeting := make([]byte, 128)
+	for i := range greeting {
+		greeting[i] = ' '
+	}
+	copy(greeting, testGreetingVersion)
+	greeting[63] = '\n'
+	copy(greeting[64:], testGreetingSalt)
+	greeting[127] = '\n'
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write(greeting)
+			io.Copy(io.Discard, conn)
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestSshDialer_Dial(t *testing.T) {
+	server := startTestSshServer(t)
+	target := startTestGreeter(t)
+
+	dialer := tarantool.GreetingDialer{
+		Dialer: sshDialer{
+			address: "tcp://" + target,
+			ssh: SshOpts{
+				Host:           server.addr,
+				User:           "tt",
+				KeyFile:        server.keyFile,
+				KnownHostsFile: server.knownHostsFile,
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := dialer.Dial(ctx, tarantool.DialOpts{})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.True(t, strings.HasPrefix(conn.Greeting().Version, testGreetingVersion))
+	require.Equal(t, testGreetingSalt, conn.Greeting().Salt)
+}
+
+func TestSshDialer_Dial_errors(t *testing.T) {
+	server := startTestSshServer(t)
+	target := startTestGreeter(t)
+
+	emptyKnownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(emptyKnownHosts, nil, 0o600))
+
+	cases := []struct {
+		name string
+		opts SshOpts
+		err  string
+	}{
+		{
+			name: "unknown_host",
+			opts: SshOpts{
+				Host:           server.addr,
+				User:           "tt",
+				KeyFile:        server.keyFile,
+				KnownHostsFile: emptyKnownHosts,
+			},
+			err: "failed to establish SSH connection",
+		},
+		{
+			name: "unknown_user",
+			opts: SshOpts{
+				Host:           server.addr,
+				User:           "admin",
+				KeyFile:        server.keyFile,
+				KnownHostsFile: server.knownHostsFile,
+			},
+			err: "unable to authenticate",
+		},
+		{
+			name: "missing_key_file",
+			opts: SshOpts{
+				Host:           server.addr,
+				User:           "tt",
+				KeyFile:        filepath.Join(t.TempDir(), "missing"),
+				KnownHostsFile: server.knownHostsFile,
+			},
+			err: "failed to read SSH key file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dialer := sshDialer{
+				address: "tcp://" + target,
+				ssh:     tc.opts,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := dialer.Dial(ctx, tarantool.DialOpts{})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestNew_ssh(t *testing.T) {
+	sshOpts := SshOpts{Host: "bastion", User: "tt"}
+	expected := tarantool.AuthDialer{
+		Dialer: tarantool.ProtocolDialer{
+			Dialer: tarantool.GreetingDialer{
+				Dialer: sshDialer{
+					address: "tcp://localhost:3301",
+					ssh:     sshOpts,
+				},
+			},
+		},
+		Username: "admin",
+		Password: "secret",
+	}
+
+	for _, transport := range []string{"", "ssh"} {
+		t.Run(transport, func(t *testing.T) {
+			dialer, err := New(Opts{
+				Address:   "tcp://localhost:3301",
+				User:      "admin",
+				Password:  "secret",
+				Transport: transport,
+				Ssh:       sshOpts,
+			})
+			require.NoError(t, err)
+			require.Equal(t, expected, dialer)
+		})
+	}
+}
+
+func TestNew_sshErrors(t *testing.T) {
+	_, err := New(Opts{Transport: "ssh"})
+	require.EqualError(t, err, "SSH host is not specified")
+
+	_, err = New(Opts{
+		SslCaFile: "ca.crt",
+		Ssh:       SshOpts{Host: "bastion"},
+	})
+	require.EqualError(t, err, "SSL connections through an SSH tunnel are not supported")
+}
+
+func TestParseAddress(t *testing.T) {
+	cases := []struct {
+		address string
+		network string
+		addr    string
+	}{
+		{"localhost:3301", "tcp", "localhost:3301"},
+		{"tcp://localhost:3301", "tcp", "localhost:3301"},
+		{"unix:///var/run/tt.sock", "unix", "/var/run/tt.sock"},
+		{"unix/:./tt.sock", "unix", "./tt.sock"},
+		{"/var/run/tt.sock", "unix", "/var/run/tt.sock"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.address, func(t *testing.T) {
+			network, addr := parseAddress(tc.address)
+			require.Equal(t, tc.network, network)
+			require.Equal(t, tc.addr, addr)
+		})
+	}
+}
+
+func TestSshForward(t *testing.T) {
+	server := startTestSshServer(t)
+	target := startTestGreeter(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	forward, err := NewSshForward(ctx, SshOpts{
+		Host:           server.addr,
+		User:           "tt",
+		KeyFile:        server.keyFile,
+		KnownHostsFile: server.knownHostsFile,
+	}, "tcp://"+target)
+	require.NoError(t, err)
+
+	// Every local connection is forwarded to the target.
+	for range 2 {
+		conn, err := net.Dial("tcp", forward.Addr())
+		require.NoError(t, err)
+		greeting := make([]byte, 128)
+		_, err = io.ReadFull(conn, greeting)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(greeting), testGreetingVersion))
+		conn.Close()
+	}
+
+	require.NoError(t, forward.Close())
+	_, err = net.Dial("tcp", forward.Addr())
+	require.Error(t, err)
+}
+
+func TestSshForward_errors(t *testing.T) {
+	_, err := NewSshForward(context.Background(), SshOpts{}, "localhost:3301")
+	require.EqualError(t, err, "SSH host is not specified")
+}
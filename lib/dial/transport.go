@@ -6,6 +6,7 @@ const (
 	transportDefault string = ""
 	transportPlain   string = "plain"
 	transportSsl     string = "ssl"
+	transportSsh     string = "ssh"
 )
 
 // Transport is a type, with a restriction on the list of supported connection modes.
@@ -18,10 +19,12 @@ const (
 	TransportPlain
 	// TransportSSL used for encrypted connection mode.
 	TransportSsl
+	// TransportSsh used for a plain connection through an SSH tunnel.
+	TransportSsh
 )
 
 // Parse returns Transport type according to the input string.
-// Under normal circumstances input should be "ssl" or "plain".
+// Under normal circumstances input should be "ssl", "plain" or "ssh".
 func Parse(tr string) (Transport, error) {
 	switch tr {
 	case transportDefault:
@@ -30,6 +33,8 @@ func Parse(tr string) (Transport, error) {
 		return TransportPlain, nil
 	case transportSsl:
 		return TransportSsl, nil
+	case transportSsh:
+		return TransportSsh, nil
 	default:
 		return TransportDefault, fmt.Errorf("unknown Transport type: %s", tr)
 	}
@@ -44,6 +49,8 @@ func (t Transport) String() string {
 		return transportPlain
 	case TransportSsl:
 		return transportSsl
+	case TransportSsh:
+		return transportSsh
 	default:
 		return fmt.Sprintf("Transport(%d)", t)
 	}
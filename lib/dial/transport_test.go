@@ -14,6 +14,7 @@ func TestTransportType_String(t *testing.T) {
 		{TransportDefault, ""},
 		{TransportPlain, "plain"},
 		{TransportSsl, "ssl"},
+		{TransportSsh, "ssh"},
 		{Transport(15), "Transport(15)"},
 	}
 
@@ -0,0 +1,81 @@
+package watchdog
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRestartLimit is returned when a process has been restarted too many
+// times within the restart policy window.
+var ErrRestartLimit = errors.New("restart limit is reached")
+
+// RestartPolicy describes how a failed process is restarted.
+type RestartPolicy struct {
+	// Delay is a delay before the first restart.
+	Delay time.Duration
+	// MaxDelay caps the delay. The delay is doubled after each restart until
+	// it reaches MaxDelay. The backoff is disabled if MaxDelay is not greater
+	// than Delay.
+	MaxDelay time.Duration
+	// MaxRestarts is a maximum number of restarts within Window. The process
+	// is not restarted anymore when the limit is reached. Zero means no limit.
+	MaxRestarts int
+	// Window is a period to count restarts in. Zero means that all restarts
+	// are counted.
+	Window time.Duration
+}
+
+// NewConstantRestartPolicy creates a restart policy with a constant delay
+// and without a restarts limit.
+func NewConstantRestartPolicy(delay time.Duration) RestartPolicy {
+	return RestartPolicy{Delay: delay}
+}
+
+// Backoff tracks restarts of a process and calculates delays between them
+// according to the restart policy.
+type Backoff struct {
+	// policy is the restart policy in use.
+	policy RestartPolicy
+	// delay is the next delay.
+	delay time.Duration
+	// restarts are moments of restarts within the window.
+	restarts []time.Time
+}
+
+// NewBackoff creates a new Backoff for the restart policy.
+func NewBackoff(policy RestartPolicy) *Backoff {
+	return &Backoff{
+		policy: policy,
+		delay:  policy.Delay,
+	}
+}
+
+// Next registers a restart of the process exited at the moment after
+// running for the uptime. It returns a delay before the restart or
+// ErrRestartLimit if the process should not be restarted. The delay is
+// reset if the process has been running longer than the maximum delay.
+func (b *Backoff) Next(now time.Time, uptime time.Duration) (time.Duration, error) {
+	if b.policy.MaxRestarts > 0 {
+		if b.policy.Window > 0 {
+			start := 0
+			for start < len(b.restarts) && now.Sub(b.restarts[start]) > b.policy.Window {
+				start++
+			}
+			b.restarts = b.restarts[start:]
+		}
+		if len(b.restarts) >= b.policy.MaxRestarts {
+			return 0, ErrRestartLimit
+		}
+		b.restarts = append(b.restarts, now)
+	}
+
+	if b.policy.MaxDelay <= b.policy.Delay {
+		return b.policy.Delay, nil
+	}
+	if uptime > b.policy.MaxDelay {
+		b.delay = b.policy.Delay
+	}
+	delay := b.delay
+	b.delay = min(2*b.delay, b.policy.MaxDelay)
+	return delay, nil
+}
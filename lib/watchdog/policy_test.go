@@ -0,0 +1,128 @@
+package watchdog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	type restart struct {
+		at     time.Duration
+		uptime time.Duration
+		delay  time.Duration
+		err    error
+	}
+
+	cases := []struct {
+		name     string
+		policy   RestartPolicy
+		restarts []restart
+	}{
+		{
+			name:   "constant",
+			policy: NewConstantRestartPolicy(time.Second),
+			restarts: []restart{
+				{at: 0, delay: time.Second},
+				{at: time.Second, delay: time.Second},
+				{at: 2 * time.Second, delay: time.Second},
+			},
+		},
+		{
+			name: "exponential with cap",
+			policy: RestartPolicy{
+				Delay:    time.Second,
+				MaxDelay: 5 * time.Second,
+			},
+			restarts: []restart{
+				{delay: time.Second},
+				{delay: 2 * time.Second},
+				{delay: 4 * time.Second},
+				{delay: 5 * time.Second},
+				{delay: 5 * time.Second},
+			},
+		},
+		{
+			name: "reset after a long run",
+			policy: RestartPolicy{
+				Delay:    time.Second,
+				MaxDelay: 5 * time.Second,
+			},
+			restarts: []restart{
+				{delay: time.Second},
+				{delay: 2 * time.Second},
+				{uptime: 5 * time.Second, delay: 4 * time.Second},
+				{uptime: 6 * time.Second, delay: time.Second},
+				{delay: 2 * time.Second},
+			},
+		},
+		{
+			name: "limit within window",
+			policy: RestartPolicy{
+				Delay:       time.Second,
+				MaxRestarts: 2,
+				Window:      10 * time.Second,
+			},
+			restarts: []restart{
+				{at: 0, delay: time.Second},
+				{at: 5 * time.Second, delay: time.Second},
+				{at: 9 * time.Second, err: ErrRestartLimit},
+				{at: 11 * time.Second, delay: time.Second},
+				{at: 12 * time.Second, err: ErrRestartLimit},
+			},
+		},
+		{
+			name: "limit without window",
+			policy: RestartPolicy{
+				Delay:       time.Second,
+				MaxRestarts: 1,
+			},
+			restarts: []restart{
+				{at: 0, delay: time.Second},
+				{at: time.Hour, err: ErrRestartLimit},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			backoff := NewBackoff(tc.policy)
+			for i, r := range tc.restarts {
+				delay, err := backoff.Next(start.Add(r.at), r.uptime)
+				if r.err != nil {
+					require.ErrorIs(t, err, r.err, "restart %d", i)
+					continue
+				}
+				require.NoError(t, err, "restart %d", i)
+				assert.Equal(t, r.delay, delay, "restart %d", i)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tt.state")
+
+	state, err := ReadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, State{}, state)
+
+	expected := State{
+		Restarts:       3,
+		LastExitReason: "exit status 1",
+		LastExitTime:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Failed:         true,
+	}
+	require.NoError(t, WriteState(path, expected))
+	state, err = ReadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,76 @@
+package watchdog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is a persisted state of process restarts.
+type State struct {
+	// Restarts is a number of restarts since the watchdog start.
+	Restarts int `json:"restarts"`
+	// LastExitReason describes the last exit of the process.
+	LastExitReason string `json:"last_exit_reason,omitempty"`
+	// LastExitTime is a moment of the last exit of the process.
+	LastExitTime time.Time `json:"last_exit_time,omitzero"`
+	// Failed is true if the watchdog gave up restarting the process.
+	Failed bool `json:"failed"`
+	// LastCoredump is a path to the archive with the core dump of the last
+	// crash.
+	LastCoredump string `json:"last_coredump,omitempty"`
+	// LastCoredumpError describes why the core dump of the last crash is
+	// not captured.
+	LastCoredumpError string `json:"last_coredump_error,omitempty"`
+}
+
+// ExitReason returns a description of the completed process state.
+func ExitReason(state *os.ProcessState) string {
+	if state == nil {
+		return "unknown"
+	}
+	return state.String()
+}
+
+// ReadState reads the state from the file. An empty state is returned if
+// the file does not exist.
+func ReadState(path string) (State, error) {
+	var state State
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse the watchdog state %q: %w", path, err)
+	}
+	return state, nil
+}
+
+// WriteState writes the state into the file. The file is replaced
+// atomically, so readers never see a partially written state.
+func WriteState(path string, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
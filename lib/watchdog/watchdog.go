@@ -21,8 +21,8 @@ import (
 type Watchdog struct {
 	// cmd is the child process command (protected by cmdMutex).
 	cmd *exec.Cmd
-	// restartTimeout defines delay before restart (0 = immediate).
-	restartTimeout time.Duration
+	// backoff defines delays before restarts according to the restart policy.
+	backoff *Backoff
 	// shouldStop is atomic flag to prevent restarts when true.
 	shouldStop atomic.Bool
 	// doneBarrier waits for goroutines during shutdown.
@@ -48,10 +48,16 @@ type Watchdog struct {
 // for signal notification and startup completion. Returns a pointer
 // to the created Watchdog.
 func NewWatchdog(pidFile, wdPidFile string, restartTimeout time.Duration) *Watchdog {
+	return NewWatchdogWithPolicy(pidFile, wdPidFile, NewConstantRestartPolicy(restartTimeout))
+}
+
+// NewWatchdogWithPolicy initializes a new Watchdog instance with the
+// specified PID file paths and restart policy.
+func NewWatchdogWithPolicy(pidFile, wdPidFile string, policy RestartPolicy) *Watchdog {
 	return &Watchdog{
 		pidFile:         pidFile,
 		wdPidFile:       wdPidFile,
-		restartTimeout:  restartTimeout,
+		backoff:         NewBackoff(policy),
 		signalChan:      make(chan os.Signal, 1),
 		startupComplete: make(chan struct{}),
 	}
@@ -119,6 +125,7 @@ func (wd *Watchdog) Start(bin string, args ...string) error {
 		}
 
 		log.Infof("Process started successfully")
+		startTime := time.Now()
 		close(wd.startupComplete) // Signal that startup is complete.
 
 		// Wait for process completion in separate goroutine.
@@ -156,9 +163,14 @@ func (wd *Watchdog) Start(bin string, args ...string) error {
 		}
 
 		// Wait before restarting
-		log.Infof("Waiting %s before restart...", wd.restartTimeout)
+		restartTimeout, err := wd.backoff.Next(time.Now(), time.Since(startTime))
+		if err != nil {
+			log.Errorf("Process is not restarted: %v", err)
+			return err
+		}
+		log.Infof("Waiting %s before restart...", restartTimeout)
 		select {
-		case <-time.After(wd.restartTimeout):
+		case <-time.After(restartTimeout):
 			// Continue to next iteration after timeout.
 		case <-ctx.Done():
 			// Exit if context canceled during wait.
@@ -756,6 +756,9 @@ def test_cluster_publish_config_no_auth(
             instance.disable_auth()
 
     assert expected_err_msg in publish_output
+    # Pruning of the publish history is best-effort and does not hide
+    # the publish error.
+    assert "config.storage.get" not in publish_output
 
 
 @pytest.mark.parametrize("instance_name", ["etcd", "tcs"])